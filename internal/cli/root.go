@@ -16,9 +16,10 @@ import (
 )
 
 var (
-	port   int
-	branch string
-	token  string
+	port    int
+	branch  string
+	token   string
+	noClone bool
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -34,7 +35,8 @@ You can browse local repositories or clone remote ones.`,
 func init() {
 	rootCmd.Flags().IntVarP(&port, "port", "p", 4242, "Port to run the server on")
 	rootCmd.Flags().StringVarP(&branch, "branch", "b", "", "Branch to browse (defaults to HEAD)")
-	rootCmd.Flags().StringVarP(&token, "token", "t", "", "Personal access token for authentication (GitHub or GitLab)")
+	rootCmd.Flags().StringVarP(&token, "token", "t", "", "Personal access token for the repository's host")
+	rootCmd.Flags().BoolVar(&noClone, "no-clone", false, "Browse a remote repository read-only through its host's API instead of cloning it")
 	rootCmd.AddCommand(versionCmd)
 }
 
@@ -60,6 +62,15 @@ func run(cmd *cobra.Command, args []string) error {
 	// Detect if it's a URL or local path
 	isURL := isRemoteURL(pathOrURL)
 
+	// Browse a remote repository without cloning it
+	if isURL && noClone {
+		provider, err := openAPIProvider(pathOrURL, cfg)
+		if err != nil {
+			return err
+		}
+		return serve(provider, cfg)
+	}
+
 	var absPath string
 
 	if isURL {
@@ -87,6 +98,34 @@ func run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Use the configured commit author, if any
+	provider.SetDefaultAuthor(cfg.Commit.Name, cfg.Commit.Email)
+
+	// Sign commits if a signing key is configured
+	if cfg.Commit.SigningKey != "" {
+		keyPath, err := expandHome(cfg.Commit.SigningKey)
+		if err != nil {
+			return err
+		}
+		if err := provider.SetSigningKey(keyPath, cfg.Commit.SigningFormat, os.Getenv(config.SigningPassphraseEnv)); err != nil {
+			return fmt.Errorf("failed to load signing key: %w", err)
+		}
+	}
+
+	// Authenticate with origin using the token for its host, or the SSH settings
+	if originURL, err := provider.RemoteURL("origin"); err == nil {
+		creds, err := remoteCredentials(originURL, resolveAuthToken(originURL, cfg), cfg)
+		if err != nil {
+			return err
+		}
+		provider.SetCredentials(creds)
+	}
+
+	return serve(provider, cfg)
+}
+
+// serve starts the server for a provider and opens it in the browser
+func serve(provider git.GitProvider, cfg *config.Config) error {
 	// Check if port is available before starting the server
 	if err := checkPortAvailable(port); err != nil {
 		return err
@@ -94,6 +133,7 @@ func run(cmd *cobra.Command, args []string) error {
 
 	// Create and start the server
 	srv := server.New(port, provider)
+	srv.SetUploadLimit(cfg.Upload.MaxSize)
 
 	// Start server in a goroutine so we can open the browser
 	errChan := make(chan error, 1)
@@ -117,6 +157,12 @@ func run(cmd *cobra.Command, args []string) error {
 func isRemoteURL(s string) bool {
 	return strings.HasPrefix(s, "http://") ||
 		strings.HasPrefix(s, "https://") ||
+		isSSHRemoteURL(s)
+}
+
+// isSSHRemoteURL detects if the given string is a remote repository URL using SSH
+func isSSHRemoteURL(s string) bool {
+	return strings.HasPrefix(s, "ssh://") ||
 		strings.HasPrefix(s, "git@")
 }
 
@@ -145,6 +191,20 @@ func resolveLocalPath(path string) (string, error) {
 	return absPath, nil
 }
 
+// expandHome replaces a leading "~/" in a path with the user's home directory
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not get home directory: %w", err)
+	}
+
+	return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+}
+
 // checkPortAvailable checks if the given port is available
 func checkPortAvailable(port int) error {
 	addr := fmt.Sprintf(":%d", port)
@@ -158,16 +218,15 @@ func checkPortAvailable(port int) error {
 
 // handleRemoteURL handles cloning a remote repository
 func handleRemoteURL(url string, cfg *config.Config) (string, error) {
-	// Resolve authentication token based on the repository host
-	// For now, we'll use GitHub token for github.com and GitLab token for gitlab.com
-	// This is a simple heuristic - a more robust solution would detect the host
-	var authToken string
-	if strings.Contains(url, "github.com") {
-		resolved := cfg.ResolveGitHubToken(token)
-		authToken = resolved.Value
-	} else if strings.Contains(url, "gitlab.com") {
-		resolved := cfg.ResolveGitLabToken(token)
-		authToken = resolved.Value
+	// Resolve authentication token based on the repository host; SSH URLs
+	// authenticate with a key instead
+	auth := resolveAuthToken(url, cfg)
+	if !isSSHRemoteURL(url) {
+		reportAuthToken(auth)
+	}
+	creds, err := remoteCredentials(url, auth, cfg)
+	if err != nil {
+		return "", err
 	}
 
 	// Check where the repository would be cloned and if it already exists
@@ -186,7 +245,7 @@ func handleRemoteURL(url string, cfg *config.Config) (string, error) {
 
 		// User wants to pull
 		fmt.Fprintf(os.Stderr, "Pulling latest changes...\n")
-		if err := git.PullExistingWithAuth(path, authToken); err != nil {
+		if err := git.PullExistingWithCredentials(path, creds); err != nil {
 			// Pull failed, but we can still serve the repo
 			fmt.Fprintf(os.Stderr, "Warning: failed to pull: %v\n", err)
 			fmt.Fprintf(os.Stderr, "Continuing with existing repository...\n")
@@ -203,7 +262,7 @@ func handleRemoteURL(url string, cfg *config.Config) (string, error) {
 
 	// User confirmed - perform the clone
 	fmt.Fprintf(os.Stderr, "Cloning repository to %s...\n", path)
-	if err := git.CloneRemoteWithAuth(url, path, authToken); err != nil {
+	if err := git.CloneRemoteWithCredentials(url, path, creds); err != nil {
 		return "", fmt.Errorf("clone failed: %w", err)
 	}
 
@@ -211,6 +270,101 @@ func handleRemoteURL(url string, cfg *config.Config) (string, error) {
 	return path, nil
 }
 
+// openAPIProvider opens a remote repository through its host's API, without
+// cloning it. The host type comes from the host's [[hosts]] config entry;
+// github.com and gitlab.com are recognized without one.
+func openAPIProvider(url string, cfg *config.Config) (git.GitProvider, error) {
+	host, repoPath, err := git.ParseRemoteURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process remote URL: %w", err)
+	}
+
+	var kind, apiURL string
+	if entry := cfg.LookupHost(host); entry != nil {
+		kind, apiURL = entry.Type, entry.APIURL
+	} else if host == "github.com" {
+		kind = git.HostGitHub
+	} else if host == "gitlab.com" {
+		kind = git.HostGitLab
+	} else {
+		return nil, fmt.Errorf("unknown host %s: add a [[hosts]] entry for it to the config file", host)
+	}
+
+	if apiURL == "" {
+		apiURL, err = git.DefaultAPIURL(kind, host)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The owner may be a group path, e.g. GitLab's group/subgroup
+	i := strings.LastIndex(repoPath, "/")
+	fmt.Fprintf(os.Stderr, "Browsing %s read-only through the %s API...\n", repoPath, kind)
+	auth := resolveAuthToken(url, cfg)
+	reportAuthToken(auth)
+	provider, err := git.NewAPIProvider(kind, apiURL, repoPath[:i], repoPath[i+1:], branch, auth.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cache trees and files to stay within the API's rate limit
+	return git.NewCachingProvider(provider, 0), nil
+}
+
+// resolveAuthToken resolves the credentials to use for a remote URL from its
+// host, with the usual precedence: the --token flag, then the config file, then
+// the environment (see config.ResolveCredential). URLs without a recognizable
+// host get no token. A failing credential helper is reported as a warning.
+func resolveAuthToken(url string, cfg *config.Config) config.ResolvedToken {
+	host, _, err := git.ParseRemoteURL(url)
+	if err != nil {
+		return config.ResolvedToken{Source: config.TokenSourceNone}
+	}
+
+	resolved, err := cfg.ResolveCredential(host, token)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	return resolved
+}
+
+// remoteCredentials combines the token resolved for a remote URL with the [ssh]
+// settings into the credentials for cloning, pulling, and pushing. The token is
+// only sent to the URL's host. The passphrase of an encrypted SSH key comes from
+// the environment.
+func remoteCredentials(url string, auth config.ResolvedToken, cfg *config.Config) (git.Credentials, error) {
+	keyFile, err := expandHome(cfg.SSH.KeyFile)
+	if err != nil {
+		return git.Credentials{}, err
+	}
+	knownHosts, err := expandHome(cfg.SSH.KnownHosts)
+	if err != nil {
+		return git.Credentials{}, err
+	}
+
+	// URLs without a recognizable host get no token from resolveAuthToken
+	host, _, _ := git.ParseRemoteURL(url)
+
+	return git.Credentials{
+		Username: auth.Username,
+		Token:    auth.Value,
+		Host:     host,
+		SSH: git.SSHOptions{
+			KeyFile:       keyFile,
+			Passphrase:    os.Getenv(config.SSHPassphraseEnv),
+			KnownHosts:    knownHosts,
+			HostKeyPolicy: cfg.SSH.HostKeyPolicy,
+		},
+	}, nil
+}
+
+// reportAuthToken tells the user where the token for a remote came from.
+func reportAuthToken(auth config.ResolvedToken) {
+	if auth.Source != config.TokenSourceNone {
+		fmt.Fprintf(os.Stderr, "Authenticating with token from %s\n", auth.Source)
+	}
+}
+
 // promptYesNo prompts the user for a yes/no response
 // defaultYes determines whether Enter defaults to yes (true) or no (false)
 func promptYesNo(prompt string, defaultYes bool) bool {
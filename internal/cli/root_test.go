@@ -3,7 +3,11 @@ package cli
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/buckleypaul/giki/internal/config"
+	"github.com/buckleypaul/giki/internal/git"
 )
 
 func TestIsRemoteURL(t *testing.T) {
@@ -15,6 +19,7 @@ func TestIsRemoteURL(t *testing.T) {
 		{"HTTP URL", "http://github.com/org/repo", true},
 		{"HTTPS URL", "https://github.com/org/repo", true},
 		{"Git SSH URL", "git@github.com:org/repo.git", true},
+		{"SSH scheme URL", "ssh://git@github.com/org/repo.git", true},
 		{"Local path", "/tmp/path", false},
 		{"Relative path", "./path", false},
 		{"Dot", ".", false},
@@ -80,6 +85,31 @@ func TestResolveLocalPath(t *testing.T) {
 	})
 }
 
+func TestExpandHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"~/.ssh/id_ed25519", filepath.Join(home, ".ssh", "id_ed25519")},
+		{"~", home},
+		{"/etc/key.asc", "/etc/key.asc"},
+		{"keys/~/key.asc", "keys/~/key.asc"},
+	}
+
+	for _, tt := range tests {
+		result, err := expandHome(tt.path)
+		if err != nil {
+			t.Fatalf("expandHome(%q) returned error: %v", tt.path, err)
+		}
+		if result != tt.expected {
+			t.Errorf("expandHome(%q) = %q, want %q", tt.path, result, tt.expected)
+		}
+	}
+}
+
 func TestCheckPortAvailable(t *testing.T) {
 	t.Run("Available port succeeds", func(t *testing.T) {
 		// Use a high port number that's likely to be available
@@ -176,3 +206,120 @@ func TestRootCmdArgs(t *testing.T) {
 		}
 	})
 }
+
+func TestResolveAuthToken(t *testing.T) {
+	cfg := &config.Config{
+		GitHubToken: "github-token",
+		GitLabToken: "gitlab-token",
+	}
+
+	tests := []struct {
+		name     string
+		url      string
+		expected string
+	}{
+		{"GitHub HTTPS", "https://github.com/org/repo.git", "github-token"},
+		{"GitHub SSH", "git@github.com:org/repo.git", "github-token"},
+		{"GitLab HTTPS", "https://gitlab.com/org/repo", "gitlab-token"},
+		{"Other host", "https://example.com/org/repo.git", ""},
+		{"Local path", "/srv/git/repo.git", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := resolveAuthToken(tt.url, cfg).Value
+			if result != tt.expected {
+				t.Errorf("resolveAuthToken(%q) = %q, want %q", tt.url, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResolveAuthToken_Credentials(t *testing.T) {
+	cfg := &config.Config{
+		GitHubToken: "github-token",
+		GitLabToken: "gitlab-token",
+		Credentials: []config.CredentialConfig{
+			{Host: "git.example.com", Token: "self-hosted-token"},
+			{Host: "bitbucket.org", Username: "alice", Token: "app-password"},
+			{Host: "gitlab.com", Token: "credentials-token"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		url          string
+		wantToken    string
+		wantUsername string
+		wantSource   config.TokenSource
+	}{
+		{"Self-hosted HTTPS", "https://git.example.com/group/sub/repo.git", "self-hosted-token", "", config.TokenSourceConfig},
+		{"Self-hosted SSH", "git@git.example.com:group/repo.git", "self-hosted-token", "", config.TokenSourceConfig},
+		{"Username for Bitbucket", "https://bitbucket.org/team/repo.git", "app-password", "alice", config.TokenSourceConfig},
+		{"Credentials entry over legacy token", "https://gitlab.com/org/repo", "credentials-token", "", config.TokenSourceConfig},
+		{"Legacy GitHub token", "https://github.com/org/repo.git", "github-token", "", config.TokenSourceConfig},
+		{"Other host", "https://example.com/org/repo.git", "", "", config.TokenSourceNone},
+		{"Local path", "/srv/git/repo.git", "", "", config.TokenSourceNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := resolveAuthToken(tt.url, cfg)
+			if result.Value != tt.wantToken || result.Username != tt.wantUsername || result.Source != tt.wantSource {
+				t.Errorf("resolveAuthToken(%q) = %+v, want (%q, %q, %s)", tt.url, result, tt.wantToken, tt.wantUsername, tt.wantSource)
+			}
+		})
+	}
+
+	// The --token flag still takes precedence, for any host
+	origToken := token
+	defer func() { token = origToken }()
+	token = "cli-token"
+	for _, url := range []string{"https://git.example.com/group/repo", "https://example.com/org/repo.git"} {
+		if result := resolveAuthToken(url, cfg); result.Value != "cli-token" || result.Source != config.TokenSourceCLI {
+			t.Errorf("expected CLI token to take precedence for %s, got %+v", url, result)
+		}
+	}
+}
+
+func TestRemoteCredentials(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(config.SSHPassphraseEnv, "key-passphrase")
+
+	cfg := &config.Config{
+		SSH: config.SSHConfig{
+			KeyFile:       "~/.ssh/giki_ed25519",
+			KnownHosts:    "/etc/giki/known_hosts",
+			HostKeyPolicy: "accept-new",
+		},
+	}
+	auth := config.ResolvedToken{Value: "app-password", Username: "alice", Source: config.TokenSourceConfig}
+
+	creds, err := remoteCredentials("https://git.example.com/team/wiki.git", auth, cfg)
+	if err != nil {
+		t.Fatalf("remoteCredentials failed: %v", err)
+	}
+
+	expected := git.Credentials{
+		Username: "alice",
+		Token:    "app-password",
+		Host:     "git.example.com",
+		SSH: git.SSHOptions{
+			KeyFile:       filepath.Join(home, ".ssh", "giki_ed25519"),
+			Passphrase:    "key-passphrase",
+			KnownHosts:    "/etc/giki/known_hosts",
+			HostKeyPolicy: "accept-new",
+		},
+	}
+	if creds != expected {
+		t.Errorf("remoteCredentials() = %+v, want %+v", creds, expected)
+	}
+}
+
+func TestOpenAPIProvider_UnknownHost(t *testing.T) {
+	_, err := openAPIProvider("https://git.example.com/group/repo", &config.Config{})
+	if err == nil || !strings.Contains(err.Error(), "[[hosts]]") {
+		t.Errorf("expected unknown host error mentioning [[hosts]], got %v", err)
+	}
+}
@@ -3,15 +3,92 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/pelletier/go-toml/v2"
 )
 
 // Config holds all application configuration
 type Config struct {
-	// Tokens for authentication
+	// Credentials for git hosts, by hostname
+	Credentials []CredentialConfig `toml:"credentials"`
+
+	// Tokens for github.com and gitlab.com.
+	// Deprecated: use [[credentials]] entries for those hosts instead.
 	GitHubToken string `toml:"github_token"`
 	GitLabToken string `toml:"gitlab_token"`
+
+	// Commit settings
+	Commit CommitConfig `toml:"commit"`
+
+	// Upload settings
+	Upload UploadConfig `toml:"upload"`
+
+	// SSH settings, for repositories cloned over SSH
+	SSH SSHConfig `toml:"ssh"`
+
+	// Per-host settings, for self-hosted instances and hosts other than
+	// github.com and gitlab.com
+	Hosts []HostConfig `toml:"hosts"`
+}
+
+// CommitConfig holds the [commit] section of the config file
+type CommitConfig struct {
+	// Author identity for commits made from giki.
+	// Falls back to user.name/user.email from gitconfig when empty.
+	Name  string `toml:"name"`
+	Email string `toml:"email"`
+
+	// Private key used to sign commits made from giki; unsigned when empty.
+	// SigningFormat is "openpgp" or "ssh", detected from the key file when empty.
+	// The passphrase of an encrypted key is read from GIKI_SIGNING_PASSPHRASE.
+	SigningKey    string `toml:"signing_key"`
+	SigningFormat string `toml:"signing_format"`
+}
+
+// SigningPassphraseEnv is the environment variable holding the passphrase of
+// an encrypted commit signing key
+const SigningPassphraseEnv = "GIKI_SIGNING_PASSPHRASE"
+
+// UploadConfig holds the [upload] section of the config file
+type UploadConfig struct {
+	// Largest file accepted by /api/upload, in bytes; the server default when zero.
+	MaxSize int64 `toml:"max_size"`
+}
+
+// SSHPassphraseEnv is the environment variable holding the passphrase of an
+// encrypted SSH key
+const SSHPassphraseEnv = "GIKI_SSH_PASSPHRASE"
+
+// SSHConfig holds the [ssh] section of the config file
+type SSHConfig struct {
+	// Private key for SSH remotes. ssh-agent, or else the default keys in
+	// ~/.ssh, are used when empty. The passphrase of an encrypted key is
+	// read from GIKI_SSH_PASSPHRASE.
+	KeyFile string `toml:"key_file"`
+
+	// known_hosts file for verifying host keys; ~/.ssh/known_hosts when empty.
+	// HostKeyPolicy is "strict" (the default) to only connect to known hosts,
+	// "accept-new" to add hosts that are not known yet, or "insecure" to skip
+	// verification.
+	KnownHosts    string `toml:"known_hosts"`
+	HostKeyPolicy string `toml:"host_key_policy"`
+}
+
+// HostConfig holds one [[hosts]] entry of the config file
+type HostConfig struct {
+	// Hostname as it appears in repository URLs, e.g. "git.example.com"
+	Host string `toml:"host"`
+
+	// Hosting service: "github", "gitlab", or "gitea".
+	// APIURL is only needed when the API is not at the service's usual path on the host.
+	// Tokens for the host go in its [[credentials]] entry.
+	Type   string `toml:"type"`
+	APIURL string `toml:"api_url"`
+
+	// Deprecated: use a [[credentials]] entry for the host. Still read when
+	// the host's [[credentials]] entry has no token.
+	Token string `toml:"token"`
 }
 
 // TokenSource describes where a token came from
@@ -21,13 +98,16 @@ const (
 	TokenSourceCLI    TokenSource = "cli"
 	TokenSourceConfig TokenSource = "config"
 	TokenSourceEnv    TokenSource = "env"
+	TokenSourceHelper TokenSource = "credential_helper"
 	TokenSourceNone   TokenSource = "none"
 )
 
-// ResolvedToken contains a token and its source
+// ResolvedToken contains a token and its source, and the username to send
+// with it if one is configured
 type ResolvedToken struct {
-	Value  string
-	Source TokenSource
+	Value    string
+	Username string
+	Source   TokenSource
 }
 
 // Load reads configuration from the default config file location
@@ -65,6 +145,8 @@ func LoadFrom(path string) (*Config, error) {
 
 // ResolveGitHubToken determines which GitHub token to use based on precedence:
 // CLI flag > config file > env var
+//
+// Deprecated: use ResolveCredential, which also reads [[credentials]] entries.
 func (c *Config) ResolveGitHubToken(cliToken string) ResolvedToken {
 	// CLI flag takes highest precedence
 	if cliToken != "" {
@@ -86,6 +168,8 @@ func (c *Config) ResolveGitHubToken(cliToken string) ResolvedToken {
 
 // ResolveGitLabToken determines which GitLab token to use based on precedence:
 // CLI flag > config file > env var
+//
+// Deprecated: use ResolveCredential, which also reads [[credentials]] entries.
 func (c *Config) ResolveGitLabToken(cliToken string) ResolvedToken {
 	// CLI flag takes highest precedence
 	if cliToken != "" {
@@ -105,6 +189,17 @@ func (c *Config) ResolveGitLabToken(cliToken string) ResolvedToken {
 	return ResolvedToken{Value: "", Source: TokenSourceNone}
 }
 
+// LookupHost returns the [[hosts]] entry for a hostname, or nil if there is none.
+// Hostnames are compared case-insensitively.
+func (c *Config) LookupHost(host string) *HostConfig {
+	for i := range c.Hosts {
+		if strings.EqualFold(c.Hosts[i].Host, host) {
+			return &c.Hosts[i]
+		}
+	}
+	return nil
+}
+
 // defaultConfigPath returns the default config file path
 func defaultConfigPath() (string, error) {
 	home, err := os.UserHomeDir()
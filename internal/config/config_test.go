@@ -219,3 +219,135 @@ func TestLoad_UsesDefaultPath(t *testing.T) {
 		t.Error("Load should return non-nil config")
 	}
 }
+
+func TestLoadFrom_CommitSection(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.toml")
+
+	content := `
+github_token = "gh_test_token_123"
+
+[commit]
+name = "Ada Lovelace"
+email = "ada@example.com"
+signing_key = "~/.ssh/id_ed25519"
+signing_format = "ssh"
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+
+	cfg, err := LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+
+	if cfg.Commit.Name != "Ada Lovelace" {
+		t.Errorf("Expected commit name 'Ada Lovelace', got '%s'", cfg.Commit.Name)
+	}
+	if cfg.Commit.Email != "ada@example.com" {
+		t.Errorf("Expected commit email 'ada@example.com', got '%s'", cfg.Commit.Email)
+	}
+	if cfg.Commit.SigningKey != "~/.ssh/id_ed25519" || cfg.Commit.SigningFormat != "ssh" {
+		t.Errorf("Expected ssh signing key, got '%s' (%s)", cfg.Commit.SigningKey, cfg.Commit.SigningFormat)
+	}
+	if cfg.GitHubToken != "gh_test_token_123" {
+		t.Errorf("Expected GitHubToken to still load, got '%s'", cfg.GitHubToken)
+	}
+}
+
+func TestLoadFrom_UploadSection(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.toml")
+
+	content := `
+[upload]
+max_size = 52428800
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+
+	cfg, err := LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+
+	if cfg.Upload.MaxSize != 50<<20 {
+		t.Errorf("Expected upload max size %d, got %d", 50<<20, cfg.Upload.MaxSize)
+	}
+}
+
+func TestLoadFrom_SSHSection(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.toml")
+
+	content := `
+[ssh]
+key_file = "~/.ssh/giki_ed25519"
+known_hosts = "~/.ssh/giki_known_hosts"
+host_key_policy = "accept-new"
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+
+	cfg, err := LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+
+	expected := SSHConfig{
+		KeyFile:       "~/.ssh/giki_ed25519",
+		KnownHosts:    "~/.ssh/giki_known_hosts",
+		HostKeyPolicy: "accept-new",
+	}
+	if cfg.SSH != expected {
+		t.Errorf("Expected SSH config %+v, got %+v", expected, cfg.SSH)
+	}
+}
+
+func TestLoadFrom_HostsSection(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.toml")
+
+	content := `
+[[hosts]]
+host = "git.example.com"
+type = "gitlab"
+
+[[hosts]]
+host = "code.example.org"
+type = "gitea"
+api_url = "https://code.example.org/gitea/api/v1"
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+
+	cfg, err := LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+
+	if len(cfg.Hosts) != 2 {
+		t.Fatalf("Expected 2 hosts, got %d", len(cfg.Hosts))
+	}
+
+	host := cfg.LookupHost("GIT.example.com")
+	if host == nil {
+		t.Fatal("Expected git.example.com to be found case-insensitively")
+	}
+	if host.Type != "gitlab" || host.APIURL != "" {
+		t.Errorf("Unexpected host entry %+v", host)
+	}
+
+	host = cfg.LookupHost("code.example.org")
+	if host == nil || host.APIURL != "https://code.example.org/gitea/api/v1" {
+		t.Errorf("Unexpected host entry %+v", host)
+	}
+
+	if cfg.LookupHost("github.com") != nil {
+		t.Error("Expected no entry for an unconfigured host")
+	}
+}
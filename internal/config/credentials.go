@@ -0,0 +1,175 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// credentialHelperTimeout bounds how long a credential helper may run, so one
+// that hangs or waits for input cannot block startup.
+var credentialHelperTimeout = 30 * time.Second
+
+// CredentialConfig holds one [[credentials]] entry of the config file
+type CredentialConfig struct {
+	// Hostname as it appears in repository URLs, e.g. "github.example.com"
+	Host string `toml:"host"`
+
+	// Username sent with the token; the token alone is sent when empty,
+	// which GitHub and GitLab accept for personal access tokens
+	Username string `toml:"username"`
+
+	// Token for the host, or a command that prints one. The command is run by
+	// the shell with a git credential request on stdin, so it can be a git
+	// credential helper ("git credential-osxkeychain get") or any command
+	// that prints a token ("gh auth token").
+	Token            string `toml:"token"`
+	CredentialHelper string `toml:"credential_helper"`
+}
+
+// LookupCredential returns the [[credentials]] entry for a hostname, or nil if
+// there is none. Hostnames are compared case-insensitively.
+func (c *Config) LookupCredential(host string) *CredentialConfig {
+	for i := range c.Credentials {
+		if strings.EqualFold(c.Credentials[i].Host, host) {
+			return &c.Credentials[i]
+		}
+	}
+	return nil
+}
+
+// ResolveCredential determines the token (and username, if any) to use for a
+// host based on precedence: CLI flag > config file > env var.
+// In the config file, the host's [[credentials]] entry comes first, then the
+// deprecated token of its [[hosts]] entry, then the legacy github_token and
+// gitlab_token for github.com and gitlab.com. In the environment,
+// GIKI_TOKEN_<HOST> comes first (e.g. GIKI_TOKEN_GIT_EXAMPLE_COM), then the
+// legacy GIKI_GITHUB_TOKEN and GIKI_GITLAB_TOKEN.
+// If the host's credential helper fails, the remaining sources are still
+// tried, and the helper's error is returned along with what they resolve.
+func (c *Config) ResolveCredential(host, cliToken string) (ResolvedToken, error) {
+	host = strings.ToLower(host)
+	entry := c.LookupCredential(host)
+
+	username := ""
+	if entry != nil {
+		username = entry.Username
+	}
+
+	// CLI flag takes highest precedence
+	if cliToken != "" {
+		return ResolvedToken{Value: cliToken, Username: username, Source: TokenSourceCLI}, nil
+	}
+
+	// Config file is next
+	var helperErr error
+	if entry != nil {
+		if entry.Token != "" {
+			return ResolvedToken{Value: entry.Token, Username: username, Source: TokenSourceConfig}, nil
+		}
+		if entry.CredentialHelper != "" {
+			helperUsername, token, err := runCredentialHelper(entry.CredentialHelper, host)
+			helperErr = err
+			if username == "" {
+				username = helperUsername
+			}
+			if token != "" {
+				return ResolvedToken{Value: token, Username: username, Source: TokenSourceHelper}, nil
+			}
+		}
+	}
+	if hostEntry := c.LookupHost(host); hostEntry != nil && hostEntry.Token != "" {
+		return ResolvedToken{Value: hostEntry.Token, Username: username, Source: TokenSourceConfig}, helperErr
+	}
+	switch {
+	case host == "github.com" && c.GitHubToken != "":
+		return ResolvedToken{Value: c.GitHubToken, Username: username, Source: TokenSourceConfig}, helperErr
+	case host == "gitlab.com" && c.GitLabToken != "":
+		return ResolvedToken{Value: c.GitLabToken, Username: username, Source: TokenSourceConfig}, helperErr
+	}
+
+	// Environment variable is last
+	envVars := []string{EnvTokenVar(host)}
+	switch host {
+	case "github.com":
+		envVars = append(envVars, "GIKI_GITHUB_TOKEN")
+	case "gitlab.com":
+		envVars = append(envVars, "GIKI_GITLAB_TOKEN")
+	}
+	for _, envVar := range envVars {
+		if envToken := os.Getenv(envVar); envToken != "" {
+			return ResolvedToken{Value: envToken, Username: username, Source: TokenSourceEnv}, helperErr
+		}
+	}
+
+	return ResolvedToken{Value: "", Username: username, Source: TokenSourceNone}, helperErr
+}
+
+// EnvTokenVar returns the environment variable holding the token for a host:
+// GIKI_TOKEN_ followed by the hostname in upper case, with every character
+// other than a letter or digit replaced by an underscore.
+func EnvTokenVar(host string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, host)
+	return "GIKI_TOKEN_" + name
+}
+
+// runCredentialHelper runs a credential helper command for a host and returns
+// the username and token it reports. Output in git's credential format is
+// read for its username and password; any other output is taken as the token.
+func runCredentialHelper(command, host string) (username, token string, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), credentialHelperTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Stdin = strings.NewReader("protocol=https\nhost=" + host + "\n\n")
+	// Don't wait for children of the shell that keep its output open
+	cmd.WaitDelay = time.Second
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "", "", fmt.Errorf("credential helper for %s timed out after %s", host, credentialHelperTimeout)
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", "", fmt.Errorf("credential helper for %s failed: %w: %s", host, err, msg)
+		}
+		return "", "", fmt.Errorf("credential helper for %s failed: %w", host, err)
+	}
+
+	output := strings.TrimSpace(string(out))
+	if !strings.Contains(output, "password=") {
+		return "", output, nil
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		key, value, _ := strings.Cut(strings.TrimSpace(line), "=")
+		switch key {
+		case "username":
+			username = value
+		case "password":
+			token = value
+		}
+	}
+	return username, token, nil
+}
@@ -0,0 +1,202 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadFrom_CredentialsSection(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.toml")
+
+	content := `
+[[credentials]]
+host = "bitbucket.org"
+username = "alice"
+token = "app-password"
+
+[[credentials]]
+host = "github.example.com"
+credential_helper = "git credential-osxkeychain get"
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+
+	cfg, err := LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+
+	if len(cfg.Credentials) != 2 {
+		t.Fatalf("Expected 2 credentials, got %d", len(cfg.Credentials))
+	}
+
+	cred := cfg.LookupCredential("BitBucket.org")
+	if cred == nil || cred.Username != "alice" || cred.Token != "app-password" {
+		t.Errorf("Unexpected credential entry %+v", cred)
+	}
+	cred = cfg.LookupCredential("github.example.com")
+	if cred == nil || cred.CredentialHelper != "git credential-osxkeychain get" {
+		t.Errorf("Unexpected credential entry %+v", cred)
+	}
+	if cfg.LookupCredential("gitlab.com") != nil {
+		t.Error("Expected no entry for an unconfigured host")
+	}
+}
+
+func TestResolveCredential_Precedence(t *testing.T) {
+	cfg := &Config{
+		Credentials: []CredentialConfig{
+			{Host: "bitbucket.org", Username: "alice", Token: "bitbucket-token"},
+			{Host: "github.example.com", Token: "ghe-token"},
+			{Host: "gitlab.com", Username: "bob"},
+		},
+		Hosts: []HostConfig{
+			{Host: "git.example.com", Type: "gitea", Token: "deprecated-host-token"},
+			{Host: "github.example.com", Type: "github", Token: "unused-host-token"},
+		},
+		GitHubToken: "legacy-github",
+		GitLabToken: "legacy-gitlab",
+	}
+
+	os.Setenv("GIKI_TOKEN_GITHUB_EXAMPLE_COM", "ghe-env")
+	os.Setenv("GIKI_TOKEN_GITEA_EXAMPLE_COM", "gitea-env")
+	os.Setenv("GIKI_GITHUB_TOKEN", "legacy-github-env")
+	defer os.Unsetenv("GIKI_TOKEN_GITHUB_EXAMPLE_COM")
+	defer os.Unsetenv("GIKI_TOKEN_GITEA_EXAMPLE_COM")
+	defer os.Unsetenv("GIKI_GITHUB_TOKEN")
+
+	tests := []struct {
+		name         string
+		host         string
+		cliToken     string
+		wantToken    string
+		wantUsername string
+		wantSource   TokenSource
+	}{
+		{"CLI flag wins", "github.example.com", "cli-token", "cli-token", "", TokenSourceCLI},
+		{"CLI flag keeps configured username", "bitbucket.org", "cli-token", "cli-token", "alice", TokenSourceCLI},
+		{"credentials entry over env", "github.example.com", "", "ghe-token", "", TokenSourceConfig},
+		{"credentials entry with username", "BITBUCKET.ORG", "", "bitbucket-token", "alice", TokenSourceConfig},
+		{"deprecated hosts token", "git.example.com", "", "deprecated-host-token", "", TokenSourceConfig},
+		{"legacy config token", "github.com", "", "legacy-github", "", TokenSourceConfig},
+		{"legacy config token with entry username", "gitlab.com", "", "legacy-gitlab", "bob", TokenSourceConfig},
+		{"host env var", "gitea.example.com", "", "gitea-env", "", TokenSourceEnv},
+		{"no token", "example.com", "", "", "", TokenSourceNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, err := cfg.ResolveCredential(tt.host, tt.cliToken)
+			if err != nil {
+				t.Fatalf("ResolveCredential failed: %v", err)
+			}
+			if resolved.Value != tt.wantToken || resolved.Username != tt.wantUsername || resolved.Source != tt.wantSource {
+				t.Errorf("Expected (%q, %q, %s), got (%q, %q, %s)",
+					tt.wantToken, tt.wantUsername, tt.wantSource, resolved.Value, resolved.Username, resolved.Source)
+			}
+		})
+	}
+
+	// The legacy env var still applies to github.com when nothing else is set
+	resolved, err := (&Config{}).ResolveCredential("github.com", "")
+	if err != nil {
+		t.Fatalf("ResolveCredential failed: %v", err)
+	}
+	if resolved.Value != "legacy-github-env" || resolved.Source != TokenSourceEnv {
+		t.Errorf("Expected legacy env token, got %+v", resolved)
+	}
+}
+
+func TestResolveCredential_CredentialHelper(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("helper commands in this test need a POSIX shell")
+	}
+
+	tests := []struct {
+		name         string
+		helper       string
+		username     string
+		wantToken    string
+		wantUsername string
+	}{
+		{"prints a token", "echo helper-token", "", "helper-token", ""},
+		{"git credential format", `printf 'protocol=https\nhost=git.example.com\nusername=carol\npassword=helper-secret\n'`, "", "helper-secret", "carol"},
+		{"configured username wins", `printf 'username=carol\npassword=helper-secret\n'`, "dave", "helper-secret", "dave"},
+		{"reads the request", `sed -n 's/^host=//p'`, "", "git.example.com", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Credentials: []CredentialConfig{
+				{Host: "git.example.com", Username: tt.username, CredentialHelper: tt.helper},
+			}}
+
+			resolved, err := cfg.ResolveCredential("git.example.com", "")
+			if err != nil {
+				t.Fatalf("ResolveCredential failed: %v", err)
+			}
+			if resolved.Value != tt.wantToken || resolved.Username != tt.wantUsername || resolved.Source != TokenSourceHelper {
+				t.Errorf("Expected (%q, %q, %s), got %+v", tt.wantToken, tt.wantUsername, TokenSourceHelper, resolved)
+			}
+		})
+	}
+
+	// A failing helper is reported with its error output
+	cfg := &Config{Credentials: []CredentialConfig{
+		{Host: "git.example.com", CredentialHelper: "echo locked >&2; exit 1"},
+	}}
+	_, err := cfg.ResolveCredential("git.example.com", "")
+	if err == nil || !strings.Contains(err.Error(), "locked") {
+		t.Errorf("Expected credential helper error, got %v", err)
+	}
+
+	// ...and the environment is still tried
+	os.Setenv("GIKI_TOKEN_GIT_EXAMPLE_COM", "env-token")
+	defer os.Unsetenv("GIKI_TOKEN_GIT_EXAMPLE_COM")
+	resolved, err := cfg.ResolveCredential("git.example.com", "")
+	if err == nil {
+		t.Error("Expected credential helper error")
+	}
+	if resolved.Value != "env-token" || resolved.Source != TokenSourceEnv {
+		t.Errorf("Expected env token after a failing helper, got %+v", resolved)
+	}
+}
+
+func TestResolveCredential_CredentialHelperTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("helper commands in this test need a POSIX shell")
+	}
+	defer func(timeout time.Duration) { credentialHelperTimeout = timeout }(credentialHelperTimeout)
+	credentialHelperTimeout = 100 * time.Millisecond
+
+	cfg := &Config{Credentials: []CredentialConfig{
+		{Host: "git.example.com", CredentialHelper: "sleep 10"},
+	}}
+	start := time.Now()
+	_, err := cfg.ResolveCredential("git.example.com", "")
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Expected timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the helper to be stopped, took %s", elapsed)
+	}
+}
+
+func TestEnvTokenVar(t *testing.T) {
+	tests := map[string]string{
+		"github.com":             "GIKI_TOKEN_GITHUB_COM",
+		"git.example-corp.com":   "GIKI_TOKEN_GIT_EXAMPLE_CORP_COM",
+		"Gitea.Example.org:3000": "GIKI_TOKEN_GITEA_EXAMPLE_ORG_3000",
+	}
+	for host, expected := range tests {
+		if got := EnvTokenVar(host); got != expected {
+			t.Errorf("EnvTokenVar(%q) = %q, want %q", host, got, expected)
+		}
+	}
+}
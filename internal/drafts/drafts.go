@@ -0,0 +1,198 @@
+// Package drafts persists unsaved edits outside the browser, so pending
+// changes survive page reloads, crashes and restarts of the giki process.
+package drafts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned when no draft exists for a path and branch.
+var ErrNotFound = errors.New("draft not found")
+
+// Draft is the unsaved content of one file on one branch.
+type Draft struct {
+	Path     string    `json:"path"`
+	Branch   string    `json:"branch"`
+	Content  string    `json:"content"`
+	BaseHash string    `json:"baseHash,omitempty"` // blob hash the edit started from; empty for a new file
+	Updated  time.Time `json:"updated"`
+}
+
+// Store keeps drafts as JSON files in a directory, one file per path and branch.
+type Store struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewStore returns a store that keeps its drafts in dir.
+// The directory is created when the first draft is saved.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// DefaultDir returns the drafts directory for the repository at source:
+// ~/.giki/drafts/<repo-id>, where the repo ID is derived from the absolute path,
+// or from the URL itself for a repository browsed through a hosting API.
+func DefaultDir(source string) (string, error) {
+	key := source
+	// A URL is already absolute; resolving it as a path would tie it to the working directory
+	if !strings.Contains(source, "://") {
+		absPath, err := filepath.Abs(source)
+		if err != nil {
+			return "", fmt.Errorf("could not resolve path: %w", err)
+		}
+		key = absPath
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+
+	return filepath.Join(homeDir, ".giki", "drafts", RepoID(key)), nil
+}
+
+// RepoID returns a short, stable identifier for the repository at an absolute path or URL.
+func RepoID(absPath string) string {
+	sum := sha256.Sum256([]byte(filepath.Clean(absPath)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Get returns the draft for a path on a branch, or ErrNotFound.
+func (s *Store) Get(branch, path string) (*Draft, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.read(s.file(branch, path))
+}
+
+// List returns every draft, or only those on branch if it is non-empty,
+// sorted by branch and path.
+func (s *Store) List(branch string) ([]Draft, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Draft{}, nil
+		}
+		return nil, fmt.Errorf("failed to read drafts: %w", err)
+	}
+
+	drafts := []Draft{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		draft, err := s.read(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			// Skip files that were removed meanwhile or are not drafts
+			continue
+		}
+		if branch == "" || draft.Branch == branch {
+			drafts = append(drafts, *draft)
+		}
+	}
+
+	sort.Slice(drafts, func(i, j int) bool {
+		if drafts[i].Branch != drafts[j].Branch {
+			return drafts[i].Branch < drafts[j].Branch
+		}
+		return drafts[i].Path < drafts[j].Path
+	})
+
+	return drafts, nil
+}
+
+// Put saves a draft, replacing any earlier draft for the same path and branch.
+// The write is atomic, so a crash leaves either the old or the new draft.
+func (s *Store) Put(draft Draft) error {
+	if draft.Path == "" || draft.Branch == "" {
+		return fmt.Errorf("draft path and branch cannot be empty")
+	}
+
+	data, err := json.Marshal(draft)
+	if err != nil {
+		return fmt.Errorf("failed to encode draft: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create drafts directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".draft-*")
+	if err != nil {
+		return fmt.Errorf("failed to save draft: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save draft: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save draft: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save draft: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.file(draft.Branch, draft.Path)); err != nil {
+		return fmt.Errorf("failed to save draft: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the draft for a path on a branch. Deleting a draft that
+// does not exist is not an error.
+func (s *Store) Delete(branch, path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.file(branch, path)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete draft: %w", err)
+	}
+	return nil
+}
+
+// file returns the file a draft is stored in. Names are hashed so that any
+// path and branch map to a single flat file name.
+func (s *Store) file(branch, path string) string {
+	sum := sha256.Sum256([]byte(branch + "\x00" + path))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// read loads a draft file, returning ErrNotFound if it does not exist.
+func (s *Store) read(file string) (*Draft, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to read draft: %w", err)
+	}
+
+	var draft Draft
+	if err := json.Unmarshal(data, &draft); err != nil {
+		return nil, fmt.Errorf("failed to decode draft: %w", err)
+	}
+
+	return &draft, nil
+}
@@ -0,0 +1,167 @@
+package drafts
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStore_PutGetDelete(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "drafts"))
+
+	// Nothing saved yet
+	if _, err := store.Get("main", "docs/page.md"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	draft := Draft{
+		Path:     "docs/page.md",
+		Branch:   "main",
+		Content:  "# Draft",
+		BaseHash: "abc123",
+		Updated:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	if err := store.Put(draft); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	got, err := store.Get("main", "docs/page.md")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if *got != draft {
+		t.Errorf("expected %+v, got %+v", draft, *got)
+	}
+
+	// The same path on another branch is a separate draft
+	if _, err := store.Get("feature", "docs/page.md"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound on another branch, got %v", err)
+	}
+
+	// Saving again replaces the draft
+	draft.Content = "# Draft, continued"
+	if err := store.Put(draft); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if got, _ := store.Get("main", "docs/page.md"); got == nil || got.Content != draft.Content {
+		t.Errorf("expected replaced draft, got %+v", got)
+	}
+
+	if err := store.Delete("main", "docs/page.md"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := store.Get("main", "docs/page.md"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+
+	// Deleting again is not an error
+	if err := store.Delete("main", "docs/page.md"); err != nil {
+		t.Errorf("expected no error deleting a missing draft, got %v", err)
+	}
+}
+
+func TestStore_List(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+
+	// A missing directory has no drafts
+	if drafts, err := NewStore(filepath.Join(dir, "missing")).List(""); err != nil || len(drafts) != 0 {
+		t.Fatalf("expected no drafts, got %v, %v", drafts, err)
+	}
+
+	for _, d := range []Draft{
+		{Path: "b.md", Branch: "main"},
+		{Path: "a.md", Branch: "main"},
+		{Path: "a.md", Branch: "feature"},
+	} {
+		if err := store.Put(d); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+
+	// Unrelated files in the directory are ignored
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	all, err := store.List("")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	var keys []string
+	for _, d := range all {
+		keys = append(keys, d.Branch+":"+d.Path)
+	}
+	if strings.Join(keys, ",") != "feature:a.md,main:a.md,main:b.md" {
+		t.Errorf("unexpected drafts %v", keys)
+	}
+
+	onMain, err := store.List("main")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(onMain) != 2 || onMain[0].Path != "a.md" || onMain[1].Path != "b.md" {
+		t.Errorf("unexpected drafts on main %+v", onMain)
+	}
+}
+
+func TestStore_PutErrors(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	if err := store.Put(Draft{Branch: "main"}); err == nil {
+		t.Error("expected error for empty path")
+	}
+	if err := store.Put(Draft{Path: "a.md"}); err == nil {
+		t.Error("expected error for empty branch")
+	}
+}
+
+func TestDefaultDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	dir, err := DefaultDir("/work/repo")
+	if err != nil {
+		t.Fatalf("DefaultDir failed: %v", err)
+	}
+
+	expected := filepath.Join(home, ".giki", "drafts", RepoID("/work/repo"))
+	if dir != expected {
+		t.Errorf("expected %s, got %s", expected, dir)
+	}
+
+	// Different repositories get different directories
+	if RepoID("/work/repo") == RepoID("/work/other") {
+		t.Error("expected distinct repo IDs")
+	}
+	if RepoID("/work/repo/") != RepoID("/work/repo") {
+		t.Error("expected repo ID to ignore a trailing slash")
+	}
+}
+
+func TestDefaultDir_URL(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	const url = "https://github.com/octo/wiki"
+	dir, err := DefaultDir(url)
+	if err != nil {
+		t.Fatalf("DefaultDir failed: %v", err)
+	}
+
+	// The directory for a URL does not depend on the working directory
+	t.Chdir(t.TempDir())
+	again, err := DefaultDir(url)
+	if err != nil {
+		t.Fatalf("DefaultDir failed: %v", err)
+	}
+	if again != dir {
+		t.Errorf("expected %s from any working directory, got %s", dir, again)
+	}
+
+	if other, _ := DefaultDir("https://github.com/octo/other"); other == dir {
+		t.Error("expected distinct directories for distinct URLs")
+	}
+}
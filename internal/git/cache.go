@@ -0,0 +1,177 @@
+package git
+
+import (
+	"bytes"
+	"container/list"
+	"strings"
+	"sync"
+)
+
+// DefaultCacheSize is the number of bytes of trees and files a CachingProvider
+// keeps when created with a size of zero.
+const DefaultCacheSize = 64 << 20
+
+// treeNodeCost approximates the memory a TreeNode takes besides its strings.
+const treeNodeCost = 64
+
+// RevisionResolver is implemented by providers that can resolve a branch, tag,
+// or commit to the hash of the commit it points to. An empty revision means
+// the current branch.
+type RevisionResolver interface {
+	ResolveRevision(revision string) (string, error)
+}
+
+// CachingProvider wraps a GitProvider whose reads are expensive, such as one
+// backed by a hosting API, with an LRU cache of file trees and file contents.
+//
+// Entries are keyed by commit hash, so they never go stale: each read first
+// resolves its branch to a commit, which API providers revalidate cheaply with
+// an ETag, and is served from the cache if that commit was read before. Reads
+// from providers that are not RevisionResolvers are passed through uncached,
+// as are all other methods.
+type CachingProvider struct {
+	GitProvider
+	resolver RevisionResolver // nil if the provider cannot resolve revisions
+	cache    *lruCache
+}
+
+// NewCachingProvider wraps provider with a cache of up to size bytes of trees
+// and files, or DefaultCacheSize if size is zero or less. Files larger than the
+// whole cache are not cached.
+func NewCachingProvider(provider GitProvider, size int) *CachingProvider {
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+
+	resolver, _ := provider.(RevisionResolver)
+	return &CachingProvider{
+		GitProvider: provider,
+		resolver:    resolver,
+		cache:       newLRUCache(size),
+	}
+}
+
+// Tree returns the file tree for the given branch, tag, or commit, from the
+// cache if that commit's tree was read before. Cached trees are shared
+// between callers and must not be modified.
+func (c *CachingProvider) Tree(branch string) (*TreeNode, error) {
+	if c.resolver == nil {
+		return c.GitProvider.Tree(branch)
+	}
+
+	hash, err := c.resolver.ResolveRevision(branch)
+	if err != nil {
+		return nil, err
+	}
+
+	key := "tree\x00" + hash
+	if tree, ok := c.cache.get(key); ok {
+		return tree.(*TreeNode), nil
+	}
+
+	tree, err := c.GitProvider.Tree(hash)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.add(key, tree, treeCost(tree))
+	return tree, nil
+}
+
+// FileContent returns the raw bytes of a file on the given branch, tag, or
+// commit, from the cache if that file was read at that commit before.
+func (c *CachingProvider) FileContent(path, branch string) ([]byte, error) {
+	if c.resolver == nil {
+		return c.GitProvider.FileContent(path, branch)
+	}
+
+	hash, err := c.resolver.ResolveRevision(branch)
+	if err != nil {
+		return nil, err
+	}
+
+	key := "blob\x00" + hash + "\x00" + strings.Trim(path, "/")
+	if content, ok := c.cache.get(key); ok {
+		return bytes.Clone(content.([]byte)), nil
+	}
+
+	content, err := c.GitProvider.FileContent(path, hash)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.add(key, bytes.Clone(content), len(content))
+	return content, nil
+}
+
+// treeCost approximates the number of bytes a tree takes in memory.
+func treeCost(node *TreeNode) int {
+	cost := treeNodeCost + len(node.Name) + len(node.Path)
+	for i := range node.Children {
+		cost += treeCost(&node.Children[i])
+	}
+	return cost
+}
+
+// lruCache is a map bounded by the total cost, in bytes, of its values, that
+// evicts its least recently used entries when full.
+type lruCache struct {
+	mu      sync.Mutex
+	size    int // maximum total cost
+	used    int // total cost of the entries
+	entries map[string]*list.Element
+	order   *list.List // of *lruEntry, most recently used first
+}
+
+type lruEntry struct {
+	key   string
+	value any
+	cost  int
+}
+
+func newLRUCache(size int) *lruCache {
+	return &lruCache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// get returns the value for key and marks it as most recently used.
+func (l *lruCache) get(key string) (any, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	elem, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+	l.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry).value, true
+}
+
+// add stores value for key, evicting least recently used entries until the
+// cache is within its size. Values costing more than the whole cache are not
+// stored, and replace any earlier value for key.
+func (l *lruCache) add(key string, value any, cost int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if elem, ok := l.entries[key]; ok {
+		l.remove(elem)
+	}
+	if cost > l.size {
+		return
+	}
+
+	l.entries[key] = l.order.PushFront(&lruEntry{key: key, value: value, cost: cost})
+	l.used += cost
+	for l.used > l.size {
+		l.remove(l.order.Back())
+	}
+}
+
+// remove deletes an entry. The caller must hold l.mu.
+func (l *lruCache) remove(elem *list.Element) {
+	entry := l.order.Remove(elem).(*lruEntry)
+	delete(l.entries, entry.key)
+	l.used -= entry.cost
+}
@@ -0,0 +1,196 @@
+package git
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCachingProvider(t *testing.T) {
+	fake, server := newFakeGitHub(t)
+
+	github, err := NewGitHubProvider(server.URL, "octo", "wiki", "", "")
+	if err != nil {
+		t.Fatalf("NewGitHubProvider failed: %v", err)
+	}
+	provider := NewCachingProvider(github, 0)
+
+	// Repeated reads of an unchanged branch are served from the cache
+	for range 3 {
+		content, err := provider.FileContent("README.md", "")
+		if err != nil {
+			t.Fatalf("FileContent failed: %v", err)
+		}
+		if string(content) != "# Wiki" {
+			t.Errorf("expected %q, got %q", "# Wiki", content)
+		}
+		content[0] = 'X' // callers may modify what they get
+	}
+	if n := fake.served("contents"); n != 1 {
+		t.Errorf("expected 1 contents request, got %d", n)
+	}
+
+	for range 3 {
+		tree, err := provider.Tree("")
+		if err != nil {
+			t.Fatalf("Tree failed: %v", err)
+		}
+		if len(tree.Children) != 2 {
+			t.Errorf("unexpected tree %+v", tree.Children)
+		}
+	}
+	if n := fake.served("trees"); n != 1 {
+		t.Errorf("expected 1 trees request, got %d", n)
+	}
+
+	// Each read still checks the branch, but only by revalidating it; the
+	// other full request looked up the commit's tree when the tree was read
+	if n := fake.served("commits"); n != 2 {
+		t.Errorf("expected 2 full commits requests, got %d", n)
+	}
+	if fake.revalidated() == 0 {
+		t.Error("expected branch resolution to be revalidated")
+	}
+
+	// Other branches are cached separately
+	content, err := provider.FileContent("README.md", "feature/x")
+	if err != nil {
+		t.Fatalf("FileContent failed: %v", err)
+	}
+	if string(content) != "# Feature" {
+		t.Errorf("expected %q, got %q", "# Feature", content)
+	}
+
+	// A push moves the branch to a new commit, which is read afresh
+	fake.branches["main"]["README.md"] = "# Updated"
+	fake.push("main")
+	content, err = provider.FileContent("README.md", "")
+	if err != nil {
+		t.Fatalf("FileContent failed: %v", err)
+	}
+	if string(content) != "# Updated" {
+		t.Errorf("expected %q after push, got %q", "# Updated", content)
+	}
+}
+
+func TestCachingProvider_Eviction(t *testing.T) {
+	fake, server := newFakeGitHub(t)
+
+	github, err := NewGitHubProvider(server.URL, "octo", "wiki", "", "")
+	if err != nil {
+		t.Fatalf("NewGitHubProvider failed: %v", err)
+	}
+	provider := NewCachingProvider(github, 12) // bytes; the files are 6, 5 and 3 bytes
+
+	read := func(path string) {
+		t.Helper()
+		if _, err := provider.FileContent(path, ""); err != nil {
+			t.Fatalf("FileContent(%s) failed: %v", path, err)
+		}
+	}
+
+	read("README.md")
+	read("docs/guide.md")
+	read("README.md") // now the most recently used
+	read("docs/api/index.md")
+	if n := fake.served("contents"); n != 3 {
+		t.Fatalf("expected 3 contents requests, got %d", n)
+	}
+
+	// docs/guide.md was least recently used, so it was evicted
+	read("README.md")
+	read("docs/api/index.md")
+	if n := fake.served("contents"); n != 3 {
+		t.Errorf("expected cached files to be kept, got %d requests", n)
+	}
+	read("docs/guide.md")
+	if n := fake.served("contents"); n != 4 {
+		t.Errorf("expected evicted file to be read again, got %d requests", n)
+	}
+
+	// Files larger than the whole cache are never cached
+	small := NewCachingProvider(github, 5)
+	for range 2 {
+		if _, err := small.FileContent("README.md", ""); err != nil {
+			t.Fatalf("FileContent failed: %v", err)
+		}
+	}
+	if n := fake.served("contents"); n != 6 {
+		t.Errorf("expected oversized file to be read each time, got %d requests", n)
+	}
+}
+
+func TestLRUCache(t *testing.T) {
+	cache := newLRUCache(10)
+	cache.add("a", "a", 4)
+	cache.add("b", "b", 4)
+	cache.add("a", "a2", 7) // replaces a, and pushes out b
+	if _, ok := cache.get("b"); ok {
+		t.Error("expected b to be evicted")
+	}
+	if value, ok := cache.get("a"); !ok || value != "a2" {
+		t.Errorf("expected a2, got %v", value)
+	}
+	if cache.used != 7 {
+		t.Errorf("expected 7 bytes used, got %d", cache.used)
+	}
+
+	cache.add("a", "big", 11) // too big to cache, so a is dropped
+	if _, ok := cache.get("a"); ok {
+		t.Error("expected oversized value not to be cached")
+	}
+	if cache.used != 0 || len(cache.entries) != 0 {
+		t.Errorf("expected empty cache, got %d bytes in %d entries", cache.used, len(cache.entries))
+	}
+}
+
+func TestCachingProvider_Errors(t *testing.T) {
+	fake, server := newFakeGitHub(t)
+
+	github, err := NewGitHubProvider(server.URL, "octo", "wiki", "", "")
+	if err != nil {
+		t.Fatalf("NewGitHubProvider failed: %v", err)
+	}
+	provider := NewCachingProvider(github, 0)
+
+	if _, err := provider.FileContent("missing.md", ""); err == nil {
+		t.Error("expected error for a missing file")
+	}
+	if _, err := provider.Tree("missing"); err == nil {
+		t.Error("expected error for a missing branch")
+	}
+
+	// Rate limit errors reach the caller intact
+	fake.rateLimited.Store(true)
+	_, err = provider.FileContent("README.md", "")
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) {
+		t.Errorf("expected RateLimitError, got %v", err)
+	}
+}
+
+func TestCachingProvider_Uncached(t *testing.T) {
+	// Create temporary git repository with initial commit
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	local, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	provider := NewCachingProvider(local, 0)
+
+	// A provider that cannot resolve revisions is read directly, so
+	// working tree changes show up immediately
+	for _, content := range []string{"first", "second"} {
+		if err := provider.WriteFile("notes.md", []byte(content)); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+		got, err := provider.FileContent("notes.md", "")
+		if err != nil {
+			t.Fatalf("FileContent failed: %v", err)
+		}
+		if string(got) != content {
+			t.Errorf("expected %q, got %q", content, got)
+		}
+	}
+}
@@ -1,13 +1,16 @@
 package git
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 )
 
@@ -45,6 +48,17 @@ func CloneRemote(url, targetPath string) error {
 // If token is empty, no authentication is used (for public repos).
 // Use GetClonePath first to determine the path and check if it already exists.
 func CloneRemoteWithAuth(url, targetPath, token string) error {
+	return CloneRemoteWithCredentials(url, targetPath, Credentials{Token: token})
+}
+
+// CloneRemoteWithCredentials clones a remote git repository, authenticating
+// with the token for HTTP(S) URLs and with the SSH options for SSH URLs.
+func CloneRemoteWithCredentials(url, targetPath string, creds Credentials) error {
+	auth, err := creds.authFor(url)
+	if err != nil {
+		return err
+	}
+
 	// Create parent directories
 	if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
 		return fmt.Errorf("failed to create parent directories: %w", err)
@@ -53,21 +67,12 @@ func CloneRemoteWithAuth(url, targetPath, token string) error {
 	// Prepare clone options
 	cloneOpts := &git.CloneOptions{
 		URL:      url,
+		Auth:     auth,
 		Progress: os.Stdout,
 	}
 
-	// Add authentication if token is provided
-	if token != "" {
-		// For HTTPS URLs, use token as username with empty password
-		// This works for both GitHub (PAT) and GitLab (PAT)
-		cloneOpts.Auth = &http.BasicAuth{
-			Username: token, // GitHub/GitLab PATs can be used as username
-			Password: "",    // Leave password empty
-		}
-	}
-
 	// Clone the repository
-	_, err := git.PlainClone(targetPath, false, cloneOpts)
+	_, err = git.PlainClone(targetPath, false, cloneOpts)
 	if err != nil {
 		return fmt.Errorf("failed to clone repository: %w", err)
 	}
@@ -84,45 +89,168 @@ func PullExisting(path string) error {
 // PullExistingWithAuth pulls the latest changes with optional authentication.
 // If token is empty, no authentication is used (for public repos).
 func PullExistingWithAuth(path, token string) error {
+	return PullExistingWithCredentials(path, Credentials{Token: token})
+}
+
+// PullExistingWithCredentials pulls the latest changes from origin,
+// authenticating as CloneRemoteWithCredentials does.
+func PullExistingWithCredentials(path string, creds Credentials) error {
 	repo, err := git.PlainOpen(path)
 	if err != nil {
 		return fmt.Errorf("failed to open repository: %w", err)
 	}
 
-	worktree, err := repo.Worktree()
+	remote, err := repo.Remote("origin")
 	if err != nil {
-		return fmt.Errorf("failed to get worktree: %w", err)
+		return fmt.Errorf("remote 'origin' not found")
 	}
 
-	// Prepare pull options
-	pullOpts := &git.PullOptions{
-		RemoteName: "origin",
+	var auth transport.AuthMethod
+	if urls := remote.Config().URLs; len(urls) > 0 {
+		if auth, err = creds.authFor(urls[0]); err != nil {
+			return err
+		}
 	}
 
-	// Add authentication if token is provided
-	if token != "" {
-		pullOpts.Auth = &http.BasicAuth{
-			Username: token, // GitHub/GitLab PATs can be used as username
-			Password: "",    // Leave password empty
+	return pullBranch(context.Background(), repo, "origin", auth, nil)
+}
+
+// pullBranch fetches from the remote and fast-forwards the current branch
+// to its upstream. Progress messages are written to progress if non-nil.
+func pullBranch(ctx context.Context, repo *git.Repository, remoteName string, auth transport.AuthMethod, progress io.Writer) error {
+	branch := ""
+	if head, err := repo.Head(); err == nil && head.Name().IsBranch() {
+		branch = head.Name().Short()
+	}
+
+	target, err := fetchUpstream(ctx, repo, remoteName, branch, auth, progress)
+	if err != nil {
+		return err
+	}
+	return fastForward(repo, target)
+}
+
+// fetchUpstream fetches from the remote and returns the commit the branch
+// should be fast-forwarded to: its upstream when one is configured on this
+// remote, or else the remote's HEAD branch.
+func fetchUpstream(ctx context.Context, repo *git.Repository, remoteName, branch string, auth transport.AuthMethod, progress io.Writer) (plumbing.Hash, error) {
+	remote, err := repo.Remote(remoteName)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("remote '%s' not found", remoteName)
+	}
+
+	err = remote.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: remoteName,
+		Auth:       auth,
+		Progress:   progress,
+	})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return plumbing.ZeroHash, fmt.Errorf("failed to pull: %w", err)
+	}
+
+	// Merge the branch's upstream when one is configured, rather than the remote's HEAD
+	var merge plumbing.ReferenceName
+	cfg, err := repo.Config()
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to read repository config: %w", err)
+	}
+	if upstream := cfg.Branches[branch]; upstream != nil && upstream.Remote == remoteName && upstream.Merge != "" {
+		merge = upstream.Merge
+	} else {
+		refs, err := remote.ListContext(ctx, &git.ListOptions{Auth: auth})
+		if err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("failed to pull: %w", err)
 		}
+		for _, ref := range refs {
+			if ref.Name() == plumbing.HEAD && ref.Type() == plumbing.SymbolicReference {
+				merge = ref.Target()
+			}
+		}
+	}
+	if merge == "" {
+		return plumbing.ZeroHash, fmt.Errorf("no upstream branch configured")
+	}
+
+	ref, err := repo.Reference(plumbing.NewRemoteReferenceName(remoteName, merge.Short()), true)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("no upstream branch configured")
+	}
+	return ref.Hash(), nil
+}
+
+// fastForward moves the current branch and the working tree to target.
+// Nothing changes if the branch already contains target.
+// Returns a *PullBlockedError if the branch has diverged from target or
+// the working tree has unstaged changes.
+func fastForward(repo *git.Repository, target plumbing.Hash) error {
+	head, err := repo.Head()
+	if err != nil {
+		return fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	if head.Hash() == target {
+		return nil
+	}
+
+	headCommit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return fmt.Errorf("failed to get HEAD commit: %w", err)
+	}
+	targetCommit, err := repo.CommitObject(target)
+	if err != nil {
+		return fmt.Errorf("failed to get upstream commit: %w", err)
 	}
 
-	err = worktree.Pull(pullOpts)
+	behind, err := headCommit.IsAncestor(targetCommit)
 	if err != nil {
-		// If already up-to-date, that's not an error
-		if err == git.NoErrAlreadyUpToDate {
+		return fmt.Errorf("failed to compare with upstream: %w", err)
+	}
+	if !behind {
+		ahead, err := targetCommit.IsAncestor(headCommit)
+		if err != nil {
+			return fmt.Errorf("failed to compare with upstream: %w", err)
+		}
+		if ahead {
 			return nil
 		}
-		// Check if the error is because there's nothing to pull (e.g., no upstream configured)
-		if err == plumbing.ErrReferenceNotFound {
-			return fmt.Errorf("no upstream branch configured")
+		return &PullBlockedError{Reason: PullDiverged}
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree: %w", err)
+	}
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(head.Name(), target)); err != nil {
+		return fmt.Errorf("failed to update branch: %w", err)
+	}
+	err = worktree.Reset(&git.ResetOptions{Mode: git.MergeReset, Commit: target})
+	if err == git.ErrUnstagedChanges {
+		// Put the branch back so nothing changed
+		if err := repo.Storer.SetReference(head); err != nil {
+			return fmt.Errorf("failed to restore branch: %w", err)
 		}
+		return &PullBlockedError{Reason: PullDirtyTree}
+	}
+	if err != nil {
 		return fmt.Errorf("failed to pull: %w", err)
 	}
 
 	return nil
 }
 
+// tokenAuth returns HTTP basic authentication for a personal access token.
+// Without a username, the token is used as the username with an empty
+// password, which works for both GitHub and GitLab PATs. Hosts that need a
+// username, such as Bitbucket with app passwords, get the token as password.
+func tokenAuth(username, token string) *http.BasicAuth {
+	if username == "" {
+		return &http.BasicAuth{
+			Username: token, // GitHub/GitLab PATs can be used as username
+			Password: "",    // Leave password empty
+		}
+	}
+	return &http.BasicAuth{Username: username, Password: token}
+}
+
 // parseGitURL extracts the owner and repository name from a git URL.
 // Supports formats:
 //   - https://github.com/owner/repo
@@ -183,6 +311,42 @@ func parseGitURL(url string) (owner, repo string, err error) {
 	return "", "", fmt.Errorf("unsupported URL format: %s", url)
 }
 
+// ParseRemoteURL splits a remote repository URL (HTTPS, ssh://, or git@host:path)
+// into its hostname and its full repository path without the .git suffix, such
+// as "group/subgroup/repo".
+func ParseRemoteURL(url string) (host, repoPath string, err error) {
+	url = strings.TrimSpace(url)
+
+	var rest string
+	switch {
+	case strings.HasPrefix(url, "git@"):
+		var ok bool
+		host, rest, ok = strings.Cut(strings.TrimPrefix(url, "git@"), ":")
+		if !ok {
+			return "", "", fmt.Errorf("invalid SSH URL format: %s", url)
+		}
+	case strings.HasPrefix(url, "https://"), strings.HasPrefix(url, "http://"), strings.HasPrefix(url, "ssh://"):
+		_, withoutProtocol, _ := strings.Cut(url, "://")
+		host, rest, _ = strings.Cut(withoutProtocol, "/")
+		// Drop any user@ prefix and :port suffix
+		if atIndex := strings.LastIndex(host, "@"); atIndex != -1 {
+			host = host[atIndex+1:]
+		}
+		if colonIndex := strings.Index(host, ":"); colonIndex != -1 {
+			host = host[:colonIndex]
+		}
+	default:
+		return "", "", fmt.Errorf("unsupported URL format: %s", url)
+	}
+
+	repoPath = strings.TrimSuffix(strings.Trim(rest, "/"), ".git")
+	if host == "" || !strings.Contains(repoPath, "/") {
+		return "", "", fmt.Errorf("invalid repository URL: %s", url)
+	}
+
+	return strings.ToLower(host), repoPath, nil
+}
+
 // extractOwnerRepo extracts owner and repo from a path like "owner/repo" or "owner/repo.git"
 func extractOwnerRepo(path string) (owner, repo string, err error) {
 	path = strings.TrimSpace(path)
@@ -0,0 +1,252 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5/utils/diff"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// File change statuses reported in FileDiff.Status.
+const (
+	FileAdded    = "added"
+	FileModified = "modified"
+	FileDeleted  = "deleted"
+	FileRenamed  = "renamed"
+)
+
+// diffContextLines is the number of unchanged lines shown around each change.
+const diffContextLines = 3
+
+// noNewlineMarker is appended after a line that has no trailing newline,
+// matching the output of git diff.
+const noNewlineMarker = `\ No newline at end of file`
+
+// diffLine is a single line of a line-oriented diff.
+type diffLine struct {
+	op   byte   // ' ' (unchanged), '+' (added) or '-' (deleted)
+	text string // line content including its trailing newline, if any
+}
+
+// buildFileDiff computes the diff between two versions of a file.
+// oldContent is nil when the file did not exist before, and newContent is nil
+// when the file no longer exists. Binary files are reported without hunks.
+func buildFileDiff(oldPath, newPath string, oldContent, newContent []byte) FileDiff {
+	fd := FileDiff{
+		Path:  newPath,
+		Hunks: []DiffHunk{},
+	}
+
+	switch {
+	case oldContent == nil:
+		fd.Status = FileAdded
+	case newContent == nil:
+		fd.Status = FileDeleted
+		fd.Path = oldPath
+	case oldPath != newPath:
+		fd.Status = FileRenamed
+		fd.OldPath = oldPath
+	default:
+		fd.Status = FileModified
+	}
+
+	if !isText(oldContent) || !isText(newContent) {
+		fd.Binary = true
+		fd.Patch = patchHeader(oldPath, newPath, fd.Status) +
+			fmt.Sprintf("Binary files %s and %s differ\n", patchName("a", oldPath, oldContent == nil), patchName("b", newPath, newContent == nil))
+		return fd
+	}
+
+	lines := diffLines(string(oldContent), string(newContent))
+	fd.Hunks = buildHunks(lines)
+
+	var patch strings.Builder
+	patch.WriteString(patchHeader(oldPath, newPath, fd.Status))
+	if len(fd.Hunks) > 0 {
+		fmt.Fprintf(&patch, "--- %s\n", patchName("a", oldPath, oldContent == nil))
+		fmt.Fprintf(&patch, "+++ %s\n", patchName("b", newPath, newContent == nil))
+	}
+	for _, hunk := range fd.Hunks {
+		patch.WriteString(hunk.Header)
+		patch.WriteString("\n")
+		for _, line := range hunk.Lines {
+			patch.WriteString(line)
+			patch.WriteString("\n")
+
+			switch line[0] {
+			case '+':
+				fd.Additions++
+			case '-':
+				fd.Deletions++
+			}
+		}
+	}
+	fd.Patch = patch.String()
+
+	return fd
+}
+
+// isText reports whether content is text. Missing content counts as text.
+func isText(content []byte) bool {
+	if content == nil {
+		return true
+	}
+	return isTextContent(content)
+}
+
+// patchHeader returns the "diff --git" header lines for a file patch.
+func patchHeader(oldPath, newPath, status string) string {
+	if oldPath == "" {
+		oldPath = newPath
+	}
+	if newPath == "" {
+		newPath = oldPath
+	}
+
+	header := fmt.Sprintf("diff --git a/%s b/%s\n", oldPath, newPath)
+	switch status {
+	case FileAdded:
+		header += "new file mode 100644\n"
+	case FileDeleted:
+		header += "deleted file mode 100644\n"
+	case FileRenamed:
+		header += fmt.Sprintf("rename from %s\nrename to %s\n", oldPath, newPath)
+	}
+	return header
+}
+
+// patchName returns the name used in ---/+++ lines, or /dev/null for a missing side.
+func patchName(prefix, path string, missing bool) string {
+	if missing {
+		return "/dev/null"
+	}
+	return prefix + "/" + path
+}
+
+// diffLines computes a line-oriented diff between two texts.
+func diffLines(oldText, newText string) []diffLine {
+	var lines []diffLine
+
+	for _, d := range diff.Do(oldText, newText) {
+		var op byte
+		switch d.Type {
+		case diffmatchpatch.DiffEqual:
+			op = ' '
+		case diffmatchpatch.DiffInsert:
+			op = '+'
+		case diffmatchpatch.DiffDelete:
+			op = '-'
+		}
+
+		for _, text := range strings.SplitAfter(d.Text, "\n") {
+			if text == "" {
+				continue
+			}
+			lines = append(lines, diffLine{op: op, text: text})
+		}
+	}
+
+	return lines
+}
+
+// buildHunks groups diff lines into hunks with diffContextLines lines of context.
+// Changes separated by no more than twice the context are merged into one hunk.
+func buildHunks(lines []diffLine) []DiffHunk {
+	// oldBefore[i] and newBefore[i] count the old/new lines preceding lines[i]
+	oldBefore := make([]int, len(lines)+1)
+	newBefore := make([]int, len(lines)+1)
+	for i, line := range lines {
+		oldBefore[i+1] = oldBefore[i]
+		newBefore[i+1] = newBefore[i]
+		if line.op != '+' {
+			oldBefore[i+1]++
+		}
+		if line.op != '-' {
+			newBefore[i+1]++
+		}
+	}
+
+	hunks := []DiffHunk{}
+
+	i := 0
+	for i < len(lines) {
+		if lines[i].op == ' ' {
+			i++
+			continue
+		}
+
+		start := max(0, i-diffContextLines)
+
+		// Find the last change of this group
+		last := i
+		j := i + 1
+		for j < len(lines) {
+			if lines[j].op != ' ' {
+				last = j
+				j++
+				continue
+			}
+
+			// Measure the run of unchanged lines
+			k := j
+			for k < len(lines) && lines[k].op == ' ' {
+				k++
+			}
+			if k < len(lines) && k-j <= 2*diffContextLines {
+				j = k
+				continue
+			}
+			break
+		}
+
+		end := min(len(lines), last+1+diffContextLines)
+		hunks = append(hunks, newHunk(lines[start:end], oldBefore[start], newBefore[start]))
+		i = end
+	}
+
+	return hunks
+}
+
+// newHunk builds a hunk from a slice of diff lines.
+// oldBefore and newBefore are the number of old/new lines preceding the slice.
+func newHunk(lines []diffLine, oldBefore, newBefore int) DiffHunk {
+	hunk := DiffHunk{Lines: make([]string, 0, len(lines))}
+
+	for _, line := range lines {
+		if line.op != '+' {
+			hunk.OldLines++
+		}
+		if line.op != '-' {
+			hunk.NewLines++
+		}
+
+		text, hasNewline := strings.CutSuffix(line.text, "\n")
+		hunk.Lines = append(hunk.Lines, string(line.op)+text)
+		if !hasNewline {
+			hunk.Lines = append(hunk.Lines, noNewlineMarker)
+		}
+	}
+
+	// Like git, an empty side starts at the line before the hunk
+	hunk.OldStart = oldBefore
+	if hunk.OldLines > 0 {
+		hunk.OldStart++
+	}
+	hunk.NewStart = newBefore
+	if hunk.NewLines > 0 {
+		hunk.NewStart++
+	}
+
+	hunk.Header = fmt.Sprintf("@@ -%s +%s @@", hunkRange(hunk.OldStart, hunk.OldLines), hunkRange(hunk.NewStart, hunk.NewLines))
+
+	return hunk
+}
+
+// hunkRange formats a hunk range, omitting the count when it is 1.
+func hunkRange(start, count int) string {
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
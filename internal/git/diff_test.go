@@ -0,0 +1,141 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestBuildFileDiff_Modified(t *testing.T) {
+	oldContent := []byte("one\ntwo\nthree\n")
+	newContent := []byte("one\nTWO\nthree\nfour\n")
+
+	fd := buildFileDiff("notes.md", "notes.md", oldContent, newContent)
+
+	if fd.Status != FileModified {
+		t.Errorf("expected status %q, got %q", FileModified, fd.Status)
+	}
+	if fd.Additions != 2 || fd.Deletions != 1 {
+		t.Errorf("expected +2 -1, got +%d -%d", fd.Additions, fd.Deletions)
+	}
+
+	expected := "diff --git a/notes.md b/notes.md\n" +
+		"--- a/notes.md\n" +
+		"+++ b/notes.md\n" +
+		"@@ -1,3 +1,4 @@\n" +
+		" one\n" +
+		"-two\n" +
+		"+TWO\n" +
+		" three\n" +
+		"+four\n"
+	if fd.Patch != expected {
+		t.Errorf("unexpected patch:\n%s\nexpected:\n%s", fd.Patch, expected)
+	}
+}
+
+func TestBuildFileDiff_AddedAndDeleted(t *testing.T) {
+	added := buildFileDiff("new.md", "new.md", nil, []byte("hello\n"))
+	if added.Status != FileAdded {
+		t.Errorf("expected status %q, got %q", FileAdded, added.Status)
+	}
+	if len(added.Hunks) != 1 || added.Hunks[0].Header != "@@ -0,0 +1 @@" {
+		t.Errorf("unexpected hunks for added file: %+v", added.Hunks)
+	}
+	if !strings.Contains(added.Patch, "--- /dev/null\n+++ b/new.md\n") {
+		t.Errorf("expected /dev/null old side in patch:\n%s", added.Patch)
+	}
+
+	deleted := buildFileDiff("old.md", "old.md", []byte("bye\n"), nil)
+	if deleted.Status != FileDeleted {
+		t.Errorf("expected status %q, got %q", FileDeleted, deleted.Status)
+	}
+	if deleted.Path != "old.md" {
+		t.Errorf("expected path old.md, got %s", deleted.Path)
+	}
+	if len(deleted.Hunks) != 1 || deleted.Hunks[0].Header != "@@ -1 +0,0 @@" {
+		t.Errorf("unexpected hunks for deleted file: %+v", deleted.Hunks)
+	}
+	if !strings.Contains(deleted.Patch, "--- a/old.md\n+++ /dev/null\n") {
+		t.Errorf("expected /dev/null new side in patch:\n%s", deleted.Patch)
+	}
+}
+
+func TestBuildFileDiff_Renamed(t *testing.T) {
+	fd := buildFileDiff("a.md", "b.md", []byte("same\n"), []byte("same\n"))
+
+	if fd.Status != FileRenamed {
+		t.Errorf("expected status %q, got %q", FileRenamed, fd.Status)
+	}
+	if fd.OldPath != "a.md" || fd.Path != "b.md" {
+		t.Errorf("expected a.md -> b.md, got %s -> %s", fd.OldPath, fd.Path)
+	}
+	if len(fd.Hunks) != 0 {
+		t.Errorf("expected no hunks for pure rename, got %d", len(fd.Hunks))
+	}
+	if !strings.Contains(fd.Patch, "rename from a.md\nrename to b.md\n") {
+		t.Errorf("expected rename header in patch:\n%s", fd.Patch)
+	}
+}
+
+func TestBuildFileDiff_Binary(t *testing.T) {
+	fd := buildFileDiff("img.png", "img.png", []byte{0x89, 0x00, 0x01}, []byte{0x89, 0x00, 0x02})
+
+	if !fd.Binary {
+		t.Error("expected binary diff")
+	}
+	if len(fd.Hunks) != 0 {
+		t.Errorf("expected no hunks for binary file, got %d", len(fd.Hunks))
+	}
+	if !strings.Contains(fd.Patch, "Binary files a/img.png and b/img.png differ") {
+		t.Errorf("unexpected binary patch:\n%s", fd.Patch)
+	}
+}
+
+func TestBuildFileDiff_NoNewlineAtEnd(t *testing.T) {
+	fd := buildFileDiff("f.txt", "f.txt", []byte("a\nb"), []byte("a\nb\n"))
+
+	expected := []string{" a", "-b", noNewlineMarker, "+b"}
+	if len(fd.Hunks) != 1 {
+		t.Fatalf("expected 1 hunk, got %d", len(fd.Hunks))
+	}
+	if strings.Join(fd.Hunks[0].Lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected lines %q, got %q", expected, fd.Hunks[0].Lines)
+	}
+	if fd.Additions != 1 || fd.Deletions != 1 {
+		t.Errorf("expected +1 -1, got +%d -%d", fd.Additions, fd.Deletions)
+	}
+}
+
+func TestBuildHunks_SplitsDistantChanges(t *testing.T) {
+	var oldLines, newLines []string
+	for i := 1; i <= 20; i++ {
+		oldLines = append(oldLines, fmt.Sprintf("line %d", i))
+		newLines = append(newLines, fmt.Sprintf("line %d", i))
+	}
+	newLines[1] = "changed 2"
+	newLines[17] = "changed 18"
+
+	fd := buildFileDiff("f.txt", "f.txt",
+		[]byte(strings.Join(oldLines, "\n")+"\n"),
+		[]byte(strings.Join(newLines, "\n")+"\n"))
+
+	if len(fd.Hunks) != 2 {
+		t.Fatalf("expected 2 hunks, got %d: %+v", len(fd.Hunks), fd.Hunks)
+	}
+	if fd.Hunks[0].Header != "@@ -1,5 +1,5 @@" {
+		t.Errorf("unexpected first hunk header %q", fd.Hunks[0].Header)
+	}
+	if fd.Hunks[1].Header != "@@ -15,6 +15,6 @@" {
+		t.Errorf("unexpected second hunk header %q", fd.Hunks[1].Header)
+	}
+
+	// Changes close together share a hunk
+	newLines[17] = "line 18"
+	newLines[6] = "changed 7"
+	fd = buildFileDiff("f.txt", "f.txt",
+		[]byte(strings.Join(oldLines, "\n")+"\n"),
+		[]byte(strings.Join(newLines, "\n")+"\n"))
+	if len(fd.Hunks) != 1 {
+		t.Errorf("expected nearby changes to merge into 1 hunk, got %d", len(fd.Hunks))
+	}
+}
@@ -0,0 +1,205 @@
+package git
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// GiteaProvider implements GitProvider by browsing a repository through the
+// Gitea REST API, which Forgejo also serves, without cloning it. Like
+// GitHubProvider it is read-only.
+type GiteaProvider struct {
+	apiRepo
+	owner string
+	repo  string
+}
+
+// NewGiteaProvider creates a provider for the repository owner/repo served by
+// the Gitea API at apiURL (the /api/v1 root of the instance).
+// If branch is empty, uses the repository's default branch. A token is needed
+// for private repositories.
+// Returns an error if the repository or branch does not exist.
+func NewGiteaProvider(apiURL, owner, repo, branch, token string) (*GiteaProvider, error) {
+	header := http.Header{}
+	header.Set("Accept", "application/json")
+	if token != "" {
+		header.Set("Authorization", "token "+token)
+	}
+
+	p := &GiteaProvider{owner: owner, repo: repo}
+	p.api = newAPIClient(apiURL, header)
+
+	// Look up the repository, which also checks it is accessible
+	var info struct {
+		HTMLURL       string `json:"html_url"`
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := p.api.getJSON(p.repoPath(), nil, &info); err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("repository %s/%s not found", owner, repo)
+		}
+		return nil, fmt.Errorf("failed to load repository: %w", err)
+	}
+	p.source = info.HTMLURL
+	p.defaultBranch = info.DefaultBranch
+
+	if branch == "" {
+		branch = info.DefaultBranch
+	} else if err := p.checkBranch(branch); err != nil {
+		return nil, err
+	}
+	p.branch = branch
+
+	return p, nil
+}
+
+// Tree returns the complete file tree for the given branch, tag, or commit.
+func (p *GiteaProvider) Tree(branch string) (*TreeNode, error) {
+	const perPage = 1000
+
+	commit, err := p.commit(branch)
+	if err != nil {
+		return nil, err
+	}
+	treeSHA := commit.Commit.Tree.SHA
+
+	// Large trees are paginated; truncated is set while entries remain
+	var paths []string
+	for page := 1; ; page++ {
+		var tree struct {
+			Tree []struct {
+				Path string `json:"path"`
+				Type string `json:"type"`
+			} `json:"tree"`
+			Truncated bool `json:"truncated"`
+		}
+		query := url.Values{
+			"recursive": {"true"},
+			"per_page":  {fmt.Sprint(perPage)},
+			"page":      {fmt.Sprint(page)},
+		}
+		if err := p.api.getJSON(p.repoPath("git", "trees", treeSHA), query, &tree); err != nil {
+			return nil, fmt.Errorf("failed to get tree: %w", err)
+		}
+
+		// Directories are implicit in the file paths
+		for _, entry := range tree.Tree {
+			if entry.Type == "blob" {
+				paths = append(paths, entry.Path)
+			}
+		}
+		if !tree.Truncated || len(tree.Tree) == 0 {
+			break
+		}
+	}
+
+	return treeFromPaths(paths), nil
+}
+
+// ResolveRevision returns the hash of the commit a branch, tag, or commit
+// points to (the current branch if revision is empty). The lookup is
+// revalidated with an ETag, so repeating it for an unchanged branch is cheap.
+func (p *GiteaProvider) ResolveRevision(revision string) (string, error) {
+	commit, err := p.commit(revision)
+	if err != nil {
+		return "", err
+	}
+	return commit.SHA, nil
+}
+
+// giteaCommit is the part of a Gitea commit that GiteaProvider uses.
+type giteaCommit struct {
+	SHA    string `json:"sha"`
+	Commit struct {
+		Tree struct {
+			SHA string `json:"sha"`
+		} `json:"tree"`
+	} `json:"commit"`
+}
+
+// commit looks up the commit a branch, tag, or commit points to.
+func (p *GiteaProvider) commit(revision string) (*giteaCommit, error) {
+	revision = p.revision(revision)
+
+	var commits []giteaCommit
+	query := url.Values{"sha": {revision}, "limit": {"1"}, "stat": {"false"}}
+	if err := p.api.getJSONRevalidated(p.repoPath("commits"), query, &commits); err != nil {
+		if isNotFound(err) || isUnprocessable(err) {
+			return nil, fmt.Errorf("revision '%s' not found", revision)
+		}
+		return nil, fmt.Errorf("failed to resolve revision: %w", err)
+	}
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("revision '%s' not found", revision)
+	}
+	return &commits[0], nil
+}
+
+// FileContent returns the raw bytes of a file on the given branch, tag, or commit.
+func (p *GiteaProvider) FileContent(path, branch string) ([]byte, error) {
+	path, err := normalizeRemotePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{"ref": {p.revision(branch)}}
+	return readContentsFile(p.api, p.repoPath("contents", path), query, func(sha string) string {
+		return p.repoPath("git", "blobs", sha)
+	})
+}
+
+// Branches returns all branches of the repository. The branch being browsed
+// is marked as IsDefault, as HEAD is for a local repository.
+func (p *GiteaProvider) Branches() ([]BranchInfo, error) {
+	const perPage = 50
+
+	current := p.currentBranch()
+
+	var branches []BranchInfo
+	for page := 1; ; page++ {
+		var batch []struct {
+			Name string `json:"name"`
+		}
+		query := url.Values{"limit": {fmt.Sprint(perPage)}, "page": {fmt.Sprint(page)}}
+		if err := p.api.getJSON(p.repoPath("branches"), query, &batch); err != nil {
+			return nil, fmt.Errorf("failed to list branches: %w", err)
+		}
+
+		for _, b := range batch {
+			branches = append(branches, BranchInfo{Name: b.Name, IsDefault: b.Name == current})
+		}
+		if len(batch) < perPage {
+			break
+		}
+	}
+
+	return branches, nil
+}
+
+// Checkout switches the branch being browsed. With no working tree there are
+// no uncommitted changes, so mode has no effect.
+func (p *GiteaProvider) Checkout(branch, mode string) error {
+	return p.checkout(branch, p.checkBranch)
+}
+
+// checkBranch returns an error if the repository has no branch of that name.
+func (p *GiteaProvider) checkBranch(branch string) error {
+	if err := p.api.getJSON(p.repoPath("branches", branch), nil, nil); err != nil {
+		if isNotFound(err) {
+			return fmt.Errorf("branch '%s' not found", branch)
+		}
+		return fmt.Errorf("failed to look up branch: %w", err)
+	}
+	return nil
+}
+
+// repoPath returns the API path of a resource of the repository. Each element
+// may contain slashes, as in file paths and branch names.
+func (p *GiteaProvider) repoPath(elems ...string) string {
+	apiPath := "/repos/" + url.PathEscape(p.owner) + "/" + url.PathEscape(p.repo)
+	for _, elem := range elems {
+		apiPath += "/" + escapePath(elem)
+	}
+	return apiPath
+}
@@ -0,0 +1,386 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// fakeGitea is an httptest stand-in for the parts of the Gitea REST API used
+// by GiteaProvider, serving the repository octo/wiki.
+type fakeGitea struct {
+	branches map[string]map[string]string // branch -> file path -> content
+	token    string                       // required access token; empty allows anonymous requests
+	large    map[string]bool              // files served without content, as blobs
+}
+
+// newFakeGitea starts a fake API with a main and a feature/x branch.
+func newFakeGitea(t *testing.T) (*fakeGitea, *httptest.Server) {
+	t.Helper()
+
+	fake := &fakeGitea{
+		branches: map[string]map[string]string{
+			"main": {
+				"README.md":         "# Wiki",
+				"docs/guide.md":     "guide",
+				"docs/api/index.md": "api",
+			},
+			"feature/x": {
+				"README.md": "# Feature",
+			},
+		},
+		large: map[string]bool{},
+	}
+
+	mux := http.NewServeMux()
+	const repo = "/repos/octo/wiki"
+	mux.HandleFunc("GET "+repo, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{"html_url": "https://gitea.example.com/octo/wiki", "default_branch": "main"})
+	})
+	mux.HandleFunc("GET "+repo+"/branches", func(w http.ResponseWriter, r *http.Request) {
+		var names []string
+		for name := range fake.branches {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		list := []map[string]string{}
+		for _, name := range paginate(r, names) {
+			list = append(list, map[string]string{"name": name})
+		}
+		writeJSON(w, list)
+	})
+	mux.HandleFunc("GET "+repo+"/branches/{name...}", func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := fake.branches[r.PathValue("name")]; !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, map[string]string{"name": r.PathValue("name")})
+	})
+	mux.HandleFunc("GET "+repo+"/commits", func(w http.ResponseWriter, r *http.Request) {
+		ref := r.URL.Query().Get("sha")
+		if _, ok := fake.branches[ref]; !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, []map[string]any{{"sha": "commit-" + ref, "commit": map[string]any{"tree": map[string]string{"sha": treeSHA(ref)}}}})
+	})
+	mux.HandleFunc("GET "+repo+"/git/trees/{sha}", func(w http.ResponseWriter, r *http.Request) {
+		files := fake.branches[strings.ReplaceAll(strings.TrimPrefix(r.PathValue("sha"), "tree-"), "~", "/")]
+		if files == nil || r.URL.Query().Get("recursive") != "true" {
+			notFound(w)
+			return
+		}
+		dirs := map[string]bool{}
+		var paths []string
+		for path := range files {
+			paths = append(paths, path)
+			for dir := parentDir(path); dir != ""; dir = parentDir(dir) {
+				if !dirs[dir] {
+					dirs[dir] = true
+					paths = append(paths, dir)
+				}
+			}
+		}
+		sort.Strings(paths)
+		page := paginate(r, paths)
+		entries := []map[string]string{}
+		for _, path := range page {
+			entryType := "blob"
+			if dirs[path] {
+				entryType = "tree"
+			}
+			entries = append(entries, map[string]string{"path": path, "type": entryType})
+		}
+		truncated := len(page) > 0 && page[len(page)-1] != paths[len(paths)-1]
+		writeJSON(w, map[string]any{"tree": entries, "truncated": truncated})
+	})
+	mux.HandleFunc("GET "+repo+"/contents/{path...}", func(w http.ResponseWriter, r *http.Request) {
+		files := fake.branches[r.URL.Query().Get("ref")]
+		path := r.PathValue("path")
+		if content, ok := files[path]; ok {
+			file := map[string]any{"type": "file", "path": path, "sha": "blob:" + content}
+			if fake.large[path] {
+				file["encoding"] = nil
+				file["content"] = nil
+			} else {
+				file["encoding"] = "base64"
+				file["content"] = wrapBase64(content)
+			}
+			writeJSON(w, file)
+			return
+		}
+		for filePath := range files {
+			if strings.HasPrefix(filePath, path+"/") {
+				writeJSON(w, []map[string]string{{"type": "file", "path": filePath}})
+				return
+			}
+		}
+		notFound(w)
+	})
+	mux.HandleFunc("GET "+repo+"/git/blobs/{sha}", func(w http.ResponseWriter, r *http.Request) {
+		content := strings.TrimPrefix(r.PathValue("sha"), "blob:")
+		writeJSON(w, map[string]string{"encoding": "base64", "content": wrapBase64(content)})
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fake.token != "" && r.Header.Get("Authorization") != "token "+fake.token {
+			// Gitea hides private repositories from unauthenticated requests
+			notFound(w)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return fake, server
+}
+
+func TestNewGiteaProvider(t *testing.T) {
+	_, server := newFakeGitea(t)
+
+	provider, err := NewGiteaProvider(server.URL, "octo", "wiki", "", "")
+	if err != nil {
+		t.Fatalf("NewGiteaProvider failed: %v", err)
+	}
+
+	status, err := provider.Status()
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if status.Branch != "main" || status.Source != "https://gitea.example.com/octo/wiki" || status.IsDirty {
+		t.Errorf("unexpected status %+v", status)
+	}
+
+	// An explicit branch must exist
+	provider, err = NewGiteaProvider(server.URL, "octo", "wiki", "feature/x", "")
+	if err != nil {
+		t.Fatalf("NewGiteaProvider failed: %v", err)
+	}
+	if status, _ := provider.Status(); status.Branch != "feature/x" {
+		t.Errorf("expected branch feature/x, got %s", status.Branch)
+	}
+
+	if _, err := NewGiteaProvider(server.URL, "octo", "wiki", "missing", ""); err == nil || !strings.Contains(err.Error(), "branch 'missing' not found") {
+		t.Errorf("expected branch not found error, got %v", err)
+	}
+	if _, err := NewGiteaProvider(server.URL, "octo", "missing", "", ""); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected repository not found error, got %v", err)
+	}
+}
+
+func TestGiteaProvider_Token(t *testing.T) {
+	fake, server := newFakeGitea(t)
+	fake.token = "secret"
+
+	if _, err := NewGiteaProvider(server.URL, "octo", "wiki", "", ""); err == nil {
+		t.Error("expected private repository to be hidden without a token")
+	}
+
+	provider, err := NewGiteaProvider(server.URL, "octo", "wiki", "", "secret")
+	if err != nil {
+		t.Fatalf("NewGiteaProvider failed: %v", err)
+	}
+	if _, err := provider.FileContent("README.md", ""); err != nil {
+		t.Errorf("FileContent failed with token: %v", err)
+	}
+}
+
+func TestGiteaProvider_Tree(t *testing.T) {
+	fake, server := newFakeGitea(t)
+
+	provider, err := NewGiteaProvider(server.URL, "octo", "wiki", "", "")
+	if err != nil {
+		t.Fatalf("NewGiteaProvider failed: %v", err)
+	}
+
+	tree, err := provider.Tree("")
+	if err != nil {
+		t.Fatalf("Tree failed: %v", err)
+	}
+
+	// Directories first, then files, nested by path
+	if len(tree.Children) != 2 || tree.Children[0].Path != "docs" || !tree.Children[0].IsDir || tree.Children[1].Path != "README.md" {
+		t.Fatalf("unexpected root children %+v", tree.Children)
+	}
+	docs := tree.Children[0]
+	if len(docs.Children) != 2 || docs.Children[0].Path != "docs/api" || docs.Children[1].Path != "docs/guide.md" {
+		t.Errorf("unexpected docs children %+v", docs.Children)
+	}
+
+	// Another branch
+	tree, err = provider.Tree("feature/x")
+	if err != nil {
+		t.Fatalf("Tree failed: %v", err)
+	}
+	if len(tree.Children) != 1 || tree.Children[0].Path != "README.md" {
+		t.Errorf("unexpected feature/x tree %+v", tree.Children)
+	}
+
+	if _, err := provider.Tree("missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected revision not found error, got %v", err)
+	}
+
+	// Truncated trees are read page by page
+	for i := range 1500 {
+		fake.branches["feature/x"][fmt.Sprintf("pages/page-%04d.md", i)] = "page"
+	}
+	tree, err = provider.Tree("feature/x")
+	if err != nil {
+		t.Fatalf("Tree failed: %v", err)
+	}
+	if len(tree.Children) != 2 || len(tree.Children[0].Children) != 1500 {
+		t.Errorf("expected 1500 pages across several API pages, got %d root children", len(tree.Children))
+	}
+}
+
+func TestGiteaProvider_FileContent(t *testing.T) {
+	fake, server := newFakeGitea(t)
+	fake.branches["main"]["big.md"] = strings.Repeat("large file ", 20)
+	fake.large["big.md"] = true
+
+	provider, err := NewGiteaProvider(server.URL, "octo", "wiki", "", "")
+	if err != nil {
+		t.Fatalf("NewGiteaProvider failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		branch   string
+		expected string
+	}{
+		{"current branch", "README.md", "", "# Wiki"},
+		{"nested file", "/docs/guide.md", "", "guide"},
+		{"other branch", "README.md", "feature/x", "# Feature"},
+		{"large file", "big.md", "", strings.Repeat("large file ", 20)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := provider.FileContent(tt.path, tt.branch)
+			if err != nil {
+				t.Fatalf("FileContent failed: %v", err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, content)
+			}
+		})
+	}
+
+	errTests := []struct {
+		name        string
+		path        string
+		errContains string
+	}{
+		{"missing file", "missing.md", "file not found"},
+		{"directory", "docs", "path is a directory"},
+		{"path traversal", "../secret", "cannot contain '..'"},
+		{"empty path", "", "file not found"},
+	}
+
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := provider.FileContent(tt.path, "")
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("expected error containing %q, got %v", tt.errContains, err)
+			}
+		})
+	}
+}
+
+func TestGiteaProvider_BranchesAndCheckout(t *testing.T) {
+	fake, server := newFakeGitea(t)
+
+	provider, err := NewGiteaProvider(server.URL, "octo", "wiki", "", "")
+	if err != nil {
+		t.Fatalf("NewGiteaProvider failed: %v", err)
+	}
+
+	branches, err := provider.Branches()
+	if err != nil {
+		t.Fatalf("Branches failed: %v", err)
+	}
+	if len(branches) != 2 || branches[0].Name != "feature/x" || branches[0].IsDefault || branches[1].Name != "main" || !branches[1].IsDefault {
+		t.Errorf("unexpected branches %+v", branches)
+	}
+
+	// Switching branches changes what the current branch reads
+	if err := provider.Checkout("feature/x", CheckoutClean); err != nil {
+		t.Fatalf("Checkout failed: %v", err)
+	}
+	content, err := provider.FileContent("README.md", "")
+	if err != nil {
+		t.Fatalf("FileContent failed: %v", err)
+	}
+	if string(content) != "# Feature" {
+		t.Errorf("expected feature/x content, got %q", content)
+	}
+
+	// The branch being browsed is marked as the default
+	branches, err = provider.Branches()
+	if err != nil {
+		t.Fatalf("Branches failed: %v", err)
+	}
+	if len(branches) != 2 || !branches[0].IsDefault || branches[1].IsDefault {
+		t.Errorf("expected feature/x to be the current branch, got %+v", branches)
+	}
+
+	if err := provider.Checkout("missing", CheckoutClean); err == nil {
+		t.Error("expected error checking out a missing branch")
+	}
+
+	// Branch lists longer than a page are read in full
+	for i := range 60 {
+		fake.branches[fmt.Sprintf("topic-%02d", i)] = map[string]string{}
+	}
+	branches, err = provider.Branches()
+	if err != nil {
+		t.Fatalf("Branches failed: %v", err)
+	}
+	if len(branches) != 62 {
+		t.Errorf("expected 62 branches, got %d", len(branches))
+	}
+}
+
+func TestGiteaProvider_ResolveRevision(t *testing.T) {
+	_, server := newFakeGitea(t)
+
+	provider, err := NewGiteaProvider(server.URL, "octo", "wiki", "", "")
+	if err != nil {
+		t.Fatalf("NewGiteaProvider failed: %v", err)
+	}
+
+	for branch, expected := range map[string]string{"": "commit-main", "feature/x": "commit-feature/x"} {
+		hash, err := provider.ResolveRevision(branch)
+		if err != nil {
+			t.Fatalf("ResolveRevision(%q) failed: %v", branch, err)
+		}
+		if hash != expected {
+			t.Errorf("ResolveRevision(%q) = %q, want %q", branch, hash, expected)
+		}
+	}
+
+	if _, err := provider.ResolveRevision("missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected revision not found error, got %v", err)
+	}
+}
+
+func TestGiteaProvider_ReadOnly(t *testing.T) {
+	_, server := newFakeGitea(t)
+
+	provider, err := NewGiteaProvider(server.URL, "octo", "wiki", "", "")
+	if err != nil {
+		t.Fatalf("NewGiteaProvider failed: %v", err)
+	}
+
+	if err := provider.WriteFile("a.md", []byte("a")); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly, got %v", err)
+	}
+	if _, err := provider.Commit("message"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly, got %v", err)
+	}
+}
@@ -0,0 +1,244 @@
+package git
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// GitHubAPIURL is the root of the public GitHub REST API.
+const GitHubAPIURL = "https://api.github.com"
+
+// GitHubProvider implements GitProvider by browsing a repository through the
+// GitHub REST API, without cloning it. It is read-only: there is no working
+// tree, so the current branch shows its committed state and every write
+// operation returns ErrReadOnly.
+type GitHubProvider struct {
+	apiRepo
+	owner string
+	repo  string
+}
+
+// NewGitHubProvider creates a provider for the repository owner/repo served by
+// the GitHub API at apiURL (GitHubAPIURL, or a GitHub Enterprise API root).
+// If branch is empty, uses the repository's default branch. A token is needed
+// for private repositories and raises the API rate limit.
+// Returns an error if the repository or branch does not exist.
+func NewGitHubProvider(apiURL, owner, repo, branch, token string) (*GitHubProvider, error) {
+	header := http.Header{}
+	header.Set("Accept", "application/vnd.github+json")
+	header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if token != "" {
+		header.Set("Authorization", "Bearer "+token)
+	}
+
+	p := &GitHubProvider{owner: owner, repo: repo}
+	p.api = newAPIClient(apiURL, header)
+
+	// Look up the repository, which also checks it is accessible
+	var info struct {
+		HTMLURL       string `json:"html_url"`
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := p.api.getJSON(p.repoPath(), nil, &info); err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("repository %s/%s not found", owner, repo)
+		}
+		return nil, fmt.Errorf("failed to load repository: %w", err)
+	}
+	p.source = info.HTMLURL
+	p.defaultBranch = info.DefaultBranch
+
+	if branch == "" {
+		branch = info.DefaultBranch
+	} else if err := p.checkBranch(branch); err != nil {
+		return nil, err
+	}
+	p.branch = branch
+
+	return p, nil
+}
+
+// Tree returns the complete file tree for the given branch, tag, or commit.
+// Very large repositories exceed the API's limit for a single recursive tree;
+// their tree is then read one folder at a time, so it is never incomplete.
+func (p *GitHubProvider) Tree(branch string) (*TreeNode, error) {
+	commit, err := p.commit(branch)
+	if err != nil {
+		return nil, err
+	}
+	treeSHA := commit.Commit.Tree.SHA
+
+	var tree githubTree
+	query := url.Values{"recursive": {"1"}}
+	if err := p.api.getJSON(p.repoPath("git", "trees", treeSHA), query, &tree); err != nil {
+		return nil, fmt.Errorf("failed to get tree: %w", err)
+	}
+
+	if tree.Truncated {
+		paths, err := p.walkTree(treeSHA)
+		if err != nil {
+			return nil, err
+		}
+		return treeFromPaths(paths), nil
+	}
+
+	// Directories are implicit in the file paths
+	var paths []string
+	for _, entry := range tree.Tree {
+		if entry.Type == "blob" {
+			paths = append(paths, entry.Path)
+		}
+	}
+
+	return treeFromPaths(paths), nil
+}
+
+// githubTree is a tree listing from the GitHub API.
+type githubTree struct {
+	Tree []struct {
+		Path string `json:"path"`
+		Type string `json:"type"`
+		SHA  string `json:"sha"`
+	} `json:"tree"`
+	Truncated bool `json:"truncated"`
+}
+
+// walkTree lists the file paths in a tree by reading each folder separately.
+// Fails if even a single folder is too large for the API to list in full.
+func (p *GitHubProvider) walkTree(rootSHA string) ([]string, error) {
+	type folder struct{ path, sha string }
+
+	var paths []string
+	pending := []folder{{"", rootSHA}}
+	for len(pending) > 0 {
+		dir := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		var tree githubTree
+		if err := p.api.getJSON(p.repoPath("git", "trees", dir.sha), nil, &tree); err != nil {
+			return nil, fmt.Errorf("failed to get tree: %w", err)
+		}
+		if tree.Truncated {
+			return nil, fmt.Errorf("failed to get tree: folder '%s' has too many entries for the GitHub API", dir.path)
+		}
+
+		for _, entry := range tree.Tree {
+			path := entry.Path
+			if dir.path != "" {
+				path = dir.path + "/" + entry.Path
+			}
+			switch entry.Type {
+			case "blob":
+				paths = append(paths, path)
+			case "tree":
+				pending = append(pending, folder{path, entry.SHA})
+			}
+		}
+	}
+
+	return paths, nil
+}
+
+// ResolveRevision returns the hash of the commit a branch, tag, or commit
+// points to (the current branch if revision is empty). The lookup is
+// revalidated with an ETag, so repeating it for an unchanged branch is cheap.
+func (p *GitHubProvider) ResolveRevision(revision string) (string, error) {
+	commit, err := p.commit(revision)
+	if err != nil {
+		return "", err
+	}
+	return commit.SHA, nil
+}
+
+// githubCommit is the part of a GitHub commit that GitHubProvider uses.
+type githubCommit struct {
+	SHA    string `json:"sha"`
+	Commit struct {
+		Tree struct {
+			SHA string `json:"sha"`
+		} `json:"tree"`
+	} `json:"commit"`
+}
+
+// commit looks up the commit a branch, tag, or commit points to.
+func (p *GitHubProvider) commit(revision string) (*githubCommit, error) {
+	revision = p.revision(revision)
+
+	var commit githubCommit
+	if err := p.api.getJSONRevalidated(p.repoPath("commits", revision), nil, &commit); err != nil {
+		if isNotFound(err) || isUnprocessable(err) {
+			return nil, fmt.Errorf("revision '%s' not found", revision)
+		}
+		return nil, fmt.Errorf("failed to resolve revision: %w", err)
+	}
+	return &commit, nil
+}
+
+// FileContent returns the raw bytes of a file on the given branch, tag, or commit.
+func (p *GitHubProvider) FileContent(path, branch string) ([]byte, error) {
+	path, err := normalizeRemotePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{"ref": {p.revision(branch)}}
+	return readContentsFile(p.api, p.repoPath("contents", path), query, func(sha string) string {
+		return p.repoPath("git", "blobs", sha)
+	})
+}
+
+// Branches returns all branches of the repository. The branch being browsed
+// is marked as IsDefault, as HEAD is for a local repository.
+func (p *GitHubProvider) Branches() ([]BranchInfo, error) {
+	const perPage = 100
+
+	current := p.currentBranch()
+
+	var branches []BranchInfo
+	for page := 1; ; page++ {
+		var batch []struct {
+			Name string `json:"name"`
+		}
+		query := url.Values{"per_page": {fmt.Sprint(perPage)}, "page": {fmt.Sprint(page)}}
+		if err := p.api.getJSON(p.repoPath("branches"), query, &batch); err != nil {
+			return nil, fmt.Errorf("failed to list branches: %w", err)
+		}
+
+		for _, b := range batch {
+			branches = append(branches, BranchInfo{Name: b.Name, IsDefault: b.Name == current})
+		}
+		if len(batch) < perPage {
+			break
+		}
+	}
+
+	return branches, nil
+}
+
+// Checkout switches the branch being browsed. With no working tree there are
+// no uncommitted changes, so mode has no effect.
+func (p *GitHubProvider) Checkout(branch, mode string) error {
+	return p.checkout(branch, p.checkBranch)
+}
+
+// checkBranch returns an error if the repository has no branch of that name.
+func (p *GitHubProvider) checkBranch(branch string) error {
+	if err := p.api.getJSON(p.repoPath("branches", branch), nil, nil); err != nil {
+		if isNotFound(err) {
+			return fmt.Errorf("branch '%s' not found", branch)
+		}
+		return fmt.Errorf("failed to look up branch: %w", err)
+	}
+	return nil
+}
+
+// repoPath returns the API path of a resource of the repository. Each element
+// may contain slashes, as in file paths and branch names.
+func (p *GitHubProvider) repoPath(elems ...string) string {
+	apiPath := "/repos/" + url.PathEscape(p.owner) + "/" + url.PathEscape(p.repo)
+	for _, elem := range elems {
+		apiPath += "/" + escapePath(elem)
+	}
+	return apiPath
+}
@@ -0,0 +1,592 @@
+package git
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeGitHub is an httptest stand-in for the parts of the GitHub REST API
+// used by GitHubProvider, serving the repository octo/wiki.
+type fakeGitHub struct {
+	branches map[string]map[string]string // branch -> file path -> content
+	token    string                       // required bearer token; empty allows anonymous requests
+	large    map[string]bool              // files served as blobs, like GitHub's files over 1 MB
+	truncate bool                         // truncate recursive trees, like GitHub for very large repositories
+
+	mu          sync.Mutex
+	pushes      map[string]int // branch -> number of pushes, which changes its commit hash
+	requests    map[string]int // endpoint -> number of requests served in full
+	notModified int            // number of 304 responses to revalidated requests
+	rateLimited atomic.Bool    // refuse requests as if the rate limit were used up
+}
+
+// commitSHA makes a fake commit hash for the current state of a branch.
+func (f *fakeGitHub) commitSHA(branch string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return fmt.Sprintf("commit-%s-%d", strings.ReplaceAll(branch, "/", "~"), f.pushes[branch])
+}
+
+// push changes the commit hash of a branch, as a push to it would.
+func (f *fakeGitHub) push(branch string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pushes[branch]++
+}
+
+// branchOf resolves a branch name or fake commit hash to its branch.
+func (f *fakeGitHub) branchOf(ref string) (string, bool) {
+	if rest, ok := strings.CutPrefix(ref, "commit-"); ok {
+		ref = strings.ReplaceAll(rest[:strings.LastIndex(rest, "-")], "~", "/")
+	}
+	_, ok := f.branches[ref]
+	return ref, ok
+}
+
+// count records a request served in full by an endpoint.
+func (f *fakeGitHub) count(endpoint string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests[endpoint]++
+}
+
+// served returns the number of requests served in full by an endpoint.
+func (f *fakeGitHub) served(endpoint string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[endpoint]
+}
+
+// revalidated returns the number of 304 responses to revalidated requests.
+func (f *fakeGitHub) revalidated() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.notModified
+}
+
+// newFakeGitHub starts a fake API with a main and a feature/x branch.
+func newFakeGitHub(t *testing.T) (*fakeGitHub, *httptest.Server) {
+	t.Helper()
+
+	fake := &fakeGitHub{
+		branches: map[string]map[string]string{
+			"main": {
+				"README.md":         "# Wiki",
+				"docs/guide.md":     "guide",
+				"docs/api/index.md": "api",
+			},
+			"feature/x": {
+				"README.md": "# Feature",
+			},
+		},
+		large:    map[string]bool{},
+		pushes:   map[string]int{},
+		requests: map[string]int{},
+	}
+
+	mux := http.NewServeMux()
+	const repo = "/repos/octo/wiki"
+	mux.HandleFunc("GET "+repo, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{"html_url": "https://github.com/octo/wiki", "default_branch": "main"})
+	})
+	mux.HandleFunc("GET "+repo+"/branches", func(w http.ResponseWriter, r *http.Request) {
+		var names []string
+		for name := range fake.branches {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if r.URL.Query().Get("page") != "1" {
+			names = nil
+		}
+		list := []map[string]string{}
+		for _, name := range names {
+			list = append(list, map[string]string{"name": name})
+		}
+		writeJSON(w, list)
+	})
+	mux.HandleFunc("GET "+repo+"/branches/{name...}", func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := fake.branches[r.PathValue("name")]; !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, map[string]string{"name": r.PathValue("name")})
+	})
+	mux.HandleFunc("GET "+repo+"/commits/{ref...}", func(w http.ResponseWriter, r *http.Request) {
+		branch, ok := fake.branchOf(r.PathValue("ref"))
+		if !ok {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			writeJSON(w, map[string]string{"message": "No commit found for SHA: " + r.PathValue("ref")})
+			return
+		}
+		sha := fake.commitSHA(branch)
+		etag := `"` + sha + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			fake.mu.Lock()
+			fake.notModified++
+			fake.mu.Unlock()
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fake.count("commits")
+		w.Header().Set("ETag", etag)
+		writeJSON(w, map[string]any{"sha": sha, "commit": map[string]any{"tree": map[string]string{"sha": treeSHA(branch)}}})
+	})
+	mux.HandleFunc("GET "+repo+"/git/trees/{sha}", func(w http.ResponseWriter, r *http.Request) {
+		fake.count("trees")
+
+		// Tree hashes are treeSHA(branch), plus "@" and the folder for subtrees
+		branch, dir, _ := strings.Cut(strings.TrimPrefix(r.PathValue("sha"), "tree-"), "@")
+		branch = strings.ReplaceAll(branch, "~", "/")
+		dir = strings.ReplaceAll(dir, "~", "/")
+		files := fake.branches[branch]
+		if files == nil {
+			notFound(w)
+			return
+		}
+
+		entries := []map[string]string{}
+		if r.URL.Query().Get("recursive") != "1" {
+			// Only the folder's own entries
+			prefix := ""
+			if dir != "" {
+				prefix = dir + "/"
+			}
+			subdirs := map[string]bool{}
+			for path := range files {
+				rest, ok := strings.CutPrefix(path, prefix)
+				if !ok {
+					continue
+				}
+				if name, _, isDir := strings.Cut(rest, "/"); isDir {
+					subdirs[name] = true
+				} else {
+					entries = append(entries, map[string]string{"path": name, "type": "blob", "sha": "blob:" + files[path]})
+				}
+			}
+			for name := range subdirs {
+				sha := treeSHA(branch) + "@" + strings.ReplaceAll(prefix+name, "/", "~")
+				entries = append(entries, map[string]string{"path": name, "type": "tree", "sha": sha})
+			}
+			writeJSON(w, map[string]any{"tree": entries, "truncated": false})
+			return
+		}
+
+		dirs := map[string]bool{}
+		for path := range files {
+			entries = append(entries, map[string]string{"path": path, "type": "blob"})
+			for dir := parentDir(path); dir != ""; dir = parentDir(dir) {
+				dirs[dir] = true
+			}
+		}
+		for dir := range dirs {
+			entries = append(entries, map[string]string{"path": dir, "type": "tree"})
+		}
+		if fake.truncate {
+			writeJSON(w, map[string]any{"tree": entries[:1], "truncated": true})
+			return
+		}
+		writeJSON(w, map[string]any{"tree": entries, "truncated": false})
+	})
+	mux.HandleFunc("GET "+repo+"/contents/{path...}", func(w http.ResponseWriter, r *http.Request) {
+		fake.count("contents")
+		branch, _ := fake.branchOf(r.URL.Query().Get("ref"))
+		files := fake.branches[branch]
+		path := r.PathValue("path")
+		if content, ok := files[path]; ok {
+			file := map[string]string{"type": "file", "path": path, "sha": "blob:" + content}
+			if fake.large[path] {
+				file["encoding"] = "none"
+			} else {
+				file["encoding"] = "base64"
+				file["content"] = wrapBase64(content)
+			}
+			writeJSON(w, file)
+			return
+		}
+		for filePath := range files {
+			if strings.HasPrefix(filePath, path+"/") {
+				writeJSON(w, []map[string]string{{"type": "file", "path": filePath}})
+				return
+			}
+		}
+		notFound(w)
+	})
+	mux.HandleFunc("GET "+repo+"/git/blobs/{sha}", func(w http.ResponseWriter, r *http.Request) {
+		content := strings.TrimPrefix(r.PathValue("sha"), "blob:")
+		writeJSON(w, map[string]string{"encoding": "base64", "content": wrapBase64(content)})
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fake.token != "" && r.Header.Get("Authorization") != "Bearer "+fake.token {
+			// GitHub hides private repositories from unauthenticated requests
+			notFound(w)
+			return
+		}
+		if fake.rateLimited.Load() {
+			w.Header().Set("X-RateLimit-Limit", "60")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", fmt.Sprint(time.Now().Add(10*time.Minute).Unix()))
+			w.WriteHeader(http.StatusForbidden)
+			writeJSON(w, map[string]string{"message": "API rate limit exceeded for 127.0.0.1."})
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return fake, server
+}
+
+// treeSHA makes a fake tree hash for a branch; like real hashes, it has no slashes.
+func treeSHA(branch string) string {
+	return "tree-" + strings.ReplaceAll(branch, "/", "~")
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func notFound(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNotFound)
+	writeJSON(w, map[string]string{"message": "Not Found"})
+}
+
+// wrapBase64 encodes content the way the GitHub API does, in 60-character lines.
+func wrapBase64(content string) string {
+	encoded := base64.StdEncoding.EncodeToString([]byte(content))
+	var lines []string
+	for len(encoded) > 60 {
+		lines = append(lines, encoded[:60])
+		encoded = encoded[60:]
+	}
+	return strings.Join(append(lines, encoded), "\n") + "\n"
+}
+
+func TestNewGitHubProvider(t *testing.T) {
+	_, server := newFakeGitHub(t)
+
+	provider, err := NewGitHubProvider(server.URL, "octo", "wiki", "", "")
+	if err != nil {
+		t.Fatalf("NewGitHubProvider failed: %v", err)
+	}
+
+	status, err := provider.Status()
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if status.Branch != "main" || status.Source != "https://github.com/octo/wiki" || status.IsDirty {
+		t.Errorf("unexpected status %+v", status)
+	}
+
+	// An explicit branch must exist
+	provider, err = NewGitHubProvider(server.URL, "octo", "wiki", "feature/x", "")
+	if err != nil {
+		t.Fatalf("NewGitHubProvider failed: %v", err)
+	}
+	if status, _ := provider.Status(); status.Branch != "feature/x" {
+		t.Errorf("expected branch feature/x, got %s", status.Branch)
+	}
+
+	if _, err := NewGitHubProvider(server.URL, "octo", "wiki", "missing", ""); err == nil || !strings.Contains(err.Error(), "branch 'missing' not found") {
+		t.Errorf("expected branch not found error, got %v", err)
+	}
+	if _, err := NewGitHubProvider(server.URL, "octo", "missing", "", ""); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected repository not found error, got %v", err)
+	}
+}
+
+func TestGitHubProvider_Token(t *testing.T) {
+	fake, server := newFakeGitHub(t)
+	fake.token = "secret"
+
+	if _, err := NewGitHubProvider(server.URL, "octo", "wiki", "", ""); err == nil {
+		t.Error("expected private repository to be hidden without a token")
+	}
+
+	provider, err := NewGitHubProvider(server.URL, "octo", "wiki", "", "secret")
+	if err != nil {
+		t.Fatalf("NewGitHubProvider failed: %v", err)
+	}
+	if _, err := provider.FileContent("README.md", ""); err != nil {
+		t.Errorf("FileContent failed with token: %v", err)
+	}
+}
+
+func TestGitHubProvider_Tree(t *testing.T) {
+	_, server := newFakeGitHub(t)
+
+	provider, err := NewGitHubProvider(server.URL, "octo", "wiki", "", "")
+	if err != nil {
+		t.Fatalf("NewGitHubProvider failed: %v", err)
+	}
+
+	tree, err := provider.Tree("")
+	if err != nil {
+		t.Fatalf("Tree failed: %v", err)
+	}
+
+	// Directories first, then files, nested by path
+	if len(tree.Children) != 2 || tree.Children[0].Path != "docs" || !tree.Children[0].IsDir || tree.Children[1].Path != "README.md" {
+		t.Fatalf("unexpected root children %+v", tree.Children)
+	}
+	docs := tree.Children[0]
+	if len(docs.Children) != 2 || docs.Children[0].Path != "docs/api" || docs.Children[1].Path != "docs/guide.md" {
+		t.Errorf("unexpected docs children %+v", docs.Children)
+	}
+	if api := docs.Children[0]; len(api.Children) != 1 || api.Children[0].Path != "docs/api/index.md" || api.Children[0].IsDir {
+		t.Errorf("unexpected docs/api children %+v", api.Children)
+	}
+
+	// Another branch
+	tree, err = provider.Tree("feature/x")
+	if err != nil {
+		t.Fatalf("Tree failed: %v", err)
+	}
+	if len(tree.Children) != 1 || tree.Children[0].Path != "README.md" {
+		t.Errorf("unexpected feature/x tree %+v", tree.Children)
+	}
+
+	if _, err := provider.Tree("missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected revision not found error, got %v", err)
+	}
+}
+
+func TestGitHubProvider_TruncatedTree(t *testing.T) {
+	fake, server := newFakeGitHub(t)
+	fake.truncate = true
+
+	provider, err := NewGitHubProvider(server.URL, "octo", "wiki", "", "")
+	if err != nil {
+		t.Fatalf("NewGitHubProvider failed: %v", err)
+	}
+
+	// The tree is read folder by folder instead of served incomplete
+	tree, err := provider.Tree("")
+	if err != nil {
+		t.Fatalf("Tree failed: %v", err)
+	}
+
+	var paths []string
+	var collect func(node TreeNode)
+	collect = func(node TreeNode) {
+		if !node.IsDir {
+			paths = append(paths, node.Path)
+		}
+		for _, child := range node.Children {
+			collect(child)
+		}
+	}
+	collect(*tree)
+	sort.Strings(paths)
+
+	expected := []string{"README.md", "docs/api/index.md", "docs/guide.md"}
+	if strings.Join(paths, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected files %v, got %v", expected, paths)
+	}
+}
+
+func TestGitHubProvider_FileContent(t *testing.T) {
+	fake, server := newFakeGitHub(t)
+	fake.branches["main"]["big.md"] = strings.Repeat("large file ", 20)
+	fake.large["big.md"] = true
+
+	provider, err := NewGitHubProvider(server.URL, "octo", "wiki", "", "")
+	if err != nil {
+		t.Fatalf("NewGitHubProvider failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		branch   string
+		expected string
+	}{
+		{"current branch", "README.md", "", "# Wiki"},
+		{"nested file", "/docs/guide.md", "", "guide"},
+		{"other branch", "README.md", "feature/x", "# Feature"},
+		{"large file", "big.md", "", strings.Repeat("large file ", 20)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := provider.FileContent(tt.path, tt.branch)
+			if err != nil {
+				t.Fatalf("FileContent failed: %v", err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, content)
+			}
+		})
+	}
+
+	errTests := []struct {
+		name        string
+		path        string
+		errContains string
+	}{
+		{"missing file", "missing.md", "file not found"},
+		{"directory", "docs", "path is a directory"},
+		{"path traversal", "../secret", "cannot contain '..'"},
+		{"empty path", "", "file not found"},
+	}
+
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := provider.FileContent(tt.path, "")
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("expected error containing %q, got %v", tt.errContains, err)
+			}
+		})
+	}
+}
+
+func TestGitHubProvider_BranchesAndCheckout(t *testing.T) {
+	_, server := newFakeGitHub(t)
+
+	provider, err := NewGitHubProvider(server.URL, "octo", "wiki", "", "")
+	if err != nil {
+		t.Fatalf("NewGitHubProvider failed: %v", err)
+	}
+
+	branches, err := provider.Branches()
+	if err != nil {
+		t.Fatalf("Branches failed: %v", err)
+	}
+	if len(branches) != 2 || branches[0].Name != "feature/x" || branches[0].IsDefault || branches[1].Name != "main" || !branches[1].IsDefault {
+		t.Errorf("unexpected branches %+v", branches)
+	}
+
+	// Switching branches changes what the current branch reads
+	if err := provider.Checkout("feature/x", CheckoutClean); err != nil {
+		t.Fatalf("Checkout failed: %v", err)
+	}
+	content, err := provider.FileContent("README.md", "")
+	if err != nil {
+		t.Fatalf("FileContent failed: %v", err)
+	}
+	if string(content) != "# Feature" {
+		t.Errorf("expected feature/x content, got %q", content)
+	}
+
+	// The branch being browsed is marked as the default
+	branches, err = provider.Branches()
+	if err != nil {
+		t.Fatalf("Branches failed: %v", err)
+	}
+	if len(branches) != 2 || !branches[0].IsDefault || branches[1].IsDefault {
+		t.Errorf("expected feature/x to be the current branch, got %+v", branches)
+	}
+
+	if err := provider.Checkout("missing", CheckoutClean); err == nil {
+		t.Error("expected error checking out a missing branch")
+	}
+}
+
+func TestGitHubProvider_ReadOnly(t *testing.T) {
+	_, server := newFakeGitHub(t)
+
+	provider, err := NewGitHubProvider(server.URL, "octo", "wiki", "", "")
+	if err != nil {
+		t.Fatalf("NewGitHubProvider failed: %v", err)
+	}
+
+	var gp GitProvider = provider
+	_, commitErr := gp.Commit("message")
+	_, changeSetErr := gp.ApplyChangeSet([]ChangeOp{{Op: OpWrite, Path: "a.md"}}, "")
+	for name, err := range map[string]error{
+		"WriteFile":      gp.WriteFile("a.md", []byte("a")),
+		"DeleteFile":     gp.DeleteFile("README.md"),
+		"MoveFile":       gp.MoveFile("README.md", "b.md"),
+		"MoveFolder":     gp.MoveFolder("docs", "guides"),
+		"CreateFolder":   gp.CreateFolder("new"),
+		"DeleteFolder":   gp.DeleteFolder("docs", true),
+		"CreateBranch":   gp.CreateBranch("new", ""),
+		"Push":           gp.Push("", ""),
+		"Commit":         commitErr,
+		"ApplyChangeSet": changeSetErr,
+	} {
+		if !errors.Is(err, ErrReadOnly) {
+			t.Errorf("%s: expected ErrReadOnly, got %v", name, err)
+		}
+	}
+
+	// There is never anything uncommitted
+	diffs, err := provider.Diff()
+	if err != nil || len(diffs) != 0 {
+		t.Errorf("expected no diffs, got %v, %v", diffs, err)
+	}
+}
+
+func TestGitHubProvider_RateLimit(t *testing.T) {
+	fake, server := newFakeGitHub(t)
+
+	provider, err := NewGitHubProvider(server.URL, "octo", "wiki", "", "")
+	if err != nil {
+		t.Fatalf("NewGitHubProvider failed: %v", err)
+	}
+
+	fake.rateLimited.Store(true)
+	_, err = provider.FileContent("README.md", "")
+
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) {
+		t.Fatalf("expected RateLimitError, got %v", err)
+	}
+	if rateErr.Limit != 60 {
+		t.Errorf("expected limit 60, got %d", rateErr.Limit)
+	}
+	if retry := rateErr.RetryAfter(); retry < 9*time.Minute || retry > 10*time.Minute {
+		t.Errorf("expected reset in about 10 minutes, got %v", retry)
+	}
+
+	// A plain 403 is not a rate limit
+	if err := rateLimitError(&http.Response{StatusCode: http.StatusForbidden, Header: http.Header{}}); err != nil {
+		t.Errorf("expected no rate limit error for a plain 403, got %v", err)
+	}
+}
+
+func TestGitHubProvider_ResolveRevision(t *testing.T) {
+	fake, server := newFakeGitHub(t)
+
+	provider, err := NewGitHubProvider(server.URL, "octo", "wiki", "", "")
+	if err != nil {
+		t.Fatalf("NewGitHubProvider failed: %v", err)
+	}
+
+	hash, err := provider.ResolveRevision("")
+	if err != nil {
+		t.Fatalf("ResolveRevision failed: %v", err)
+	}
+	if hash != fake.commitSHA("main") {
+		t.Errorf("expected %s, got %s", fake.commitSHA("main"), hash)
+	}
+
+	// Resolving again revalidates the remembered response
+	if _, err := provider.ResolveRevision(""); err != nil {
+		t.Fatalf("ResolveRevision failed: %v", err)
+	}
+	if fake.served("commits") != 1 || fake.revalidated() != 1 {
+		t.Errorf("expected 1 full and 1 revalidated request, got %d and %d", fake.served("commits"), fake.revalidated())
+	}
+
+	// A push is picked up
+	fake.push("main")
+	if hash, _ := provider.ResolveRevision(""); hash != fake.commitSHA("main") {
+		t.Errorf("expected %s after push, got %s", fake.commitSHA("main"), hash)
+	}
+
+	if _, err := provider.ResolveRevision("missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected revision not found error, got %v", err)
+	}
+}
@@ -0,0 +1,190 @@
+package git
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// GitLabAPIURL is the root of the gitlab.com REST API.
+const GitLabAPIURL = "https://gitlab.com/api/v4"
+
+// GitLabProvider implements GitProvider by browsing a repository through the
+// GitLab REST API, without cloning it. Like GitHubProvider it is read-only.
+type GitLabProvider struct {
+	apiRepo
+	project string // full path of the project, e.g. "group/subgroup/repo"
+}
+
+// NewGitLabProvider creates a provider for a project served by the GitLab API
+// at apiURL (GitLabAPIURL, or the /api/v4 root of a self-hosted instance).
+// project is the project's full path, which may include subgroups.
+// If branch is empty, uses the project's default branch. A token is needed
+// for private projects.
+// Returns an error if the project or branch does not exist.
+func NewGitLabProvider(apiURL, project, branch, token string) (*GitLabProvider, error) {
+	header := http.Header{}
+	if token != "" {
+		header.Set("PRIVATE-TOKEN", token)
+	}
+
+	p := &GitLabProvider{project: project}
+	p.api = newAPIClient(apiURL, header)
+
+	// Look up the project, which also checks it is accessible
+	var info struct {
+		WebURL        string `json:"web_url"`
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := p.api.getJSON(p.projectPath(), nil, &info); err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("project %s not found", project)
+		}
+		return nil, fmt.Errorf("failed to load project: %w", err)
+	}
+	p.source = info.WebURL
+	p.defaultBranch = info.DefaultBranch
+
+	if branch == "" {
+		branch = info.DefaultBranch
+	} else if err := p.checkBranch(branch); err != nil {
+		return nil, err
+	}
+	p.branch = branch
+
+	return p, nil
+}
+
+// Tree returns the complete file tree for the given branch, tag, or commit.
+func (p *GitLabProvider) Tree(branch string) (*TreeNode, error) {
+	const perPage = 100
+	branch = p.revision(branch)
+
+	var paths []string
+	for page := 1; ; page++ {
+		var batch []struct {
+			Path string `json:"path"`
+			Type string `json:"type"`
+		}
+		query := url.Values{
+			"ref":       {branch},
+			"recursive": {"true"},
+			"per_page":  {fmt.Sprint(perPage)},
+			"page":      {fmt.Sprint(page)},
+		}
+		if err := p.api.getJSON(p.projectPath("repository", "tree"), query, &batch); err != nil {
+			if isNotFound(err) {
+				return nil, fmt.Errorf("revision '%s' not found", branch)
+			}
+			return nil, fmt.Errorf("failed to get tree: %w", err)
+		}
+
+		// Directories are implicit in the file paths
+		for _, entry := range batch {
+			if entry.Type == "blob" {
+				paths = append(paths, entry.Path)
+			}
+		}
+		if len(batch) < perPage {
+			break
+		}
+	}
+
+	return treeFromPaths(paths), nil
+}
+
+// ResolveRevision returns the hash of the commit a branch, tag, or commit
+// points to (the current branch if revision is empty). The lookup is
+// revalidated with an ETag, so repeating it for an unchanged branch is cheap.
+func (p *GitLabProvider) ResolveRevision(revision string) (string, error) {
+	revision = p.revision(revision)
+
+	var commit struct {
+		ID string `json:"id"`
+	}
+	if err := p.api.getJSONRevalidated(p.projectPath("repository", "commits", revision), nil, &commit); err != nil {
+		if isNotFound(err) {
+			return "", fmt.Errorf("revision '%s' not found", revision)
+		}
+		return "", fmt.Errorf("failed to resolve revision: %w", err)
+	}
+	return commit.ID, nil
+}
+
+// FileContent returns the raw bytes of a file on the given branch, tag, or commit.
+func (p *GitLabProvider) FileContent(path, branch string) ([]byte, error) {
+	path, err := normalizeRemotePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file struct {
+		Encoding string `json:"encoding"`
+		Content  string `json:"content"`
+	}
+	query := url.Values{"ref": {p.revision(branch)}}
+	if err := p.api.getJSON(p.projectPath("repository", "files", path), query, &file); err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("file not found")
+		}
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	return decodeBase64Content(file.Encoding, file.Content)
+}
+
+// Branches returns all branches of the repository. The branch being browsed
+// is marked as IsDefault, as HEAD is for a local repository.
+func (p *GitLabProvider) Branches() ([]BranchInfo, error) {
+	const perPage = 100
+
+	current := p.currentBranch()
+
+	var branches []BranchInfo
+	for page := 1; ; page++ {
+		var batch []struct {
+			Name string `json:"name"`
+		}
+		query := url.Values{"per_page": {fmt.Sprint(perPage)}, "page": {fmt.Sprint(page)}}
+		if err := p.api.getJSON(p.projectPath("repository", "branches"), query, &batch); err != nil {
+			return nil, fmt.Errorf("failed to list branches: %w", err)
+		}
+
+		for _, b := range batch {
+			branches = append(branches, BranchInfo{Name: b.Name, IsDefault: b.Name == current})
+		}
+		if len(batch) < perPage {
+			break
+		}
+	}
+
+	return branches, nil
+}
+
+// Checkout switches the branch being browsed. With no working tree there are
+// no uncommitted changes, so mode has no effect.
+func (p *GitLabProvider) Checkout(branch, mode string) error {
+	return p.checkout(branch, p.checkBranch)
+}
+
+// checkBranch returns an error if the project has no branch of that name.
+func (p *GitLabProvider) checkBranch(branch string) error {
+	if err := p.api.getJSON(p.projectPath("repository", "branches", branch), nil, nil); err != nil {
+		if isNotFound(err) {
+			return fmt.Errorf("branch '%s' not found", branch)
+		}
+		return fmt.Errorf("failed to look up branch: %w", err)
+	}
+	return nil
+}
+
+// projectPath returns the API path of a resource of the project. Unlike
+// GitHub's, GitLab's API expects the project path, file paths and branch
+// names each as a single URL-encoded segment, slashes included.
+func (p *GitLabProvider) projectPath(elems ...string) string {
+	apiPath := "/projects/" + url.PathEscape(p.project)
+	for _, elem := range elems {
+		apiPath += "/" + url.PathEscape(elem)
+	}
+	return apiPath
+}
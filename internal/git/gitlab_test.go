@@ -0,0 +1,379 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fakeGitLab is an httptest stand-in for the parts of the GitLab REST API
+// used by GitLabProvider, serving the project group/wiki.
+type fakeGitLab struct {
+	branches map[string]map[string]string // branch -> file path -> content
+	token    string                       // required private token; empty allows anonymous requests
+}
+
+// newFakeGitLab starts a fake API with a main and a feature/x branch.
+func newFakeGitLab(t *testing.T) (*fakeGitLab, *httptest.Server) {
+	t.Helper()
+
+	fake := &fakeGitLab{
+		branches: map[string]map[string]string{
+			"main": {
+				"README.md":         "# Wiki",
+				"docs/guide.md":     "guide",
+				"docs/api/index.md": "api",
+			},
+			"feature/x": {
+				"README.md": "# Feature",
+			},
+		},
+	}
+
+	// GitLab takes the project path, file paths and branch names as single
+	// encoded segments, which the mux decodes into path values
+	mux := http.NewServeMux()
+	project := func(h http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.PathValue("id") != "group/wiki" {
+				notFound(w)
+				return
+			}
+			h(w, r)
+		}
+	}
+	mux.HandleFunc("GET /projects/{id}", project(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{"web_url": "https://gitlab.example.com/group/wiki", "default_branch": "main"})
+	}))
+	mux.HandleFunc("GET /projects/{id}/repository/branches", project(func(w http.ResponseWriter, r *http.Request) {
+		var names []string
+		for name := range fake.branches {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		list := []map[string]string{}
+		for _, name := range paginate(r, names) {
+			list = append(list, map[string]string{"name": name})
+		}
+		writeJSON(w, list)
+	}))
+	mux.HandleFunc("GET /projects/{id}/repository/branches/{name}", project(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := fake.branches[r.PathValue("name")]; !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, map[string]string{"name": r.PathValue("name")})
+	}))
+	mux.HandleFunc("GET /projects/{id}/repository/commits/{ref}", project(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := fake.branches[r.PathValue("ref")]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			writeJSON(w, map[string]string{"message": "404 Commit Not Found"})
+			return
+		}
+		writeJSON(w, map[string]string{"id": "commit-" + r.PathValue("ref")})
+	}))
+	mux.HandleFunc("GET /projects/{id}/repository/tree", project(func(w http.ResponseWriter, r *http.Request) {
+		files := fake.branches[r.URL.Query().Get("ref")]
+		if files == nil || r.URL.Query().Get("recursive") != "true" {
+			w.WriteHeader(http.StatusNotFound)
+			writeJSON(w, map[string]string{"message": "404 Tree Not Found"})
+			return
+		}
+		dirs := map[string]bool{}
+		var paths []string
+		for path := range files {
+			paths = append(paths, path)
+			for dir := parentDir(path); dir != ""; dir = parentDir(dir) {
+				if !dirs[dir] {
+					dirs[dir] = true
+					paths = append(paths, dir)
+				}
+			}
+		}
+		sort.Strings(paths)
+		entries := []map[string]string{}
+		for _, path := range paginate(r, paths) {
+			entryType := "blob"
+			if dirs[path] {
+				entryType = "tree"
+			}
+			entries = append(entries, map[string]string{"path": path, "type": entryType})
+		}
+		writeJSON(w, entries)
+	}))
+	mux.HandleFunc("GET /projects/{id}/repository/files/{path}", project(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := fake.branches[r.URL.Query().Get("ref")][r.PathValue("path")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			writeJSON(w, map[string]string{"message": "404 File Not Found"})
+			return
+		}
+		writeJSON(w, map[string]string{"file_path": r.PathValue("path"), "encoding": "base64", "content": wrapBase64(content)})
+	}))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fake.token != "" && r.Header.Get("PRIVATE-TOKEN") != fake.token {
+			// GitLab hides private projects from unauthenticated requests
+			notFound(w)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return fake, server
+}
+
+// paginate returns the page of items selected by the request's page and
+// per_page (or Gitea's limit) query parameters, defaulting to 20 per page.
+func paginate(r *http.Request, items []string) []string {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if limit, _ := strconv.Atoi(r.URL.Query().Get("limit")); limit > 0 {
+		perPage = limit
+	}
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = 20
+	}
+	start := min((page-1)*perPage, len(items))
+	return items[start:min(start+perPage, len(items))]
+}
+
+func TestNewGitLabProvider(t *testing.T) {
+	_, server := newFakeGitLab(t)
+
+	provider, err := NewGitLabProvider(server.URL, "group/wiki", "", "")
+	if err != nil {
+		t.Fatalf("NewGitLabProvider failed: %v", err)
+	}
+
+	status, err := provider.Status()
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if status.Branch != "main" || status.Source != "https://gitlab.example.com/group/wiki" || status.IsDirty {
+		t.Errorf("unexpected status %+v", status)
+	}
+
+	// An explicit branch must exist
+	provider, err = NewGitLabProvider(server.URL, "group/wiki", "feature/x", "")
+	if err != nil {
+		t.Fatalf("NewGitLabProvider failed: %v", err)
+	}
+	if status, _ := provider.Status(); status.Branch != "feature/x" {
+		t.Errorf("expected branch feature/x, got %s", status.Branch)
+	}
+
+	if _, err := NewGitLabProvider(server.URL, "group/wiki", "missing", ""); err == nil || !strings.Contains(err.Error(), "branch 'missing' not found") {
+		t.Errorf("expected branch not found error, got %v", err)
+	}
+	if _, err := NewGitLabProvider(server.URL, "group/missing", "", ""); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected project not found error, got %v", err)
+	}
+}
+
+func TestGitLabProvider_Token(t *testing.T) {
+	fake, server := newFakeGitLab(t)
+	fake.token = "secret"
+
+	if _, err := NewGitLabProvider(server.URL, "group/wiki", "", ""); err == nil {
+		t.Error("expected private project to be hidden without a token")
+	}
+
+	provider, err := NewGitLabProvider(server.URL, "group/wiki", "", "secret")
+	if err != nil {
+		t.Fatalf("NewGitLabProvider failed: %v", err)
+	}
+	if _, err := provider.FileContent("README.md", ""); err != nil {
+		t.Errorf("FileContent failed with token: %v", err)
+	}
+}
+
+func TestGitLabProvider_Tree(t *testing.T) {
+	fake, server := newFakeGitLab(t)
+
+	provider, err := NewGitLabProvider(server.URL, "group/wiki", "", "")
+	if err != nil {
+		t.Fatalf("NewGitLabProvider failed: %v", err)
+	}
+
+	tree, err := provider.Tree("")
+	if err != nil {
+		t.Fatalf("Tree failed: %v", err)
+	}
+
+	// Directories first, then files, nested by path
+	if len(tree.Children) != 2 || tree.Children[0].Path != "docs" || !tree.Children[0].IsDir || tree.Children[1].Path != "README.md" {
+		t.Fatalf("unexpected root children %+v", tree.Children)
+	}
+	docs := tree.Children[0]
+	if len(docs.Children) != 2 || docs.Children[0].Path != "docs/api" || docs.Children[1].Path != "docs/guide.md" {
+		t.Errorf("unexpected docs children %+v", docs.Children)
+	}
+
+	// Another branch
+	tree, err = provider.Tree("feature/x")
+	if err != nil {
+		t.Fatalf("Tree failed: %v", err)
+	}
+	if len(tree.Children) != 1 || tree.Children[0].Path != "README.md" {
+		t.Errorf("unexpected feature/x tree %+v", tree.Children)
+	}
+
+	if _, err := provider.Tree("missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected revision not found error, got %v", err)
+	}
+
+	// Trees larger than a page are read in full
+	for i := range 250 {
+		fake.branches["feature/x"][fmt.Sprintf("pages/page-%03d.md", i)] = "page"
+	}
+	tree, err = provider.Tree("feature/x")
+	if err != nil {
+		t.Fatalf("Tree failed: %v", err)
+	}
+	if len(tree.Children) != 2 || len(tree.Children[0].Children) != 250 {
+		t.Errorf("expected 250 pages across several API pages, got %+v", tree.Children)
+	}
+}
+
+func TestGitLabProvider_FileContent(t *testing.T) {
+	_, server := newFakeGitLab(t)
+
+	provider, err := NewGitLabProvider(server.URL, "group/wiki", "", "")
+	if err != nil {
+		t.Fatalf("NewGitLabProvider failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		branch   string
+		expected string
+	}{
+		{"current branch", "README.md", "", "# Wiki"},
+		{"nested file", "/docs/guide.md", "", "guide"},
+		{"other branch", "README.md", "feature/x", "# Feature"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := provider.FileContent(tt.path, tt.branch)
+			if err != nil {
+				t.Fatalf("FileContent failed: %v", err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, content)
+			}
+		})
+	}
+
+	errTests := []struct {
+		name        string
+		path        string
+		errContains string
+	}{
+		{"missing file", "missing.md", "file not found"},
+		{"directory", "docs", "file not found"},
+		{"path traversal", "../secret", "cannot contain '..'"},
+		{"empty path", "", "file not found"},
+	}
+
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := provider.FileContent(tt.path, "")
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("expected error containing %q, got %v", tt.errContains, err)
+			}
+		})
+	}
+}
+
+func TestGitLabProvider_BranchesAndCheckout(t *testing.T) {
+	_, server := newFakeGitLab(t)
+
+	provider, err := NewGitLabProvider(server.URL, "group/wiki", "", "")
+	if err != nil {
+		t.Fatalf("NewGitLabProvider failed: %v", err)
+	}
+
+	branches, err := provider.Branches()
+	if err != nil {
+		t.Fatalf("Branches failed: %v", err)
+	}
+	if len(branches) != 2 || branches[0].Name != "feature/x" || branches[0].IsDefault || branches[1].Name != "main" || !branches[1].IsDefault {
+		t.Errorf("unexpected branches %+v", branches)
+	}
+
+	// Switching branches changes what the current branch reads
+	if err := provider.Checkout("feature/x", CheckoutClean); err != nil {
+		t.Fatalf("Checkout failed: %v", err)
+	}
+	content, err := provider.FileContent("README.md", "")
+	if err != nil {
+		t.Fatalf("FileContent failed: %v", err)
+	}
+	if string(content) != "# Feature" {
+		t.Errorf("expected feature/x content, got %q", content)
+	}
+
+	// The branch being browsed is marked as the default
+	branches, err = provider.Branches()
+	if err != nil {
+		t.Fatalf("Branches failed: %v", err)
+	}
+	if len(branches) != 2 || !branches[0].IsDefault || branches[1].IsDefault {
+		t.Errorf("expected feature/x to be the current branch, got %+v", branches)
+	}
+
+	if err := provider.Checkout("missing", CheckoutClean); err == nil {
+		t.Error("expected error checking out a missing branch")
+	}
+}
+
+func TestGitLabProvider_ResolveRevision(t *testing.T) {
+	_, server := newFakeGitLab(t)
+
+	provider, err := NewGitLabProvider(server.URL, "group/wiki", "", "")
+	if err != nil {
+		t.Fatalf("NewGitLabProvider failed: %v", err)
+	}
+
+	for branch, expected := range map[string]string{"": "commit-main", "feature/x": "commit-feature/x"} {
+		hash, err := provider.ResolveRevision(branch)
+		if err != nil {
+			t.Fatalf("ResolveRevision(%q) failed: %v", branch, err)
+		}
+		if hash != expected {
+			t.Errorf("ResolveRevision(%q) = %q, want %q", branch, hash, expected)
+		}
+	}
+
+	if _, err := provider.ResolveRevision("missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected revision not found error, got %v", err)
+	}
+}
+
+func TestGitLabProvider_ReadOnly(t *testing.T) {
+	_, server := newFakeGitLab(t)
+
+	provider, err := NewGitLabProvider(server.URL, "group/wiki", "", "")
+	if err != nil {
+		t.Fatalf("NewGitLabProvider failed: %v", err)
+	}
+
+	if err := provider.WriteFile("a.md", []byte("a")); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly, got %v", err)
+	}
+	if _, err := provider.Commit("message"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly, got %v", err)
+	}
+}
@@ -3,13 +3,14 @@ package git
 import (
 	"bufio"
 	"bytes"
+	"container/heap"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
-	"time"
+	"sync"
 	"unicode/utf8"
 
 	"github.com/go-git/go-git/v5"
@@ -22,9 +23,15 @@ import (
 // It reads from the working tree for the current branch (showing uncommitted changes)
 // and from git object store for other branches (committed state only).
 type LocalProvider struct {
-	repo   *git.Repository
-	path   string
+	repo *git.Repository
+	path string
+
+	mu     sync.RWMutex // guards branch, which changes on Checkout
 	branch string
+
+	creds  Credentials  // authentication with remotes
+	author Identity     // default commit author; empty fields fall back to gitconfig
+	signer commitSigner // signs commits; nil leaves them unsigned
 }
 
 // NewLocalProvider creates a new LocalProvider for the given path and branch.
@@ -70,13 +77,20 @@ func NewLocalProvider(path, branch string) (*LocalProvider, error) {
 	}, nil
 }
 
+// currentBranch returns the branch checked out in the working tree.
+func (p *LocalProvider) currentBranch() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.branch
+}
+
 // Tree returns the complete file tree for the given branch.
 // For the current branch, reads from working tree (includes uncommitted changes).
-// For other branches, reads from git object store (committed state only).
+// For other branches, tags, and commits, reads from git object store (committed state only).
 // Respects .gitignore rules.
 func (p *LocalProvider) Tree(branch string) (*TreeNode, error) {
 	// Determine if this is the current/HEAD branch
-	isCurrentBranch := (branch == "" || branch == p.branch)
+	isCurrentBranch := (branch == "" || branch == p.currentBranch())
 
 	if isCurrentBranch {
 		// Read from working tree (includes uncommitted changes)
@@ -87,20 +101,13 @@ func (p *LocalProvider) Tree(branch string) (*TreeNode, error) {
 	return p.buildTreeFromCommit(branch)
 }
 
-// buildTreeFromCommit builds a tree from the git object store for a specific branch.
+// buildTreeFromCommit builds a tree from the git object store for a specific revision.
 // This returns the committed state only (no uncommitted changes).
-func (p *LocalProvider) buildTreeFromCommit(branch string) (*TreeNode, error) {
-	// Get the branch reference
-	branchRef := plumbing.NewBranchReferenceName(branch)
-	ref, err := p.repo.Reference(branchRef, true)
+func (p *LocalProvider) buildTreeFromCommit(rev string) (*TreeNode, error) {
+	// Resolve the revision to a commit
+	commit, err := p.resolveCommit(rev)
 	if err != nil {
-		return nil, fmt.Errorf("branch '%s' not found: %w", branch, err)
-	}
-
-	// Get the commit object
-	commit, err := p.repo.CommitObject(ref.Hash())
-	if err != nil {
-		return nil, fmt.Errorf("failed to get commit: %w", err)
+		return nil, err
 	}
 
 	// Get the tree object
@@ -119,7 +126,7 @@ func (p *LocalProvider) buildTreeFromCommit(branch string) (*TreeNode, error) {
 
 	// Walk the tree recursively
 	err = tree.Files().ForEach(func(file *object.File) error {
-		p.addPathToTree(root, file.Name)
+		addPathToTree(root, file.Name)
 		return nil
 	})
 	if err != nil {
@@ -127,7 +134,7 @@ func (p *LocalProvider) buildTreeFromCommit(branch string) (*TreeNode, error) {
 	}
 
 	// Sort the tree
-	p.sortTree(root)
+	sortTree(root)
 
 	return root, nil
 }
@@ -236,11 +243,11 @@ func (p *LocalProvider) buildWorkingTreeWithIgnore() (*TreeNode, error) {
 	}
 
 	for _, path := range filePaths {
-		p.addPathToTree(root, path)
+		addPathToTree(root, path)
 	}
 
 	// Sort tree (directories first, then alphabetically)
-	p.sortTree(root)
+	sortTree(root)
 
 	return root, nil
 }
@@ -286,7 +293,7 @@ func (p *LocalProvider) shouldIgnore(path string, patterns []gitignore.Pattern,
 }
 
 // addPathToTree adds a file path to the tree structure.
-func (p *LocalProvider) addPathToTree(root *TreeNode, path string) {
+func addPathToTree(root *TreeNode, path string) {
 	parts := strings.Split(path, "/")
 	current := root
 
@@ -321,7 +328,7 @@ func (p *LocalProvider) addPathToTree(root *TreeNode, path string) {
 }
 
 // sortTree sorts the tree recursively: directories first, then files, both alphabetically (case-insensitive).
-func (p *LocalProvider) sortTree(node *TreeNode) {
+func sortTree(node *TreeNode) {
 	if len(node.Children) == 0 {
 		return
 	}
@@ -339,13 +346,13 @@ func (p *LocalProvider) sortTree(node *TreeNode) {
 
 	// Recursively sort children
 	for i := range node.Children {
-		p.sortTree(&node.Children[i])
+		sortTree(&node.Children[i])
 	}
 }
 
 // FileContent returns the raw bytes of a file at the given path.
 // For the current branch, reads from working tree (includes uncommitted changes).
-// For other branches, reads from git object store (committed state only).
+// For other branches, tags, and commits, reads from git object store (committed state only).
 func (p *LocalProvider) FileContent(path, branch string) ([]byte, error) {
 	// Normalize path: strip leading/trailing slashes, convert to forward slashes
 	path = strings.Trim(path, "/")
@@ -362,7 +369,7 @@ func (p *LocalProvider) FileContent(path, branch string) ([]byte, error) {
 	}
 
 	// Determine if this is the current/HEAD branch
-	isCurrentBranch := (branch == "" || branch == p.branch)
+	isCurrentBranch := (branch == "" || branch == p.currentBranch())
 
 	if isCurrentBranch {
 		// Read from working tree (includes uncommitted changes)
@@ -395,20 +402,13 @@ func (p *LocalProvider) FileContent(path, branch string) ([]byte, error) {
 	return p.readFileFromCommit(branch, path)
 }
 
-// readFileFromCommit reads a file from the git object store for a specific branch.
+// readFileFromCommit reads a file from the git object store for a specific revision.
 // This returns the committed state only (no uncommitted changes).
-func (p *LocalProvider) readFileFromCommit(branch, path string) ([]byte, error) {
-	// Get the branch reference
-	branchRef := plumbing.NewBranchReferenceName(branch)
-	ref, err := p.repo.Reference(branchRef, true)
+func (p *LocalProvider) readFileFromCommit(rev, path string) ([]byte, error) {
+	// Resolve the revision to a commit
+	commit, err := p.resolveCommit(rev)
 	if err != nil {
-		return nil, fmt.Errorf("branch '%s' not found: %w", branch, err)
-	}
-
-	// Get the commit object
-	commit, err := p.repo.CommitObject(ref.Hash())
-	if err != nil {
-		return nil, fmt.Errorf("failed to get commit: %w", err)
+		return nil, err
 	}
 
 	// Get the tree object
@@ -442,8 +442,39 @@ func (p *LocalProvider) readFileFromCommit(branch, path string) ([]byte, error)
 	return content, nil
 }
 
+// resolveCommit resolves a revision to a commit.
+// Accepts branch names, tags, full or abbreviated commit hashes, and
+// revision expressions such as "HEAD~2" or "main^". An empty revision
+// resolves to HEAD. Branch names take precedence over other interpretations.
+func (p *LocalProvider) resolveCommit(rev string) (*object.Commit, error) {
+	if rev == "" {
+		rev = "HEAD"
+	}
+
+	var hash plumbing.Hash
+	if ref, err := p.repo.Reference(plumbing.NewBranchReferenceName(rev), true); err == nil {
+		hash = ref.Hash()
+	} else {
+		resolved, err := p.repo.ResolveRevision(plumbing.Revision(rev))
+		if err != nil {
+			return nil, fmt.Errorf("revision '%s' not found: %w", rev, err)
+		}
+		hash = *resolved
+	}
+
+	commit, err := p.repo.CommitObject(hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit: %w", err)
+	}
+
+	return commit, nil
+}
+
 // Branches returns a list of all branches in the repository.
 // The current HEAD branch is marked as IsDefault.
+// Remote-tracking branches (e.g. "origin/main") follow the local branches,
+// marked as Remote, with ahead/behind counts relative to the local branch
+// of the same name when one exists.
 func (p *LocalProvider) Branches() ([]BranchInfo, error) {
 	// Get iterator for all branches
 	iter, err := p.repo.Branches()
@@ -453,6 +484,10 @@ func (p *LocalProvider) Branches() ([]BranchInfo, error) {
 	defer iter.Close()
 
 	var branches []BranchInfo
+	current := p.currentBranch()
+
+	// Remember local branch tips for ahead/behind counts
+	localHashes := make(map[string]plumbing.Hash)
 
 	// Iterate through all branches
 	err = iter.ForEach(func(ref *plumbing.Reference) error {
@@ -460,21 +495,212 @@ func (p *LocalProvider) Branches() ([]BranchInfo, error) {
 		branchName := ref.Name().Short()
 
 		// Mark as default if it matches the current branch
-		isDefault := branchName == p.branch
+		isDefault := branchName == current
 
 		branches = append(branches, BranchInfo{
 			Name:      branchName,
 			IsDefault: isDefault,
 		})
+		localHashes[branchName] = ref.Hash()
 		return nil
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to iterate branches: %w", err)
 	}
 
+	// Add remote-tracking branches
+	refs, err := p.repo.References()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list references: %w", err)
+	}
+	defer refs.Close()
+
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		// Skip non-remote refs and symbolic refs such as refs/remotes/origin/HEAD
+		if !ref.Name().IsRemote() || ref.Type() != plumbing.HashReference {
+			return nil
+		}
+
+		// Extract short name (e.g., "origin/main" from "refs/remotes/origin/main")
+		branchName := ref.Name().Short()
+		info := BranchInfo{
+			Name:   branchName,
+			Remote: true,
+		}
+
+		// Compare against the local branch of the same name, if any
+		_, localName, _ := strings.Cut(branchName, "/")
+		if localHash, ok := localHashes[localName]; ok {
+			ahead, behind, err := p.aheadBehind(localHash, ref.Hash())
+			if err != nil {
+				return err
+			}
+			info.Ahead = ahead
+			info.Behind = behind
+		}
+
+		branches = append(branches, info)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate remote branches: %w", err)
+	}
+
 	return branches, nil
 }
 
+// aheadBehind counts the commits reachable from local but not from remote (ahead)
+// and the commits reachable from remote but not from local (behind).
+//
+// Like git, it walks both histories at once, newest commit first, marking each
+// commit with the tips it is reachable from, and stops once every commit left
+// to visit is reachable from both: their history is shared, so it is not walked.
+func (p *LocalProvider) aheadBehind(local, remote plumbing.Hash) (ahead, behind int, err error) {
+	if local == remote {
+		return 0, 0, nil
+	}
+
+	const (
+		fromLocal  = 1
+		fromRemote = 2
+		fromBoth   = fromLocal | fromRemote
+	)
+	marks := make(map[plumbing.Hash]uint8)
+	queue := &commitQueue{}
+	queued := make(map[plumbing.Hash]bool)
+	pending := 0 // queued commits not yet reachable from both tips
+
+	// visit marks a commit and queues it if the mark is new
+	visit := func(hash plumbing.Hash, mark uint8) error {
+		if marks[hash]|mark == marks[hash] {
+			return nil
+		}
+		marks[hash] |= mark
+
+		// A queued commit is walked with its latest marks when it is popped
+		if queued[hash] {
+			if marks[hash] == fromBoth {
+				pending--
+			}
+			return nil
+		}
+		commit, err := p.repo.CommitObject(hash)
+		if err != nil {
+			return fmt.Errorf("failed to get commit %s: %w", hash, err)
+		}
+		queued[hash] = true
+		if marks[hash] != fromBoth {
+			pending++
+		}
+		heap.Push(queue, commit)
+		return nil
+	}
+
+	if err := visit(local, fromLocal); err != nil {
+		return 0, 0, err
+	}
+	if err := visit(remote, fromRemote); err != nil {
+		return 0, 0, err
+	}
+
+	// Once every queued commit is reachable from both tips, so is everything older
+	for pending > 0 {
+		commit := heap.Pop(queue).(*object.Commit)
+		delete(queued, commit.Hash)
+		if marks[commit.Hash] != fromBoth {
+			pending--
+		}
+		for _, parent := range commit.ParentHashes {
+			if err := visit(parent, marks[commit.Hash]); err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+
+	for _, mark := range marks {
+		switch mark {
+		case fromLocal:
+			ahead++
+		case fromRemote:
+			behind++
+		}
+	}
+
+	return ahead, behind, nil
+}
+
+// commitQueue is a heap of commits, newest (by committer date) first.
+type commitQueue []*object.Commit
+
+func (q commitQueue) Len() int      { return len(q) }
+func (q commitQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q commitQueue) Less(i, j int) bool {
+	return q[i].Committer.When.After(q[j].Committer.When)
+}
+func (q *commitQueue) Push(x any) { *q = append(*q, x.(*object.Commit)) }
+func (q *commitQueue) Pop() any {
+	old := *q
+	c := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return c
+}
+
+// Tags returns all tags in the repository, newest first.
+// Annotated tags are dated by their tagger; lightweight tags by their commit.
+// Tags that do not point to a commit are skipped.
+func (p *LocalProvider) Tags() ([]TagInfo, error) {
+	iter, err := p.repo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	defer iter.Close()
+
+	tags := []TagInfo{}
+
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		info := TagInfo{Name: ref.Name().Short()}
+
+		// Annotated tags point to a tag object; lightweight tags point directly to a commit
+		commitHash := ref.Hash()
+		if tag, err := p.repo.TagObject(ref.Hash()); err == nil {
+			if tag.TargetType != plumbing.CommitObject {
+				return nil
+			}
+			info.Annotated = true
+			info.Message = strings.TrimSpace(tag.Message)
+			info.Date = tag.Tagger.When
+			commitHash = tag.Target
+		}
+
+		commit, err := p.repo.CommitObject(commitHash)
+		if err != nil {
+			// Tag points to a tree or blob
+			return nil
+		}
+
+		info.Hash = commit.Hash.String()
+		if !info.Annotated {
+			info.Date = commit.Author.When
+		}
+
+		tags = append(tags, info)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate tags: %w", err)
+	}
+
+	// Newest first, then alphabetically for tags on the same date
+	sort.Slice(tags, func(i, j int) bool {
+		if !tags[i].Date.Equal(tags[j].Date) {
+			return tags[i].Date.After(tags[j].Date)
+		}
+		return tags[i].Name < tags[j].Name
+	})
+
+	return tags, nil
+}
+
 // Status returns the current repository status.
 // Returns source path, current branch, and dirty state (uncommitted changes).
 func (p *LocalProvider) Status() (*RepoStatus, error) {
@@ -495,7 +721,7 @@ func (p *LocalProvider) Status() (*RepoStatus, error) {
 
 	return &RepoStatus{
 		Source:  p.path,
-		Branch:  p.branch,
+		Branch:  p.currentBranch(),
 		IsDirty: isDirty,
 	}, nil
 }
@@ -697,38 +923,7 @@ func (p *LocalProvider) MoveFolder(oldPath, newPath string) error {
 // Returns the commit hash on success.
 // Requires a non-empty commit message.
 func (p *LocalProvider) Commit(message string) (string, error) {
-	// Validate message is not empty
-	message = strings.TrimSpace(message)
-	if message == "" {
-		return "", fmt.Errorf("commit message cannot be empty")
-	}
-
-	// Get worktree
-	worktree, err := p.repo.Worktree()
-	if err != nil {
-		return "", fmt.Errorf("failed to get worktree: %w", err)
-	}
-
-	// Stage all changes (git add .)
-	err = worktree.AddWithOptions(&git.AddOptions{All: true})
-	if err != nil {
-		return "", fmt.Errorf("failed to stage changes: %w", err)
-	}
-
-	// Create commit
-	// TODO: In Step 25, this signature will be customizable via config
-	hash, err := worktree.Commit(message, &git.CommitOptions{
-		Author: &object.Signature{
-			Name:  "Giki User",
-			Email: "user@giki.local",
-			When:  time.Now(),
-		},
-	})
-	if err != nil {
-		return "", fmt.Errorf("failed to create commit: %w", err)
-	}
-
-	return hash.String(), nil
+	return p.CommitWithOptions(CommitOptions{Message: message})
 }
 
 // SearchFileNames performs fuzzy filename matching against all files in the repository.
@@ -948,6 +1143,11 @@ func (p *LocalProvider) SearchContent(query string) ([]SearchResult, error) {
 // isTextFile checks if the content is likely a text file (not binary).
 // Uses simple heuristics: valid UTF-8 and no null bytes in first 8KB.
 func (p *LocalProvider) isTextFile(content []byte) bool {
+	return isTextContent(content)
+}
+
+// isTextContent implements the text detection heuristic used by isTextFile.
+func isTextContent(content []byte) bool {
 	// Empty files are text
 	if len(content) == 0 {
 		return true
@@ -0,0 +1,115 @@
+package git
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Blame returns line-level authorship for a file on the given branch.
+// Consecutive lines last changed by the same commit are grouped into a single range.
+// Blame is computed from the branch tip, so uncommitted working tree edits are not reflected.
+func (p *LocalProvider) Blame(path, branch string) (*FileBlame, error) {
+	// Normalize path: strip leading/trailing slashes, convert to forward slashes
+	path = strings.Trim(path, "/")
+	path = filepath.ToSlash(path)
+
+	// Validate path is not empty
+	if path == "" {
+		return nil, fmt.Errorf("file not found")
+	}
+
+	// Security: validate path doesn't escape repository root
+	if strings.Contains(path, "..") {
+		return nil, fmt.Errorf("invalid path: cannot contain '..'")
+	}
+
+	commit, err := p.resolveCommit(branch)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure the file exists in the commit before walking history
+	file, err := commit.File(path)
+	if err != nil {
+		if err == object.ErrFileNotFound {
+			return nil, fmt.Errorf("file not found")
+		}
+		return nil, fmt.Errorf("failed to get file: %w", err)
+	}
+
+	reader, err := file.Reader()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file reader: %w", err)
+	}
+	content, err := io.ReadAll(reader)
+	reader.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file content: %w", err)
+	}
+
+	if !p.isTextFile(content) {
+		return nil, fmt.Errorf("cannot blame binary file")
+	}
+
+	result, err := git.Blame(commit, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to blame file: %w", err)
+	}
+
+	blame := &FileBlame{
+		Path:     path,
+		Revision: commit.Hash.String(),
+		Ranges:   []BlameRange{},
+	}
+
+	// Commit summaries are looked up once per distinct commit
+	summaries := make(map[plumbing.Hash]string)
+
+	for i, line := range result.Lines {
+		lineNumber := i + 1
+
+		// Extend the current range if this line came from the same commit
+		if n := len(blame.Ranges); n > 0 && blame.Ranges[n-1].Hash == line.Hash.String() {
+			blame.Ranges[n-1].EndLine = lineNumber
+			continue
+		}
+
+		summary, ok := summaries[line.Hash]
+		if !ok {
+			summary, err = p.commitSummary(line.Hash)
+			if err != nil {
+				return nil, err
+			}
+			summaries[line.Hash] = summary
+		}
+
+		blame.Ranges = append(blame.Ranges, BlameRange{
+			StartLine: lineNumber,
+			EndLine:   lineNumber,
+			Hash:      line.Hash.String(),
+			Author:    line.AuthorName,
+			Email:     line.Author,
+			Date:      line.Date,
+			Summary:   summary,
+		})
+	}
+
+	return blame, nil
+}
+
+// commitSummary returns the first line of a commit's message.
+func (p *LocalProvider) commitSummary(hash plumbing.Hash) (string, error) {
+	commit, err := p.repo.CommitObject(hash)
+	if err != nil {
+		return "", fmt.Errorf("failed to get commit %s: %w", hash, err)
+	}
+
+	summary, _, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+	return summary, nil
+}
@@ -0,0 +1,192 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// commitAs writes a file and commits it with the given author.
+func commitAs(t *testing.T, repo *git.Repository, dir, path, content, message, name string, when time.Time) string {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(dir, path), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+
+	w, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	if _, err := w.Add(path); err != nil {
+		t.Fatalf("failed to add %s: %v", path, err)
+	}
+
+	sig := &object.Signature{Name: name, Email: strings.ToLower(name) + "@example.com", When: when}
+	hash, err := w.Commit(message, &git.CommitOptions{Author: sig, Committer: sig})
+	if err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	return hash.String()
+}
+
+func TestBlame_GroupsLinesByCommit(t *testing.T) {
+	tempDir := t.TempDir()
+	repo, err := git.PlainInit(tempDir, false)
+	if err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	first := commitAs(t, repo, tempDir, "page.md", "one\ntwo\nthree\nfour\n", "Add page\n\nLonger description.", "Alice", base)
+	second := commitAs(t, repo, tempDir, "page.md", "one\nTWO\nTHREE\nfour\n", "Shout the middle", "Bob", base.Add(time.Hour))
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	blame, err := provider.Blame("page.md", "")
+	if err != nil {
+		t.Fatalf("Blame failed: %v", err)
+	}
+
+	if blame.Path != "page.md" {
+		t.Errorf("expected path page.md, got %s", blame.Path)
+	}
+	if blame.Revision != second {
+		t.Errorf("expected revision %s, got %s", second, blame.Revision)
+	}
+
+	expected := []struct {
+		start, end int
+		hash       string
+		author     string
+		summary    string
+	}{
+		{1, 1, first, "Alice", "Add page"},
+		{2, 3, second, "Bob", "Shout the middle"},
+		{4, 4, first, "Alice", "Add page"},
+	}
+
+	if len(blame.Ranges) != len(expected) {
+		t.Fatalf("expected %d ranges, got %d: %+v", len(expected), len(blame.Ranges), blame.Ranges)
+	}
+
+	for i, want := range expected {
+		got := blame.Ranges[i]
+		if got.StartLine != want.start || got.EndLine != want.end {
+			t.Errorf("range %d: expected lines %d-%d, got %d-%d", i, want.start, want.end, got.StartLine, got.EndLine)
+		}
+		if got.Hash != want.hash {
+			t.Errorf("range %d: expected hash %s, got %s", i, want.hash, got.Hash)
+		}
+		if got.Author != want.author {
+			t.Errorf("range %d: expected author %s, got %s", i, want.author, got.Author)
+		}
+		if got.Email != strings.ToLower(want.author)+"@example.com" {
+			t.Errorf("range %d: unexpected email %s", i, got.Email)
+		}
+		if got.Summary != want.summary {
+			t.Errorf("range %d: expected summary %q, got %q", i, want.summary, got.Summary)
+		}
+	}
+}
+
+func TestBlame_OtherBranch(t *testing.T) {
+	tempDir := t.TempDir()
+	repo, err := git.PlainInit(tempDir, false)
+	if err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	first := commitAs(t, repo, tempDir, "page.md", "hello\n", "Add page", "Alice", base)
+
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v", err)
+	}
+	w, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	if err := w.Checkout(&git.CheckoutOptions{Branch: "refs/heads/feature", Create: true}); err != nil {
+		t.Fatalf("failed to create feature branch: %v", err)
+	}
+	commitAs(t, repo, tempDir, "page.md", "hello\nworld\n", "Extend page", "Bob", base.Add(time.Hour))
+	if err := w.Checkout(&git.CheckoutOptions{Branch: head.Name()}); err != nil {
+		t.Fatalf("failed to check out %s: %v", head.Name(), err)
+	}
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	// Current branch only has Alice's line
+	blame, err := provider.Blame("page.md", "")
+	if err != nil {
+		t.Fatalf("Blame failed: %v", err)
+	}
+	if len(blame.Ranges) != 1 || blame.Ranges[0].Hash != first {
+		t.Errorf("expected a single range from %s, got %+v", first, blame.Ranges)
+	}
+
+	// Feature branch has Bob's line too
+	blame, err = provider.Blame("page.md", "feature")
+	if err != nil {
+		t.Fatalf("Blame on feature failed: %v", err)
+	}
+	if len(blame.Ranges) != 2 || blame.Ranges[1].Author != "Bob" {
+		t.Errorf("expected second range by Bob on feature branch, got %+v", blame.Ranges)
+	}
+}
+
+func TestBlame_Errors(t *testing.T) {
+	tempDir := t.TempDir()
+	repo, err := git.PlainInit(tempDir, false)
+	if err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	commitAs(t, repo, tempDir, "page.md", "hello\n", "Add page", "Alice", base)
+	commitAs(t, repo, tempDir, "image.bin", "\x00\x01\x02", "Add binary", "Alice", base.Add(time.Hour))
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		path        string
+		branch      string
+		errContains string
+	}{
+		{"missing file", "missing.md", "", "file not found"},
+		{"empty path", "", "", "file not found"},
+		{"path traversal", "../page.md", "", "invalid path"},
+		{"binary file", "image.bin", "", "binary file"},
+		{"unknown branch", "page.md", "nonexistent", "not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := provider.Blame(tt.path, tt.branch)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("expected error containing %q, got %v", tt.errContains, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// CreateBranch creates a new local branch named name pointing at from.
+// The from revision may be a branch, tag, or commit; empty means HEAD.
+// The new branch is not checked out.
+func (p *LocalProvider) CreateBranch(name, from string) error {
+	name = strings.TrimSpace(name)
+	if err := validateBranchName(name); err != nil {
+		return err
+	}
+
+	ref := plumbing.NewBranchReferenceName(name)
+	if _, err := p.repo.Reference(ref, false); err == nil {
+		return fmt.Errorf("branch '%s' already exists", name)
+	}
+
+	commit, err := p.resolveCommit(from)
+	if err != nil {
+		return err
+	}
+
+	if err := p.repo.Storer.SetReference(plumbing.NewHashReference(ref, commit.Hash)); err != nil {
+		return fmt.Errorf("failed to create branch '%s': %w", name, err)
+	}
+
+	return nil
+}
+
+// DeleteBranch deletes a local branch.
+// The current branch can never be deleted. A branch whose commits are not
+// contained in the current branch is only deleted when force is true.
+func (p *LocalProvider) DeleteBranch(name string, force bool) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("branch name cannot be empty")
+	}
+
+	// Hold the lock so the branch cannot be checked out while it is being deleted
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if name == p.branch {
+		return fmt.Errorf("cannot delete the current branch '%s'", name)
+	}
+
+	ref, err := p.repo.Reference(plumbing.NewBranchReferenceName(name), false)
+	if err != nil {
+		return fmt.Errorf("branch '%s' not found", name)
+	}
+
+	if !force {
+		merged, err := p.isMerged(ref.Hash())
+		if err != nil {
+			return err
+		}
+		if !merged {
+			return fmt.Errorf("branch '%s' is not fully merged into '%s'; use force to delete it anyway", name, p.branch)
+		}
+	}
+
+	if err := p.repo.Storer.RemoveReference(ref.Name()); err != nil {
+		return fmt.Errorf("failed to delete branch '%s': %w", name, err)
+	}
+
+	// Drop tracking configuration, if any
+	if err := p.repo.DeleteBranch(name); err != nil && err != git.ErrBranchNotFound {
+		return fmt.Errorf("failed to remove configuration for branch '%s': %w", name, err)
+	}
+
+	// Drop changes stashed by Checkout for the deleted branch
+	if err := os.Remove(p.stashPath(name)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove stash for branch '%s': %w", name, err)
+	}
+
+	return nil
+}
+
+// isMerged reports whether the commit is reachable from HEAD.
+func (p *LocalProvider) isMerged(hash plumbing.Hash) (bool, error) {
+	head, err := p.repo.Head()
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+
+	// Merged when the commit has nothing HEAD lacks; the walk stops at their merge base
+	ahead, _, err := p.aheadBehind(hash, head.Hash())
+	if err != nil {
+		return false, err
+	}
+
+	return ahead == 0, nil
+}
+
+// validateBranchName checks that name is a valid git branch name.
+func validateBranchName(name string) error {
+	if name == "" {
+		return fmt.Errorf("branch name cannot be empty")
+	}
+
+	// Names git itself refuses or that would be confused with options and revisions
+	if name == "HEAD" || strings.HasPrefix(name, "-") {
+		return fmt.Errorf("invalid branch name: %s", name)
+	}
+
+	if err := plumbing.NewBranchReferenceName(name).Validate(); err != nil {
+		return fmt.Errorf("invalid branch name: %s", name)
+	}
+
+	return nil
+}
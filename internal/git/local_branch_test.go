@@ -0,0 +1,208 @@
+package git
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestCreateBranch(t *testing.T) {
+	tempDir, _, hashes := createHistoryRepo(t)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	if err := provider.CreateBranch("topic/intro", ""); err != nil {
+		t.Fatalf("CreateBranch from HEAD failed: %v", err)
+	}
+	if err := provider.CreateBranch("older", hashes[1]); err != nil {
+		t.Fatalf("CreateBranch from commit failed: %v", err)
+	}
+
+	tests := []struct {
+		branch string
+		hash   string
+	}{
+		{"topic/intro", hashes[3]},
+		{"older", hashes[1]},
+	}
+	for _, tt := range tests {
+		ref, err := provider.repo.Reference(plumbing.NewBranchReferenceName(tt.branch), false)
+		if err != nil {
+			t.Fatalf("expected branch %s to exist: %v", tt.branch, err)
+		}
+		if ref.Hash().String() != tt.hash {
+			t.Errorf("expected %s at %s, got %s", tt.branch, tt.hash, ref.Hash())
+		}
+	}
+
+	// Creating a branch does not switch to it
+	status, err := provider.Status()
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if status.Branch == "topic/intro" || status.Branch == "older" {
+		t.Errorf("expected current branch to be unchanged, got %s", status.Branch)
+	}
+}
+
+func TestCreateBranch_Errors(t *testing.T) {
+	tempDir, _, _ := createHistoryRepo(t)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	if err := provider.CreateBranch("existing", ""); err != nil {
+		t.Fatalf("CreateBranch failed: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		branch      string
+		from        string
+		errContains string
+	}{
+		{"empty name", "", "", "cannot be empty"},
+		{"space in name", "my branch", "", "invalid branch name"},
+		{"double dot", "a..b", "", "invalid branch name"},
+		{"lock suffix", "topic.lock", "", "invalid branch name"},
+		{"leading dash", "-f", "", "invalid branch name"},
+		{"HEAD", "HEAD", "", "invalid branch name"},
+		{"already exists", "existing", "", "already exists"},
+		{"unknown start", "new", "nonexistent", "not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := provider.CreateBranch(tt.branch, tt.from)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("expected error containing %q, got %v", tt.errContains, err)
+			}
+		})
+	}
+}
+
+func TestDeleteBranch(t *testing.T) {
+	tempDir, repo, hashes := createHistoryRepo(t)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	// "merged" is behind the current branch; "unmerged" has a commit of its own
+	if err := provider.CreateBranch("merged", hashes[1]); err != nil {
+		t.Fatalf("CreateBranch failed: %v", err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v", err)
+	}
+	w, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	if err := w.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("unmerged"), Create: true}); err != nil {
+		t.Fatalf("failed to create branch: %v", err)
+	}
+	commitFile(t, repo, tempDir, "draft.md", "draft\n", "Draft", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	if err := w.Checkout(&git.CheckoutOptions{Branch: head.Name()}); err != nil {
+		t.Fatalf("failed to check out: %v", err)
+	}
+
+	if err := provider.DeleteBranch("merged", false); err != nil {
+		t.Fatalf("DeleteBranch on merged branch failed: %v", err)
+	}
+	if _, err := repo.Reference(plumbing.NewBranchReferenceName("merged"), false); err == nil {
+		t.Error("expected merged branch to be deleted")
+	}
+
+	err = provider.DeleteBranch("unmerged", false)
+	if err == nil || !strings.Contains(err.Error(), "not fully merged") {
+		t.Fatalf("expected not fully merged error, got %v", err)
+	}
+
+	if err := provider.DeleteBranch("unmerged", true); err != nil {
+		t.Fatalf("forced DeleteBranch failed: %v", err)
+	}
+	if _, err := repo.Reference(plumbing.NewBranchReferenceName("unmerged"), false); err == nil {
+		t.Error("expected unmerged branch to be deleted with force")
+	}
+}
+
+func TestDeleteBranch_RemovesStash(t *testing.T) {
+	tempDir, mainBranch := createCheckoutRepo(t)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	// Stash a change on feature, then leave it
+	if err := provider.Checkout("feature", CheckoutClean); err != nil {
+		t.Fatalf("Checkout failed: %v", err)
+	}
+	if err := provider.WriteFile("feature.md", []byte("edited\n")); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := provider.Checkout(mainBranch, CheckoutStash); err != nil {
+		t.Fatalf("Checkout failed: %v", err)
+	}
+
+	if err := provider.DeleteBranch("feature", true); err != nil {
+		t.Fatalf("DeleteBranch failed: %v", err)
+	}
+	if _, err := os.Stat(provider.stashPath("feature")); !os.IsNotExist(err) {
+		t.Errorf("expected stash for deleted branch to be removed, got %v", err)
+	}
+}
+
+func TestDeleteBranch_Errors(t *testing.T) {
+	tempDir, _, _ := createHistoryRepo(t)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	status, err := provider.Status()
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		branch      string
+		force       bool
+		errContains string
+	}{
+		{"empty name", "", false, "cannot be empty"},
+		{"unknown branch", "nonexistent", false, "not found"},
+		{"current branch", status.Branch, false, "current branch"},
+		{"current branch forced", status.Branch, true, "current branch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := provider.DeleteBranch(tt.branch, tt.force)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("expected error containing %q, got %v", tt.errContains, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestBranches_RemoteTracking(t *testing.T) {
+	tempDir := t.TempDir()
+	repo, err := git.PlainInit(tempDir, false)
+	if err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	first := commitFile(t, repo, tempDir, "page.md", "one\n", "First", base)
+	second := commitFile(t, repo, tempDir, "page.md", "two\n", "Second", base.Add(time.Hour))
+	commitFile(t, repo, tempDir, "page.md", "three\n", "Third", base.Add(2*time.Hour))
+
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v", err)
+	}
+	mainBranch := head.Name().Short()
+
+	// origin/<main> is one commit behind the local branch,
+	// origin/feature exists only on the remote.
+	refs := []*plumbing.Reference{
+		plumbing.NewHashReference(plumbing.NewRemoteReferenceName("origin", mainBranch), plumbing.NewHash(second)),
+		plumbing.NewHashReference(plumbing.NewRemoteReferenceName("origin", "feature"), plumbing.NewHash(first)),
+		plumbing.NewSymbolicReference(plumbing.NewRemoteHEADReferenceName("origin"), plumbing.NewRemoteReferenceName("origin", mainBranch)),
+	}
+	for _, ref := range refs {
+		if err := repo.Storer.SetReference(ref); err != nil {
+			t.Fatalf("failed to set %s: %v", ref.Name(), err)
+		}
+	}
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	branches, err := provider.Branches()
+	if err != nil {
+		t.Fatalf("Branches failed: %v", err)
+	}
+
+	byName := make(map[string]BranchInfo)
+	for _, b := range branches {
+		byName[b.Name] = b
+	}
+
+	if len(branches) != 3 {
+		t.Fatalf("expected 3 branches (local + 2 remote), got %d: %+v", len(branches), branches)
+	}
+
+	local := byName[mainBranch]
+	if local.Remote || !local.IsDefault {
+		t.Errorf("expected local default branch, got %+v", local)
+	}
+
+	remoteMain, ok := byName["origin/"+mainBranch]
+	if !ok {
+		t.Fatalf("expected origin/%s in branch list", mainBranch)
+	}
+	if !remoteMain.Remote || remoteMain.IsDefault {
+		t.Errorf("expected non-default remote branch, got %+v", remoteMain)
+	}
+	if remoteMain.Ahead != 1 || remoteMain.Behind != 0 {
+		t.Errorf("expected local to be 1 ahead, 0 behind origin, got ahead=%d behind=%d", remoteMain.Ahead, remoteMain.Behind)
+	}
+
+	feature, ok := byName["origin/feature"]
+	if !ok {
+		t.Fatal("expected origin/feature in branch list")
+	}
+	if !feature.Remote || feature.Ahead != 0 || feature.Behind != 0 {
+		t.Errorf("expected remote-only branch without counts, got %+v", feature)
+	}
+
+	if _, ok := byName["origin/HEAD"]; ok {
+		t.Error("symbolic origin/HEAD should not be listed")
+	}
+
+	// Remote-only branches are browsable from the object store
+	content, err := provider.FileContent("page.md", "origin/feature")
+	if err != nil {
+		t.Fatalf("FileContent on origin/feature failed: %v", err)
+	}
+	if string(content) != "one\n" {
+		t.Errorf("expected origin/feature content, got %q", content)
+	}
+}
+
+func TestAheadBehind_Diverged(t *testing.T) {
+	tempDir := t.TempDir()
+	repo, err := git.PlainInit(tempDir, false)
+	if err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	commitFile(t, repo, tempDir, "page.md", "base\n", "Base", base)
+
+	w, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v", err)
+	}
+
+	if err := w.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("other"), Create: true}); err != nil {
+		t.Fatalf("failed to create branch: %v", err)
+	}
+	commitFile(t, repo, tempDir, "other.md", "a\n", "Other 1", base.Add(time.Hour))
+	otherTip := commitFile(t, repo, tempDir, "other.md", "b\n", "Other 2", base.Add(2*time.Hour))
+
+	if err := w.Checkout(&git.CheckoutOptions{Branch: head.Name()}); err != nil {
+		t.Fatalf("failed to check out: %v", err)
+	}
+	localTip := commitFile(t, repo, tempDir, "local.md", "c\n", "Local", base.Add(3*time.Hour))
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	ahead, behind, err := provider.aheadBehind(plumbing.NewHash(localTip), plumbing.NewHash(otherTip))
+	if err != nil {
+		t.Fatalf("aheadBehind failed: %v", err)
+	}
+	if ahead != 1 || behind != 2 {
+		t.Errorf("expected ahead=1 behind=2, got ahead=%d behind=%d", ahead, behind)
+	}
+
+	// Merging the other branch leaves nothing behind it
+	sig := &object.Signature{Name: "Test Author", Email: "test@example.com", When: base.Add(4 * time.Hour)}
+	merge, err := w.Commit("Merge other", &git.CommitOptions{
+		Author:            sig,
+		Committer:         sig,
+		Parents:           []plumbing.Hash{plumbing.NewHash(localTip), plumbing.NewHash(otherTip)},
+		AllowEmptyCommits: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to commit merge: %v", err)
+	}
+
+	ahead, behind, err = provider.aheadBehind(merge, plumbing.NewHash(otherTip))
+	if err != nil {
+		t.Fatalf("aheadBehind failed: %v", err)
+	}
+	if ahead != 2 || behind != 0 {
+		t.Errorf("expected ahead=2 behind=0 after merge, got ahead=%d behind=%d", ahead, behind)
+	}
+}
+
+func TestBranches_AfterClone(t *testing.T) {
+	// Source repository with two branches
+	sourceDir := t.TempDir()
+	source, err := git.PlainInit(sourceDir, false)
+	if err != nil {
+		t.Fatalf("failed to init source repo: %v", err)
+	}
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tip := commitFile(t, source, sourceDir, "README.md", "hello\n", "Initial", base)
+	if err := source.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("docs"), plumbing.NewHash(tip))); err != nil {
+		t.Fatalf("failed to create docs branch: %v", err)
+	}
+
+	cloneDir := t.TempDir()
+	if err := CloneRemote(sourceDir, cloneDir); err != nil {
+		t.Fatalf("clone failed: %v", err)
+	}
+
+	provider, err := NewLocalProvider(cloneDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	branches, err := provider.Branches()
+	if err != nil {
+		t.Fatalf("Branches failed: %v", err)
+	}
+
+	found := false
+	for _, b := range branches {
+		if b.Name == "origin/docs" && b.Remote {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected origin/docs in branches after clone, got %+v", branches)
+	}
+
+	tree, err := provider.Tree("origin/docs")
+	if err != nil {
+		t.Fatalf("Tree(origin/docs) failed: %v", err)
+	}
+	if len(tree.Children) != 1 || tree.Children[0].Name != "README.md" {
+		t.Errorf("unexpected tree for origin/docs: %+v", tree.Children)
+	}
+}
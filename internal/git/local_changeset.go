@@ -0,0 +1,530 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Change set operation types, matching the single-file write endpoints.
+const (
+	OpWrite      = "write"
+	OpDelete     = "delete"
+	OpMove       = "move"
+	OpMoveFolder = "move-folder"
+)
+
+// ChangeOp is a single operation in a change set.
+type ChangeOp struct {
+	Op      string `json:"op"`
+	Path    string `json:"path,omitempty"`    // write, delete
+	Content string `json:"content,omitempty"` // write
+	OldPath string `json:"oldPath,omitempty"` // move, move-folder
+	NewPath string `json:"newPath,omitempty"` // move, move-folder
+
+	// BaseHash is the blob hash of the file (Path, or OldPath for a move) the
+	// operation was based on; the change set fails with a *ConflictError if the
+	// file has changed since. Ignored for move-folder.
+	BaseHash string `json:"baseHash,omitempty"`
+}
+
+// ChangeSetError reports the operation that made a change set fail.
+type ChangeSetError struct {
+	Index int    // position of the operation in the change set
+	Op    string // operation type
+	Err   error
+}
+
+func (e *ChangeSetError) Error() string {
+	return fmt.Sprintf("operation %d (%s): %v", e.Index, e.Op, e.Err)
+}
+
+func (e *ChangeSetError) Unwrap() error {
+	return e.Err
+}
+
+// ApplyChangeSet applies an ordered list of operations to the working tree as a unit.
+// Every operation is validated against the state left by the ones before it
+// before anything is touched, and base hashes are checked against the working
+// tree as it was before the change set; if applying any operation fails, the
+// operations already applied are undone. If message is non-empty, the affected paths are
+// then committed (and everything is rolled back if that fails).
+// Returns the commit hash, or "" when no commit was requested.
+func (p *LocalProvider) ApplyChangeSet(ops []ChangeOp, message string) (string, error) {
+	if len(ops) == 0 {
+		return "", fmt.Errorf("invalid change set: no operations")
+	}
+
+	// Keep checkouts from switching the working tree underneath the batch.
+	// CommitWithOptions does not take the lock, so it can be called while holding it.
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ops, paths, err := p.validateChangeSet(ops)
+	if err != nil {
+		return "", err
+	}
+
+	var undo []func() error
+	rollback := func(cause error) error {
+		var errs []error
+		for i := len(undo) - 1; i >= 0; i-- {
+			if err := undo[i](); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if len(errs) > 0 {
+			return fmt.Errorf("%w; rollback failed: %v", cause, errors.Join(errs...))
+		}
+		return cause
+	}
+
+	for i, op := range ops {
+		revert, err := p.applyChangeOp(op)
+		if err != nil {
+			return "", rollback(&ChangeSetError{Index: i, Op: op.Op, Err: err})
+		}
+		undo = append(undo, revert)
+	}
+
+	if message == "" {
+		return "", nil
+	}
+
+	hash, err := p.CommitWithOptions(CommitOptions{Message: message, Paths: paths})
+	if err != nil {
+		// The paths may be staged already; unstage them before undoing the changes
+		undo = append(undo, p.resetIndex)
+		return "", rollback(err)
+	}
+
+	return hash, nil
+}
+
+// validateChangeSet normalizes the operations and checks them in order against a
+// view of the working tree. Returns the normalized operations and the paths a
+// commit of the change set has to include.
+func (p *LocalProvider) validateChangeSet(ops []ChangeOp) ([]ChangeOp, []string, error) {
+	view := p.newChangeSetView()
+
+	touched := make(map[string]bool)
+	normalized := make([]ChangeOp, len(ops))
+	for i, op := range ops {
+		op, err := normalizeChangeOp(op)
+		if err == nil {
+			err = p.checkChangeOpBase(op)
+		}
+		if err == nil {
+			err = view.apply(op)
+		}
+		if err != nil {
+			return nil, nil, &ChangeSetError{Index: i, Op: op.Op, Err: err}
+		}
+
+		for _, path := range []string{op.Path, op.OldPath, op.NewPath} {
+			if path != "" {
+				touched[path] = true
+			}
+		}
+		normalized[i] = op
+	}
+
+	headTree, err := p.headTree()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Commit what ends up on disk and the removal of what was committed before;
+	// untracked paths that are gone again have nothing to commit
+	committable := make(map[string]bool)
+	for path := range touched {
+		if view.exists(path) {
+			committable[path] = true
+		} else if headTree != nil {
+			if _, err := headTree.FindEntry(path); err == nil {
+				committable[path] = true
+			}
+		}
+	}
+
+	// Paths inside a committed folder are already covered by it
+	var paths []string
+	for path := range committable {
+		covered := false
+		for dir := parentDir(path); dir != "" && !covered; dir = parentDir(dir) {
+			covered = committable[dir]
+		}
+		if !covered {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	return normalized, paths, nil
+}
+
+// normalizeChangeOp checks an operation's type and required paths and normalizes them.
+func normalizeChangeOp(op ChangeOp) (ChangeOp, error) {
+	var err error
+	switch op.Op {
+	case OpWrite, OpDelete:
+		op.Path, err = normalizeChangePath(op.Path)
+	case OpMove, OpMoveFolder:
+		if op.OldPath, err = normalizeChangePath(op.OldPath); err == nil {
+			op.NewPath, err = normalizeChangePath(op.NewPath)
+		}
+	default:
+		err = fmt.Errorf("invalid operation: %q", op.Op)
+	}
+	return op, err
+}
+
+// checkChangeOpBase checks that the file an operation edits still matches its base hash.
+func (p *LocalProvider) checkChangeOpBase(op ChangeOp) error {
+	switch op.Op {
+	case OpWrite, OpDelete:
+		return p.checkBaseHash(op.Path, op.BaseHash)
+	case OpMove:
+		return p.checkBaseHash(op.OldPath, op.BaseHash)
+	}
+	return nil
+}
+
+// normalizeChangePath strips slashes and rejects empty paths and paths escaping the repository.
+func normalizeChangePath(path string) (string, error) {
+	// Normalize path: strip leading/trailing slashes, convert to forward slashes
+	path = strings.Trim(filepath.ToSlash(path), "/")
+
+	if path == "" {
+		return "", fmt.Errorf("invalid path: path cannot be empty")
+	}
+
+	// Security: validate path doesn't escape repository root
+	if strings.Contains(path, "..") {
+		return "", fmt.Errorf("invalid path: cannot contain '..'")
+	}
+
+	// Security: never touch the repository's own data
+	if IsGitDirPath(path) {
+		return "", fmt.Errorf("invalid path: cannot modify the .git directory")
+	}
+
+	return path, nil
+}
+
+// applyChangeOp applies a validated operation to the working tree and returns
+// a function that undoes it.
+func (p *LocalProvider) applyChangeOp(op ChangeOp) (func() error, error) {
+	switch op.Op {
+	case OpWrite:
+		fullPath := filepath.Join(p.path, filepath.FromSlash(op.Path))
+		previous, err := os.ReadFile(fullPath)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		existed := err == nil
+		var mode os.FileMode
+		if existed {
+			info, err := os.Stat(fullPath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to stat file: %w", err)
+			}
+			mode = info.Mode().Perm()
+		}
+
+		created := p.missingDirs(op.Path)
+		if err := p.WriteFile(op.Path, []byte(op.Content)); err != nil {
+			removeDirs(created)
+			return nil, err
+		}
+
+		return func() error {
+			if existed {
+				if err := os.WriteFile(fullPath, previous, mode); err != nil {
+					return fmt.Errorf("failed to restore %s: %w", op.Path, err)
+				}
+				if err := os.Chmod(fullPath, mode); err != nil {
+					return fmt.Errorf("failed to restore mode of %s: %w", op.Path, err)
+				}
+			} else if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove %s: %w", op.Path, err)
+			}
+			return removeDirs(created)
+		}, nil
+
+	case OpDelete:
+		fullPath := filepath.Join(p.path, filepath.FromSlash(op.Path))
+		info, err := os.Stat(fullPath)
+		if err != nil {
+			return nil, fmt.Errorf("file not found")
+		}
+		previous, err := os.ReadFile(fullPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+
+		if err := p.DeleteFile(op.Path); err != nil {
+			return nil, err
+		}
+
+		return func() error {
+			if err := os.WriteFile(fullPath, previous, info.Mode().Perm()); err != nil {
+				return fmt.Errorf("failed to restore %s: %w", op.Path, err)
+			}
+			return nil
+		}, nil
+
+	default: // OpMove, OpMoveFolder
+		move := p.MoveFile
+		if op.Op == OpMoveFolder {
+			move = p.MoveFolder
+		}
+
+		created := p.missingDirs(op.NewPath)
+		if err := move(op.OldPath, op.NewPath); err != nil {
+			removeDirs(created)
+			return nil, err
+		}
+
+		return func() error {
+			oldFullPath := filepath.Join(p.path, filepath.FromSlash(op.OldPath))
+			newFullPath := filepath.Join(p.path, filepath.FromSlash(op.NewPath))
+			if err := os.Rename(newFullPath, oldFullPath); err != nil {
+				return fmt.Errorf("failed to move %s back to %s: %w", op.NewPath, op.OldPath, err)
+			}
+			return removeDirs(created)
+		}, nil
+	}
+}
+
+// missingDirs returns the parent folders of path that do not exist yet, outermost first.
+func (p *LocalProvider) missingDirs(path string) []string {
+	var missing []string
+	for dir := parentDir(path); dir != ""; dir = parentDir(dir) {
+		fullPath := filepath.Join(p.path, filepath.FromSlash(dir))
+		if _, err := os.Stat(fullPath); err == nil {
+			break
+		}
+		missing = append([]string{fullPath}, missing...)
+	}
+	return missing
+}
+
+// removeDirs removes folders created by an operation, innermost first.
+func removeDirs(dirs []string) error {
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := os.Remove(dirs[i]); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove folder: %w", err)
+		}
+	}
+	return nil
+}
+
+// parentDir returns the parent folder of a slash-separated path, or "" at the top level.
+func parentDir(path string) string {
+	i := strings.LastIndex(path, "/")
+	if i < 0 {
+		return ""
+	}
+	return path[:i]
+}
+
+// pathKind is what a path holds in a changeSetView.
+type pathKind int
+
+const (
+	pathMissing pathKind = iota
+	pathFile
+	pathDir
+)
+
+// changeSetView tracks which files and folders exist while a change set is
+// validated. Paths the operations have touched are recorded in an overlay;
+// everything else is looked up on disk, so validation only stats the paths
+// involved instead of listing the whole working tree. An overlay entry
+// replaces the disk's content at that path and everything beneath it.
+type changeSetView struct {
+	root    string
+	entries map[string]pathKind
+}
+
+// newChangeSetView creates a view of the working tree with no operations applied.
+func (p *LocalProvider) newChangeSetView() *changeSetView {
+	return &changeSetView{root: p.path, entries: make(map[string]pathKind)}
+}
+
+// kind reports what path currently holds in the view.
+func (v *changeSetView) kind(path string) pathKind {
+	if kind, ok := v.entries[path]; ok {
+		return kind
+	}
+	if v.overlaid(parentDir(path)) {
+		// Anything beneath an overlay entry is itself in the overlay
+		return pathMissing
+	}
+	if IsGitDirPath(path) {
+		return pathMissing
+	}
+
+	info, err := os.Lstat(filepath.Join(v.root, filepath.FromSlash(path)))
+	if err != nil {
+		return pathMissing
+	}
+	if info.IsDir() {
+		return pathDir
+	}
+	return pathFile
+}
+
+// overlaid reports whether path or one of its parents has an overlay entry.
+func (v *changeSetView) overlaid(path string) bool {
+	for dir := path; dir != ""; dir = parentDir(dir) {
+		if _, ok := v.entries[dir]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// exists reports whether path is a file or folder.
+func (v *changeSetView) exists(path string) bool {
+	return v.kind(path) != pathMissing
+}
+
+// apply checks an operation against the view and records its effect,
+// failing the same way the operation would on disk.
+func (v *changeSetView) apply(op ChangeOp) error {
+	switch op.Op {
+	case OpWrite:
+		if v.kind(op.Path) == pathDir {
+			return fmt.Errorf("path is a directory, not a file")
+		}
+		if err := v.addParents(op.Path); err != nil {
+			return err
+		}
+		v.entries[op.Path] = pathFile
+
+	case OpDelete:
+		switch v.kind(op.Path) {
+		case pathDir:
+			return fmt.Errorf("path is a directory, not a file")
+		case pathMissing:
+			return fmt.Errorf("file not found")
+		}
+		v.entries[op.Path] = pathMissing
+
+	case OpMove:
+		switch v.kind(op.OldPath) {
+		case pathDir:
+			return fmt.Errorf("source path is a directory, not a file")
+		case pathMissing:
+			return fmt.Errorf("source file not found")
+		}
+		if v.exists(op.NewPath) {
+			return fmt.Errorf("destination file already exists")
+		}
+		if err := v.addParents(op.NewPath); err != nil {
+			return err
+		}
+		v.entries[op.OldPath] = pathMissing
+		v.entries[op.NewPath] = pathFile
+
+	case OpMoveFolder:
+		if strings.HasPrefix(op.NewPath, op.OldPath+"/") {
+			return fmt.Errorf("cannot move folder into itself")
+		}
+		switch v.kind(op.OldPath) {
+		case pathFile:
+			return fmt.Errorf("source path is not a directory")
+		case pathMissing:
+			return fmt.Errorf("source folder not found")
+		}
+		if v.exists(op.NewPath) {
+			return fmt.Errorf("destination folder already exists")
+		}
+		if err := v.addParents(op.NewPath); err != nil {
+			return err
+		}
+		return v.moveFolder(op.OldPath, op.NewPath)
+	}
+
+	return nil
+}
+
+// addParents records the folders that creating path would create.
+func (v *changeSetView) addParents(path string) error {
+	var missing []string
+	for dir := parentDir(path); dir != ""; dir = parentDir(dir) {
+		switch v.kind(dir) {
+		case pathFile:
+			return fmt.Errorf("failed to create directories: %s is a file", dir)
+		case pathMissing:
+			missing = append(missing, dir)
+		}
+	}
+	for _, dir := range missing {
+		v.entries[dir] = pathDir
+	}
+	return nil
+}
+
+// moveFolder records moving the folder oldPath and everything beneath it to newPath.
+// Only the moved folder is listed on disk.
+func (v *changeSetView) moveFolder(oldPath, newPath string) error {
+	contents := make(map[string]pathKind)
+	for path, kind := range v.entries {
+		if strings.HasPrefix(path, oldPath+"/") {
+			contents[path] = kind
+		}
+	}
+
+	if !v.overlaid(oldPath) {
+		oldFullPath := filepath.Join(v.root, filepath.FromSlash(oldPath))
+		err := filepath.WalkDir(oldFullPath, func(fullPath string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(v.root, fullPath)
+			if err != nil {
+				return err
+			}
+			rel = filepath.ToSlash(rel)
+
+			if rel == oldPath {
+				return nil
+			}
+			if _, ok := contents[rel]; ok || v.overlaid(parentDir(rel)) {
+				// The overlay already describes this path
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				contents[rel] = pathDir
+			} else {
+				contents[rel] = pathFile
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list folder: %w", err)
+		}
+	}
+
+	for path := range contents {
+		delete(v.entries, path)
+	}
+	v.entries[oldPath] = pathMissing
+	v.entries[newPath] = pathDir
+	for path, kind := range contents {
+		if kind != pathMissing {
+			v.entries[newPath+strings.TrimPrefix(path, oldPath)] = kind
+		}
+	}
+	return nil
+}
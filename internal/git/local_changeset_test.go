@@ -0,0 +1,290 @@
+package git
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// createChangeSetProvider creates a repository with docs/a.md, docs/b.md and
+// old.md committed, and returns its directory and a provider for it.
+func createChangeSetProvider(t *testing.T) (string, *LocalProvider) {
+	t.Helper()
+	isolateGitConfig(t, "")
+
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	for _, path := range []string{"docs/a.md", "docs/b.md", "old.md"} {
+		if err := provider.WriteFile(path, []byte(path)); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+	if _, err := provider.Commit("Add files"); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	return tempDir, provider
+}
+
+// changeSetOps exercises every operation type; later operations depend on earlier ones.
+var changeSetOps = []ChangeOp{
+	{Op: OpWrite, Path: "new/page.md", Content: "page"},
+	{Op: OpWrite, Path: "old.md", Content: "edited"},
+	{Op: OpMove, OldPath: "old.md", NewPath: "renamed.md"},
+	{Op: OpDelete, Path: "docs/b.md"},
+	{Op: OpMoveFolder, OldPath: "docs", NewPath: "guides/docs"},
+	{Op: OpWrite, Path: "guides/docs/c.md", Content: "c"},
+}
+
+func TestApplyChangeSet(t *testing.T) {
+	tempDir, provider := createChangeSetProvider(t)
+
+	hash, err := provider.ApplyChangeSet(changeSetOps, "")
+	if err != nil {
+		t.Fatalf("ApplyChangeSet failed: %v", err)
+	}
+	if hash != "" {
+		t.Errorf("expected no commit without a message, got %s", hash)
+	}
+
+	expected := map[string]string{
+		"new/page.md":      "page",
+		"renamed.md":       "edited",
+		"guides/docs/a.md": "docs/a.md",
+		"guides/docs/c.md": "c",
+		"old.md":           "",
+		"docs/a.md":        "",
+		"guides/docs/b.md": "",
+	}
+	for path, content := range expected {
+		if got := readTestFile(t, tempDir, path); got != content {
+			t.Errorf("expected %s to contain %q, got %q", path, content, got)
+		}
+	}
+}
+
+func TestApplyChangeSet_Commit(t *testing.T) {
+	tempDir, provider := createChangeSetProvider(t)
+
+	// Unrelated changes are not swept into the commit
+	if err := os.WriteFile(filepath.Join(tempDir, "scratch.md"), []byte("scratch"), 0644); err != nil {
+		t.Fatalf("failed to write scratch file: %v", err)
+	}
+
+	hash, err := provider.ApplyChangeSet(changeSetOps, "Reorganize docs")
+	if err != nil {
+		t.Fatalf("ApplyChangeSet failed: %v", err)
+	}
+
+	commit, err := provider.repo.CommitObject(plumbing.NewHash(hash))
+	if err != nil {
+		t.Fatalf("failed to load commit: %v", err)
+	}
+	if commit.Message != "Reorganize docs" {
+		t.Errorf("unexpected commit message %q", commit.Message)
+	}
+
+	files := committedFiles(t, provider)
+	for _, path := range []string{"new/page.md", "renamed.md", "guides/docs/a.md", "guides/docs/c.md"} {
+		if !files[path] {
+			t.Errorf("expected %s to be committed", path)
+		}
+	}
+	for _, path := range []string{"old.md", "docs/a.md", "docs/b.md", "scratch.md"} {
+		if files[path] {
+			t.Errorf("expected %s not to be in the commit", path)
+		}
+	}
+
+	content, err := provider.readFileFromCommit(hash, "renamed.md")
+	if err != nil {
+		t.Fatalf("failed to read renamed.md: %v", err)
+	}
+	if string(content) != "edited" {
+		t.Errorf("expected edited content to be committed, got %q", content)
+	}
+}
+
+func TestApplyChangeSet_ValidationFailsBeforeChanges(t *testing.T) {
+	tempDir, provider := createChangeSetProvider(t)
+
+	_, err := provider.ApplyChangeSet([]ChangeOp{
+		{Op: OpWrite, Path: "new.md", Content: "new"},
+		{Op: OpMove, OldPath: "old.md", NewPath: "moved.md"},
+		{Op: OpDelete, Path: "old.md"}, // already moved away
+	}, "")
+
+	var opErr *ChangeSetError
+	if !errors.As(err, &opErr) {
+		t.Fatalf("expected ChangeSetError, got %v", err)
+	}
+	if opErr.Index != 2 || opErr.Op != OpDelete || !strings.Contains(err.Error(), "file not found") {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	// Nothing was applied
+	if got := readTestFile(t, tempDir, "new.md"); got != "" {
+		t.Errorf("expected new.md not to be written, got %q", got)
+	}
+	if got := readTestFile(t, tempDir, "old.md"); got != "old.md" {
+		t.Errorf("expected old.md to be untouched, got %q", got)
+	}
+}
+
+func TestApplyChangeSet_RollbackOnCommitFailure(t *testing.T) {
+	tempDir, provider := createChangeSetProvider(t)
+
+	// An invalid author makes the commit fail after every operation has been applied
+	provider.SetDefaultAuthor("Bad <name>", "bad@example.com")
+
+	_, err := provider.ApplyChangeSet(changeSetOps, "Reorganize docs")
+	if err == nil || !strings.Contains(err.Error(), "invalid author") {
+		t.Fatalf("expected invalid author error, got %v", err)
+	}
+
+	expected := map[string]string{
+		"old.md":           "old.md",
+		"docs/a.md":        "docs/a.md",
+		"docs/b.md":        "docs/b.md",
+		"new/page.md":      "",
+		"renamed.md":       "",
+		"guides/docs/c.md": "",
+	}
+	for path, content := range expected {
+		if got := readTestFile(t, tempDir, path); got != content {
+			t.Errorf("expected %s to contain %q, got %q", path, content, got)
+		}
+	}
+
+	// Folders created by the change set are removed again
+	for _, dir := range []string{"new", "guides"} {
+		if _, err := os.Stat(filepath.Join(tempDir, dir)); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed, got %v", dir, err)
+		}
+	}
+
+	status, err := provider.Status()
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if status.IsDirty {
+		t.Errorf("expected clean working tree after rollback, got %+v", status)
+	}
+}
+
+// failingSigner is a commit signer that always fails, so that commits fail
+// after their changes have been staged.
+type failingSigner struct{}
+
+func (failingSigner) Sign(message io.Reader) ([]byte, error) {
+	return nil, errors.New("signing failed")
+}
+func (failingSigner) format() string { return SigningFormatOpenPGP }
+func (failingSigner) verify(message io.Reader, signature string) string {
+	return SignatureUnknown
+}
+
+func TestApplyChangeSet_RollbackAfterStaging(t *testing.T) {
+	tempDir, provider := createChangeSetProvider(t)
+	provider.signer = failingSigner{}
+
+	if err := os.Chmod(filepath.Join(tempDir, "old.md"), 0755); err != nil {
+		t.Fatalf("Chmod failed: %v", err)
+	}
+
+	ops := []ChangeOp{
+		{Op: OpWrite, Path: "old.md", Content: "changed"},
+		{Op: OpWrite, Path: "added.md", Content: "added"},
+	}
+	if _, err := provider.ApplyChangeSet(ops, "Change old"); err == nil || !strings.Contains(err.Error(), "signing failed") {
+		t.Fatalf("expected signing error, got %v", err)
+	}
+
+	// The file is restored with its mode, and nothing is left staged
+	if got := readTestFile(t, tempDir, "old.md"); got != "old.md" {
+		t.Errorf("expected old.md to be restored, got %q", got)
+	}
+	if info, err := os.Stat(filepath.Join(tempDir, "old.md")); err != nil || info.Mode().Perm() != 0755 {
+		t.Errorf("expected old.md to keep mode 0755, got %v, %v", info, err)
+	}
+
+	w, err := provider.repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	status, err := w.Status()
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	for path, fileStatus := range status {
+		if fileStatus.Staging != git.Unmodified && fileStatus.Staging != git.Untracked {
+			t.Errorf("expected %s to be unstaged, got %c", path, fileStatus.Staging)
+		}
+	}
+}
+
+func TestApplyChangeSet_Errors(t *testing.T) {
+	_, provider := createChangeSetProvider(t)
+
+	tests := []struct {
+		name        string
+		ops         []ChangeOp
+		errContains string
+	}{
+		{"no operations", nil, "no operations"},
+		{"unknown operation", []ChangeOp{{Op: "copy", Path: "a.md"}}, "invalid operation"},
+		{"empty path", []ChangeOp{{Op: OpWrite, Path: "/"}}, "path cannot be empty"},
+		{"path traversal", []ChangeOp{{Op: OpDelete, Path: "../outside.md"}}, "cannot contain '..'"},
+		{"git directory", []ChangeOp{{Op: OpWrite, Path: ".git/hooks/pre-commit", Content: "#!/bin/sh"}}, "cannot modify the .git directory"},
+		{"move into git directory", []ChangeOp{{Op: OpMove, OldPath: "old.md", NewPath: "/.git/config"}}, "cannot modify the .git directory"},
+		{"missing new path", []ChangeOp{{Op: OpMove, OldPath: "old.md"}}, "path cannot be empty"},
+		{"write over folder", []ChangeOp{{Op: OpWrite, Path: "docs"}}, "is a directory"},
+		{"write below file", []ChangeOp{{Op: OpWrite, Path: "old.md/child.md"}}, "old.md is a file"},
+		{"delete folder", []ChangeOp{{Op: OpDelete, Path: "docs"}}, "is a directory"},
+		{"move onto file", []ChangeOp{{Op: OpMove, OldPath: "old.md", NewPath: "docs/a.md"}}, "already exists"},
+		{"move folder into itself", []ChangeOp{{Op: OpMoveFolder, OldPath: "docs", NewPath: "docs/sub"}}, "into itself"},
+		{"move missing folder", []ChangeOp{{Op: OpMoveFolder, OldPath: "missing", NewPath: "other"}}, "source folder not found"},
+		{"old path after folder move", []ChangeOp{
+			{Op: OpMoveFolder, OldPath: "docs", NewPath: "guides"},
+			{Op: OpDelete, Path: "docs/a.md"},
+		}, "operation 1 (delete): file not found"},
+		{"deleted file after folder moves", []ChangeOp{
+			{Op: OpWrite, Path: "docs/c.md", Content: "c"},
+			{Op: OpDelete, Path: "docs/b.md"},
+			{Op: OpMoveFolder, OldPath: "docs", NewPath: "guides"},
+			{Op: OpMoveFolder, OldPath: "guides", NewPath: "more"},
+			{Op: OpDelete, Path: "more/a.md"},
+			{Op: OpDelete, Path: "more/c.md"},
+			{Op: OpDelete, Path: "more/b.md"},
+		}, "operation 6 (delete): file not found"},
+		{"folder moved back over its old place", []ChangeOp{
+			{Op: OpMoveFolder, OldPath: "docs", NewPath: "guides"},
+			{Op: OpWrite, Path: "docs/new.md", Content: "new"},
+			{Op: OpMoveFolder, OldPath: "guides", NewPath: "docs"},
+		}, "destination folder already exists"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := provider.ApplyChangeSet(tt.ops, "")
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("expected error containing %q, got %v", tt.errContains, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,539 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Checkout modes control what happens to uncommitted changes when switching branches.
+const (
+	CheckoutClean = ""      // refuse to switch if the working tree is dirty
+	CheckoutStash = "stash" // set changes aside for the old branch; restored when switching back
+	CheckoutCarry = "carry" // bring changes along to the new branch
+)
+
+// stashDir is where stashed changes are kept, relative to the .git directory.
+const stashDir = "giki/stash"
+
+// carryFile holds changes being carried to another branch while the checkout
+// is in progress, relative to the .git directory, so they survive a failed
+// switch or a crash.
+const carryFile = "giki/carry.json"
+
+// worktreeChange is an uncommitted change to a single file.
+type worktreeChange struct {
+	Path    string `json:"path"`
+	Content []byte `json:"content,omitempty"` // working tree content; nil if deleted
+	Deleted bool   `json:"deleted,omitempty"`
+}
+
+// stash is the set of uncommitted changes saved for a branch.
+type stash struct {
+	Branch  string           `json:"branch"`
+	Base    string           `json:"base"` // commit the changes were made on top of
+	Changes []worktreeChange `json:"changes"`
+}
+
+// Checkout switches the working tree to another branch and makes it the current branch.
+// A branch that only exists on a remote (e.g. "feature" or "origin/feature") gets a
+// local tracking branch. When the working tree is dirty, mode decides what happens:
+// CheckoutClean refuses, CheckoutStash saves the changes for the old branch, and
+// CheckoutCarry brings them along unless they conflict with the new branch.
+// Changes stashed for the target branch are restored when switching with a clean tree.
+func (p *LocalProvider) Checkout(branch, mode string) error {
+	if mode != CheckoutClean && mode != CheckoutStash && mode != CheckoutCarry {
+		return fmt.Errorf("invalid checkout mode: %s", mode)
+	}
+
+	branch = strings.TrimSpace(branch)
+	if branch == "" {
+		return fmt.Errorf("branch cannot be empty")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Put back changes from a carry that was interrupted
+	if err := p.recoverCarry(); err != nil {
+		return err
+	}
+
+	target, targetHash, tracked, err := p.checkoutTarget(branch)
+	if err != nil {
+		return err
+	}
+	branch = target.Short()
+
+	worktree, err := p.repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree: %w", err)
+	}
+
+	headTree, err := p.headTree()
+	if err != nil {
+		return err
+	}
+
+	changes, err := p.worktreeChanges(worktree, headTree)
+	if err != nil {
+		return err
+	}
+
+	// Refuse before changing anything, including creating the target branch
+	if len(changes) > 0 {
+		switch mode {
+		case CheckoutClean:
+			return fmt.Errorf("working tree has uncommitted changes; commit them or choose to stash or carry them")
+		case CheckoutStash:
+			if p.branch == "HEAD" {
+				return fmt.Errorf("cannot stash changes in detached HEAD state")
+			}
+		case CheckoutCarry:
+			if err := p.checkCarryConflicts(headTree, targetHash, changes); err != nil {
+				return err
+			}
+		}
+	}
+
+	if tracked != nil {
+		if err := p.createTrackingBranch(target, tracked); err != nil {
+			return err
+		}
+	}
+
+	if len(changes) > 0 {
+		switch mode {
+		case CheckoutStash:
+			if err := p.saveStash(p.branch, changes); err != nil {
+				return err
+			}
+			if err := p.revertChanges(headTree, changes); err != nil {
+				return err
+			}
+		case CheckoutCarry:
+			return p.checkoutCarry(worktree, target, changes)
+		}
+	}
+
+	if err := worktree.Checkout(&git.CheckoutOptions{Branch: target, Force: true}); err != nil {
+		return fmt.Errorf("failed to checkout branch '%s': %w", branch, err)
+	}
+	p.branch = branch
+
+	if err := p.restoreStash(branch, targetHash); err != nil {
+		return fmt.Errorf("switched to branch '%s', but its stashed changes were not restored: %w", branch, err)
+	}
+
+	return nil
+}
+
+// checkoutCarry switches to target and reapplies changes there. The changes are
+// saved to disk first; if the switch or reapplying them fails, the old branch is
+// checked out again with the changes restored. Must be called with p.mu held.
+func (p *LocalProvider) checkoutCarry(worktree *git.Worktree, target plumbing.ReferenceName, changes []worktreeChange) error {
+	head, err := p.repo.Head()
+	if err != nil {
+		return fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+
+	if err := p.writeStashFile(p.carryPath(), stash{Branch: target.Short(), Base: head.Hash().String(), Changes: changes}); err != nil {
+		return err
+	}
+
+	err = worktree.Checkout(&git.CheckoutOptions{Branch: target, Force: true})
+	if err == nil {
+		err = p.applyChanges(changes)
+		if err == nil {
+			p.branch = target.Short()
+			return p.removeCarry()
+		}
+	}
+	err = fmt.Errorf("failed to carry changes to branch '%s': %w", target.Short(), err)
+
+	// Go back to where the changes were made and put them back
+	previous := &git.CheckoutOptions{Hash: head.Hash(), Force: true}
+	if head.Name().IsBranch() {
+		previous = &git.CheckoutOptions{Branch: head.Name(), Force: true}
+	}
+	if restoreErr := worktree.Checkout(previous); restoreErr != nil {
+		return fmt.Errorf("%w; changes are saved in %s: %v", err, p.carryPath(), restoreErr)
+	}
+	if restoreErr := p.applyChanges(changes); restoreErr != nil {
+		return fmt.Errorf("%w; changes are saved in %s: %v", err, p.carryPath(), restoreErr)
+	}
+	if removeErr := p.removeCarry(); removeErr != nil {
+		return fmt.Errorf("%w; %v", err, removeErr)
+	}
+
+	return err
+}
+
+// checkoutTarget resolves the local branch to check out and the commit it is at.
+// When the name only exists as a remote-tracking branch, it also returns that
+// branch, which the local branch must be created from with createTrackingBranch.
+func (p *LocalProvider) checkoutTarget(branch string) (target plumbing.ReferenceName, hash plumbing.Hash, tracked *plumbing.Reference, err error) {
+	local := plumbing.NewBranchReferenceName(branch)
+	if ref, err := p.repo.Reference(local, true); err == nil {
+		return local, ref.Hash(), nil, nil
+	}
+
+	remoteRef, err := p.findRemoteBranch(branch)
+	if err != nil {
+		return "", plumbing.ZeroHash, nil, err
+	}
+
+	// "origin/feature" and "feature" both become the local branch "feature"
+	_, localName, _ := strings.Cut(remoteRef.Name().Short(), "/")
+	local = plumbing.NewBranchReferenceName(localName)
+	if ref, err := p.repo.Reference(local, true); err == nil {
+		return local, ref.Hash(), nil, nil
+	}
+
+	return local, remoteRef.Hash(), remoteRef, nil
+}
+
+// createTrackingBranch creates the local branch local at the remote-tracking
+// branch remoteRef, configured to track it.
+func (p *LocalProvider) createTrackingBranch(local plumbing.ReferenceName, remoteRef *plumbing.Reference) error {
+	remoteName, _, _ := strings.Cut(remoteRef.Name().Short(), "/")
+	localName := local.Short()
+
+	if err := p.repo.Storer.SetReference(plumbing.NewHashReference(local, remoteRef.Hash())); err != nil {
+		return fmt.Errorf("failed to create branch '%s': %w", localName, err)
+	}
+
+	err := p.repo.CreateBranch(&config.Branch{
+		Name:   localName,
+		Remote: remoteName,
+		Merge:  local,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to configure tracking for '%s': %w", localName, err)
+	}
+
+	return nil
+}
+
+// findRemoteBranch finds a remote-tracking branch by its full short name
+// ("origin/feature") or by the branch name alone ("feature").
+func (p *LocalProvider) findRemoteBranch(branch string) (*plumbing.Reference, error) {
+	refs, err := p.repo.References()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list references: %w", err)
+	}
+	defer refs.Close()
+
+	var matches []*plumbing.Reference
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if !ref.Name().IsRemote() || ref.Type() != plumbing.HashReference {
+			return nil
+		}
+
+		name := ref.Name().Short()
+		if _, localName, _ := strings.Cut(name, "/"); name == branch || localName == branch {
+			matches = append(matches, ref)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate references: %w", err)
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("branch '%s' not found", branch)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("branch '%s' is ambiguous: it exists on more than one remote", branch)
+	}
+}
+
+// worktreeChanges returns the uncommitted changes in the working tree, sorted by path.
+func (p *LocalProvider) worktreeChanges(worktree *git.Worktree, headTree *object.Tree) ([]worktreeChange, error) {
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status: %w", err)
+	}
+
+	var changes []worktreeChange
+	for path, fileStatus := range status {
+		if fileStatus.Staging == git.Unmodified && fileStatus.Worktree == git.Unmodified {
+			continue
+		}
+
+		content, err := p.readWorkingFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if content == nil {
+			// Nothing to carry if the file is gone and was never committed
+			committed, err := readTreeFile(headTree, path)
+			if err != nil {
+				return nil, err
+			}
+			if committed == nil {
+				continue
+			}
+		}
+
+		changes = append(changes, worktreeChange{Path: path, Content: content, Deleted: content == nil})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes, nil
+}
+
+// checkCarryConflicts refuses to carry changes to files that also differ
+// between the current and target branches, as checking out would overwrite them.
+func (p *LocalProvider) checkCarryConflicts(headTree *object.Tree, target plumbing.Hash, changes []worktreeChange) error {
+	targetTree, err := p.commitTree(target)
+	if err != nil {
+		return err
+	}
+
+	var conflicts []string
+	for _, change := range changes {
+		current, err := readTreeFile(headTree, change.Path)
+		if err != nil {
+			return err
+		}
+		other, err := readTreeFile(targetTree, change.Path)
+		if err != nil {
+			return err
+		}
+
+		if (current == nil) != (other == nil) || string(current) != string(other) {
+			conflicts = append(conflicts, change.Path)
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return fmt.Errorf("checkout conflict: local changes would be overwritten: %s", strings.Join(conflicts, ", "))
+	}
+
+	return nil
+}
+
+// applyChanges writes changes into the working tree.
+func (p *LocalProvider) applyChanges(changes []worktreeChange) error {
+	for _, change := range changes {
+		fullPath := filepath.Join(p.path, filepath.FromSlash(change.Path))
+
+		if change.Deleted {
+			if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to delete %s: %w", change.Path, err)
+			}
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			return fmt.Errorf("failed to create directories: %w", err)
+		}
+		if err := os.WriteFile(fullPath, change.Content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", change.Path, err)
+		}
+	}
+
+	return nil
+}
+
+// revertChanges restores the committed version of every changed file,
+// removing files that are not in the tree.
+func (p *LocalProvider) revertChanges(headTree *object.Tree, changes []worktreeChange) error {
+	reverted := make([]worktreeChange, 0, len(changes))
+	for _, change := range changes {
+		content, err := readTreeFile(headTree, change.Path)
+		if err != nil {
+			return err
+		}
+		reverted = append(reverted, worktreeChange{Path: change.Path, Content: content, Deleted: content == nil})
+	}
+
+	return p.applyChanges(reverted)
+}
+
+// stashPath returns the file holding the stash for a branch.
+func (p *LocalProvider) stashPath(branch string) string {
+	return filepath.Join(p.path, ".git", filepath.FromSlash(stashDir), filepath.FromSlash(branch)+".json")
+}
+
+// saveStash records changes for a branch, replacing any earlier stash.
+func (p *LocalProvider) saveStash(branch string, changes []worktreeChange) error {
+	head, err := p.repo.Head()
+	if err != nil {
+		return fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+
+	return p.writeStashFile(p.stashPath(branch), stash{Branch: branch, Base: head.Hash().String(), Changes: changes})
+}
+
+// writeStashFile saves changes to path.
+func (p *LocalProvider) writeStashFile(path string, saved stash) error {
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return fmt.Errorf("failed to encode stash: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create stash directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to save stash: %w", err)
+	}
+
+	return nil
+}
+
+// carryPath returns the file holding changes being carried to another branch.
+func (p *LocalProvider) carryPath() string {
+	return filepath.Join(p.path, ".git", filepath.FromSlash(carryFile))
+}
+
+// removeCarry deletes the saved carry once the changes are safely in the working tree.
+func (p *LocalProvider) removeCarry() error {
+	if err := os.Remove(p.carryPath()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove carried changes: %w", err)
+	}
+	return nil
+}
+
+// recoverCarry reapplies changes left over from a carry that never finished,
+// for example because the process stopped mid-checkout, and removes them.
+// The saved changes are full file contents, so applying them again is harmless.
+func (p *LocalProvider) recoverCarry() error {
+	data, err := os.ReadFile(p.carryPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read carried changes: %w", err)
+	}
+
+	var saved stash
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return fmt.Errorf("failed to decode carried changes: %w", err)
+	}
+
+	if err := p.applyChanges(saved.Changes); err != nil {
+		return err
+	}
+	return p.removeCarry()
+}
+
+// restoreStash applies and removes the stash for a branch, if there is one.
+// If the branch has moved on from the commit the changes were made on, for
+// example through a pull or commit, they are merged with the branch's newer
+// content; if that conflicts, nothing is applied and the stash is kept.
+func (p *LocalProvider) restoreStash(branch string, tip plumbing.Hash) error {
+	path := p.stashPath(branch)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read stash: %w", err)
+	}
+
+	var saved stash
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return fmt.Errorf("failed to decode stash: %w", err)
+	}
+
+	changes := saved.Changes
+	if saved.Base != tip.String() {
+		changes, err = p.rebaseChanges(changes, plumbing.NewHash(saved.Base), tip)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := p.applyChanges(changes); err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove stash: %w", err)
+	}
+
+	return nil
+}
+
+// rebaseChanges moves changes made on top of commit base onto commit tip,
+// merging each changed file with its content at tip. Returns an error listing
+// the files that cannot be merged.
+func (p *LocalProvider) rebaseChanges(changes []worktreeChange, base, tip plumbing.Hash) ([]worktreeChange, error) {
+	baseTree, err := p.commitTree(base)
+	if err != nil {
+		return nil, err
+	}
+	tipTree, err := p.commitTree(tip)
+	if err != nil {
+		return nil, err
+	}
+
+	rebased := make([]worktreeChange, 0, len(changes))
+	var conflicts []string
+	for _, change := range changes {
+		original, err := readTreeFile(baseTree, change.Path)
+		if err != nil {
+			return nil, err
+		}
+		current, err := readTreeFile(tipTree, change.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case (original == nil) == (current == nil) && string(original) == string(current):
+			// Unchanged on the branch: the change applies as it is
+			rebased = append(rebased, change)
+		case change.Deleted == (current == nil) && string(change.Content) == string(current):
+			// The branch already has the change
+		case change.Deleted || original == nil || current == nil:
+			conflicts = append(conflicts, change.Path)
+		default:
+			merged := MergeText(string(original), string(change.Content), string(current))
+			if merged.Conflicts > 0 {
+				conflicts = append(conflicts, change.Path)
+				continue
+			}
+			rebased = append(rebased, worktreeChange{Path: change.Path, Content: []byte(merged.Merged)})
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return nil, fmt.Errorf("the branch has changed since they were stashed, and they conflict with: %s", strings.Join(conflicts, ", "))
+	}
+
+	return rebased, nil
+}
+
+// commitTree returns the tree of a commit.
+func (p *LocalProvider) commitTree(hash plumbing.Hash) (*object.Tree, error) {
+	commit, err := p.repo.CommitObject(hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit %s: %w", hash, err)
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree: %w", err)
+	}
+
+	return tree, nil
+}
@@ -0,0 +1,473 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// createCheckoutRepo creates a repository with two branches:
+// the default branch has shared.md and main.md, and "feature" changes
+// shared.md and adds feature.md. The default branch is checked out.
+func createCheckoutRepo(t *testing.T) (string, string) {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	repo, err := git.PlainInit(tempDir, false)
+	if err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	commitFile(t, repo, tempDir, "shared.md", "shared\n", "Add shared", base)
+	commitFile(t, repo, tempDir, "main.md", "main\n", "Add main", base.Add(time.Hour))
+
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v", err)
+	}
+
+	w, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	if err := w.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature"), Create: true}); err != nil {
+		t.Fatalf("failed to create branch: %v", err)
+	}
+	commitFile(t, repo, tempDir, "shared.md", "shared on feature\n", "Change shared", base.Add(2*time.Hour))
+	commitFile(t, repo, tempDir, "feature.md", "feature\n", "Add feature", base.Add(3*time.Hour))
+
+	if err := w.Checkout(&git.CheckoutOptions{Branch: head.Name()}); err != nil {
+		t.Fatalf("failed to check out: %v", err)
+	}
+
+	return tempDir, head.Name().Short()
+}
+
+// readTestFile returns the content of a working tree file, or "" if it does not exist.
+func readTestFile(t *testing.T, dir, path string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ""
+		}
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return string(content)
+}
+
+func TestCheckout_CleanTree(t *testing.T) {
+	tempDir, _ := createCheckoutRepo(t)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	if err := provider.Checkout("feature", CheckoutClean); err != nil {
+		t.Fatalf("Checkout failed: %v", err)
+	}
+
+	status, err := provider.Status()
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if status.Branch != "feature" || status.IsDirty {
+		t.Errorf("expected clean tree on feature, got %+v", status)
+	}
+
+	if got := readTestFile(t, tempDir, "shared.md"); got != "shared on feature\n" {
+		t.Errorf("expected feature version of shared.md, got %q", got)
+	}
+
+	// The working tree is now served for the new current branch
+	content, err := provider.FileContent("feature.md", "")
+	if err != nil {
+		t.Fatalf("FileContent failed: %v", err)
+	}
+	if string(content) != "feature\n" {
+		t.Errorf("unexpected feature.md content %q", content)
+	}
+}
+
+func TestCheckout_DirtyTreeRefused(t *testing.T) {
+	tempDir, mainBranch := createCheckoutRepo(t)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	if err := provider.WriteFile("main.md", []byte("edited\n")); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	err = provider.Checkout("feature", CheckoutClean)
+	if err == nil || !strings.Contains(err.Error(), "uncommitted changes") {
+		t.Fatalf("expected uncommitted changes error, got %v", err)
+	}
+
+	status, err := provider.Status()
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if status.Branch != mainBranch {
+		t.Errorf("expected to stay on %s, got %s", mainBranch, status.Branch)
+	}
+	if got := readTestFile(t, tempDir, "main.md"); got != "edited\n" {
+		t.Errorf("expected edit to be preserved, got %q", got)
+	}
+}
+
+func TestCheckout_Carry(t *testing.T) {
+	tempDir, _ := createCheckoutRepo(t)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	// main.md is identical on both branches, so the edit can be carried
+	if err := provider.WriteFile("main.md", []byte("edited\n")); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := provider.WriteFile("notes.md", []byte("new\n")); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	if err := provider.Checkout("feature", CheckoutCarry); err != nil {
+		t.Fatalf("Checkout failed: %v", err)
+	}
+
+	if got := readTestFile(t, tempDir, "main.md"); got != "edited\n" {
+		t.Errorf("expected carried edit to main.md, got %q", got)
+	}
+	if got := readTestFile(t, tempDir, "notes.md"); got != "new\n" {
+		t.Errorf("expected carried notes.md, got %q", got)
+	}
+	if got := readTestFile(t, tempDir, "shared.md"); got != "shared on feature\n" {
+		t.Errorf("expected feature version of shared.md, got %q", got)
+	}
+
+	status, err := provider.Status()
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if status.Branch != "feature" || !status.IsDirty {
+		t.Errorf("expected dirty tree on feature, got %+v", status)
+	}
+}
+
+func TestCheckout_CarryConflict(t *testing.T) {
+	tempDir, mainBranch := createCheckoutRepo(t)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	// shared.md differs between the branches, so the edit cannot be carried
+	if err := provider.WriteFile("shared.md", []byte("edited\n")); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	err = provider.Checkout("feature", CheckoutCarry)
+	if err == nil || !strings.Contains(err.Error(), "conflict") || !strings.Contains(err.Error(), "shared.md") {
+		t.Fatalf("expected conflict on shared.md, got %v", err)
+	}
+
+	if provider.currentBranch() != mainBranch {
+		t.Errorf("expected to stay on %s, got %s", mainBranch, provider.currentBranch())
+	}
+	if got := readTestFile(t, tempDir, "shared.md"); got != "edited\n" {
+		t.Errorf("expected edit to be preserved, got %q", got)
+	}
+}
+
+func TestCheckout_CarryFailureRestoresChanges(t *testing.T) {
+	tempDir, mainBranch := createCheckoutRepo(t)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	// feature.md is a file on the feature branch, so a folder of that name
+	// cannot be brought along
+	if err := provider.WriteFile("main.md", []byte("edited\n")); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := provider.WriteFile("feature.md/notes.md", []byte("notes\n")); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	if err := provider.Checkout("feature", CheckoutCarry); err == nil {
+		t.Fatal("expected carry to fail")
+	}
+
+	if provider.currentBranch() != mainBranch {
+		t.Errorf("expected to stay on %s, got %s", mainBranch, provider.currentBranch())
+	}
+	if got := readTestFile(t, tempDir, "main.md"); got != "edited\n" {
+		t.Errorf("expected edit to main.md to be restored, got %q", got)
+	}
+	if got := readTestFile(t, tempDir, "feature.md/notes.md"); got != "notes\n" {
+		t.Errorf("expected feature.md/notes.md to be restored, got %q", got)
+	}
+	if _, err := os.Stat(provider.carryPath()); !os.IsNotExist(err) {
+		t.Errorf("expected carried changes to be cleaned up, got %v", err)
+	}
+}
+
+func TestCheckout_RecoversInterruptedCarry(t *testing.T) {
+	tempDir, _ := createCheckoutRepo(t)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	// Changes saved by a carry that never finished
+	interrupted := stash{Branch: "feature", Changes: []worktreeChange{{Path: "main.md", Content: []byte("edited\n")}}}
+	if err := provider.writeStashFile(provider.carryPath(), interrupted); err != nil {
+		t.Fatalf("failed to save carry: %v", err)
+	}
+
+	// They are put back before anything else, so a clean checkout now refuses
+	err = provider.Checkout("feature", CheckoutClean)
+	if err == nil || !strings.Contains(err.Error(), "uncommitted changes") {
+		t.Fatalf("expected uncommitted changes error, got %v", err)
+	}
+	if got := readTestFile(t, tempDir, "main.md"); got != "edited\n" {
+		t.Errorf("expected recovered edit to main.md, got %q", got)
+	}
+	if _, err := os.Stat(provider.carryPath()); !os.IsNotExist(err) {
+		t.Errorf("expected carried changes to be removed, got %v", err)
+	}
+}
+
+func TestCheckout_StashAndRestore(t *testing.T) {
+	tempDir, mainBranch := createCheckoutRepo(t)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	if err := provider.WriteFile("shared.md", []byte("edited\n")); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := provider.WriteFile("notes.md", []byte("new\n")); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := provider.DeleteFile("main.md"); err != nil {
+		t.Fatalf("DeleteFile failed: %v", err)
+	}
+
+	if err := provider.Checkout("feature", CheckoutStash); err != nil {
+		t.Fatalf("Checkout failed: %v", err)
+	}
+
+	status, err := provider.Status()
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if status.Branch != "feature" || status.IsDirty {
+		t.Errorf("expected clean tree on feature after stashing, got %+v", status)
+	}
+	if got := readTestFile(t, tempDir, "notes.md"); got != "" {
+		t.Errorf("expected notes.md to be stashed away, got %q", got)
+	}
+
+	// Switching back restores the stashed changes
+	if err := provider.Checkout(mainBranch, CheckoutClean); err != nil {
+		t.Fatalf("Checkout back failed: %v", err)
+	}
+
+	if got := readTestFile(t, tempDir, "shared.md"); got != "edited\n" {
+		t.Errorf("expected stashed edit to shared.md, got %q", got)
+	}
+	if got := readTestFile(t, tempDir, "notes.md"); got != "new\n" {
+		t.Errorf("expected stashed notes.md, got %q", got)
+	}
+	if got := readTestFile(t, tempDir, "main.md"); got != "" {
+		t.Errorf("expected main.md to stay deleted, got %q", got)
+	}
+
+	if _, err := os.Stat(provider.stashPath(mainBranch)); !os.IsNotExist(err) {
+		t.Errorf("expected stash to be removed after restoring, got %v", err)
+	}
+}
+
+// stashThenMoveBranch stashes an edit to main.md that appends a line, then
+// commits newContent to main.md on the default branch behind the provider's
+// back, as a pull would, and leaves feature checked out.
+func stashThenMoveBranch(t *testing.T, newContent string) (string, string, *LocalProvider) {
+	t.Helper()
+	tempDir, mainBranch := createCheckoutRepo(t)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	if err := provider.WriteFile("main.md", []byte("main\nmine\n")); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := provider.Checkout("feature", CheckoutStash); err != nil {
+		t.Fatalf("Checkout failed: %v", err)
+	}
+
+	w, err := provider.repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	if err := w.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName(mainBranch)}); err != nil {
+		t.Fatalf("failed to check out: %v", err)
+	}
+	commitFile(t, provider.repo, tempDir, "main.md", newContent, "Change main", time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC))
+	if err := w.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature")}); err != nil {
+		t.Fatalf("failed to check out: %v", err)
+	}
+
+	return tempDir, mainBranch, provider
+}
+
+func TestCheckout_StashRestoreMergesMovedBranch(t *testing.T) {
+	tempDir, mainBranch, provider := stashThenMoveBranch(t, "theirs\nmain\n")
+
+	// The newer commit's change is kept along with the stashed one
+	if err := provider.Checkout(mainBranch, CheckoutClean); err != nil {
+		t.Fatalf("Checkout back failed: %v", err)
+	}
+	if got := readTestFile(t, tempDir, "main.md"); got != "theirs\nmain\nmine\n" {
+		t.Errorf("expected merged main.md, got %q", got)
+	}
+	if _, err := os.Stat(provider.stashPath(mainBranch)); !os.IsNotExist(err) {
+		t.Errorf("expected stash to be removed after restoring, got %v", err)
+	}
+}
+
+func TestCheckout_StashRestoreConflict(t *testing.T) {
+	tempDir, mainBranch, provider := stashThenMoveBranch(t, "main\ntheirs\n")
+
+	// The switch happens, but conflicting changes are not restored over the newer commit
+	err := provider.Checkout(mainBranch, CheckoutClean)
+	if err == nil || !strings.Contains(err.Error(), "main.md") {
+		t.Fatalf("expected stash conflict on main.md, got %v", err)
+	}
+	if provider.currentBranch() != mainBranch {
+		t.Errorf("expected %s to be current, got %s", mainBranch, provider.currentBranch())
+	}
+	if got := readTestFile(t, tempDir, "main.md"); got != "main\ntheirs\n" {
+		t.Errorf("expected committed main.md to be kept, got %q", got)
+	}
+	if _, err := os.Stat(provider.stashPath(mainBranch)); err != nil {
+		t.Errorf("expected stash to be kept, got %v", err)
+	}
+}
+
+func TestCheckout_RemoteOnlyBranchRefused(t *testing.T) {
+	sourceDir, _ := createCheckoutRepo(t)
+
+	cloneDir := t.TempDir()
+	if err := CloneRemote(sourceDir, cloneDir); err != nil {
+		t.Fatalf("clone failed: %v", err)
+	}
+
+	provider, err := NewLocalProvider(cloneDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	if err := provider.WriteFile("main.md", []byte("edited\n")); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	if err := provider.Checkout("origin/feature", CheckoutClean); err == nil {
+		t.Fatal("expected checkout with a dirty tree to be refused")
+	}
+
+	// A refused checkout leaves no tracking branch behind
+	if _, err := provider.repo.Reference(plumbing.NewBranchReferenceName("feature"), true); err == nil {
+		t.Error("expected no local feature branch")
+	}
+	cfg, err := provider.repo.Config()
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+	if _, ok := cfg.Branches["feature"]; ok {
+		t.Error("expected no tracking config for feature")
+	}
+}
+
+func TestCheckout_RemoteOnlyBranch(t *testing.T) {
+	sourceDir, _ := createCheckoutRepo(t)
+
+	cloneDir := t.TempDir()
+	if err := CloneRemote(sourceDir, cloneDir); err != nil {
+		t.Fatalf("clone failed: %v", err)
+	}
+
+	provider, err := NewLocalProvider(cloneDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	if err := provider.Checkout("origin/feature", CheckoutClean); err != nil {
+		t.Fatalf("Checkout failed: %v", err)
+	}
+
+	if provider.currentBranch() != "feature" {
+		t.Errorf("expected local feature branch to be current, got %s", provider.currentBranch())
+	}
+	if got := readTestFile(t, cloneDir, "feature.md"); got != "feature\n" {
+		t.Errorf("expected feature.md from origin/feature, got %q", got)
+	}
+
+	cfg, err := provider.repo.Config()
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+	tracking, ok := cfg.Branches["feature"]
+	if !ok || tracking.Remote != "origin" || tracking.Merge != plumbing.NewBranchReferenceName("feature") {
+		t.Errorf("expected feature to track origin/feature, got %+v", tracking)
+	}
+}
+
+func TestCheckout_Errors(t *testing.T) {
+	tempDir, _ := createCheckoutRepo(t)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		branch      string
+		mode        string
+		errContains string
+	}{
+		{"unknown branch", "nonexistent", CheckoutClean, "not found"},
+		{"invalid mode", "feature", "merge", "invalid checkout mode"},
+		{"empty branch", "", CheckoutClean, "cannot be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := provider.Checkout(tt.branch, tt.mode)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("expected error containing %q, got %v", tt.errContains, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,376 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Fallback identity used when no author is configured anywhere.
+const (
+	fallbackAuthorName  = "Giki User"
+	fallbackAuthorEmail = "user@giki.local"
+)
+
+// ErrPathNotFound is returned when a path to commit is neither in the working
+// tree nor in the index.
+var ErrPathNotFound = errors.New("path not found")
+
+// CommitError reports a commit that cannot be made as requested, such as one
+// with an invalid path or author, or with nothing to commit.
+type CommitError struct {
+	Message string
+	Err     error // underlying cause, if any
+}
+
+func (e *CommitError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *CommitError) Unwrap() error {
+	return e.Err
+}
+
+// Identity is a commit author or co-author.
+type Identity struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// String formats the identity as "Name <email>".
+func (i Identity) String() string {
+	return fmt.Sprintf("%s <%s>", i.Name, i.Email)
+}
+
+// CommitOptions configures a commit created by CommitWithOptions.
+type CommitOptions struct {
+	Message   string     // commit message; may be empty when amending to keep the previous message
+	Author    *Identity  // overrides the default author; empty fields fall back individually
+	CoAuthors []Identity // added as Co-authored-by trailers
+	Paths     []string   // files or folders to commit; empty commits all changes
+	Amend     bool       // replace the HEAD commit instead of creating a new one
+}
+
+// SetDefaultAuthor sets the author identity used for commits, typically from
+// the [commit] section of the giki config. Empty fields fall back to the
+// repository and user gitconfig.
+func (p *LocalProvider) SetDefaultAuthor(name, email string) {
+	p.author = Identity{Name: strings.TrimSpace(name), Email: strings.TrimSpace(email)}
+}
+
+// CommitWithOptions creates a git commit.
+// If opts.Paths is empty, all staged and unstaged changes are committed;
+// otherwise only changes (including deletions) to those files and folders are,
+// and everything else is left uncommitted in the working tree.
+// The author is resolved field by field from opts.Author, the default author
+// set with SetDefaultAuthor, the repository and user gitconfig, and finally
+// a generic "Giki User" identity. When amending, the original author is kept
+// unless opts.Author is given. The commit is signed if a signing key is set.
+// Returns the commit hash on success.
+func (p *LocalProvider) CommitWithOptions(opts CommitOptions) (string, error) {
+	message := strings.TrimSpace(opts.Message)
+
+	var amended *object.Commit
+	if opts.Amend {
+		head, err := p.repo.Head()
+		if err != nil {
+			return "", &CommitError{Message: "nothing to amend", Err: err}
+		}
+		amended, err = p.repo.CommitObject(head.Hash())
+		if err != nil {
+			return "", fmt.Errorf("failed to get commit: %w", err)
+		}
+
+		// Keep the previous message when none is given
+		if message == "" {
+			message = strings.TrimSpace(amended.Message)
+		}
+	}
+
+	// Validate message is not empty
+	if message == "" {
+		return "", &CommitError{Message: "commit message cannot be empty"}
+	}
+
+	committer, err := p.resolveAuthor(opts.Author)
+	if err != nil {
+		return "", err
+	}
+
+	message, err = addCoAuthorTrailers(message, opts.CoAuthors)
+	if err != nil {
+		return "", err
+	}
+
+	// Get worktree
+	worktree, err := p.repo.Worktree()
+	if err != nil {
+		return "", fmt.Errorf("failed to get worktree: %w", err)
+	}
+
+	if len(opts.Paths) > 0 {
+		if err := p.stagePaths(worktree, opts.Paths); err != nil {
+			return "", err
+		}
+	} else {
+		// Stage all changes (git add .)
+		err = worktree.AddWithOptions(&git.AddOptions{All: true})
+		if err != nil {
+			return "", fmt.Errorf("failed to stage changes: %w", err)
+		}
+	}
+
+	now := time.Now()
+	commitOpts := &git.CommitOptions{
+		Author: &object.Signature{
+			Name:  committer.Name,
+			Email: committer.Email,
+			When:  now,
+		},
+		Committer: &object.Signature{
+			Name:  committer.Name,
+			Email: committer.Email,
+			When:  now,
+		},
+	}
+
+	if p.signer != nil {
+		commitOpts.Signer = p.signer
+	}
+
+	if amended != nil {
+		commitOpts.Amend = true
+		// Rewording a commit without changing its files is allowed
+		commitOpts.AllowEmptyCommits = true
+		if opts.Author == nil {
+			author := amended.Author
+			commitOpts.Author = &author
+		}
+	}
+
+	// Create commit
+	hash, err := worktree.Commit(message, commitOpts)
+	if err != nil {
+		if err == git.ErrEmptyCommit {
+			return "", &CommitError{Message: "nothing to commit"}
+		}
+		return "", fmt.Errorf("failed to create commit: %w", err)
+	}
+
+	return hash.String(), nil
+}
+
+// stagePaths resets the index to HEAD and stages only the given files and folders,
+// including deletions. Changes to other paths stay in the working tree, unstaged.
+func (p *LocalProvider) stagePaths(worktree *git.Worktree, paths []string) error {
+	normalized := make([]string, 0, len(paths))
+	for _, path := range paths {
+		// Normalize path: strip leading/trailing slashes, convert to forward slashes
+		path = strings.Trim(filepath.ToSlash(path), "/")
+		if path == "" {
+			return &CommitError{Message: "invalid path: path cannot be empty"}
+		}
+
+		// Security: validate path doesn't escape repository root
+		if strings.Contains(path, "..") {
+			return &CommitError{Message: "invalid path: cannot contain '..'"}
+		}
+
+		normalized = append(normalized, path)
+	}
+
+	// Start from HEAD so nothing staged earlier sneaks into the commit
+	if err := p.resetIndex(); err != nil {
+		return err
+	}
+
+	for _, path := range normalized {
+		fullPath := filepath.Join(p.path, filepath.FromSlash(path))
+		if _, err := os.Lstat(fullPath); err == nil {
+			if _, err := worktree.Add(path); err != nil {
+				return fmt.Errorf("failed to stage %s: %w", path, err)
+			}
+			continue
+		} else if !os.IsNotExist(err) {
+			return fmt.Errorf("failed to stat %s: %w", path, err)
+		}
+
+		// Gone from disk: stage the deletion of the file, or of every file in the folder
+		deleted, err := p.indexPaths(path)
+		if err != nil {
+			return err
+		}
+		if len(deleted) == 0 {
+			return fmt.Errorf("%w: %s", ErrPathNotFound, path)
+		}
+		for _, entry := range deleted {
+			if _, err := worktree.Add(entry); err != nil {
+				return fmt.Errorf("failed to stage deletion of %s: %w", entry, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// resetIndex unstages every change, leaving the index matching HEAD, or empty
+// before the first commit. The working tree is not touched.
+func (p *LocalProvider) resetIndex() error {
+	head, err := p.repo.Head()
+	switch {
+	case err == plumbing.ErrReferenceNotFound:
+		if err := p.repo.Storer.SetIndex(&index.Index{Version: 2}); err != nil {
+			return fmt.Errorf("failed to reset index: %w", err)
+		}
+	case err != nil:
+		return fmt.Errorf("failed to resolve HEAD: %w", err)
+	default:
+		worktree, err := p.repo.Worktree()
+		if err != nil {
+			return fmt.Errorf("failed to get worktree: %w", err)
+		}
+		if err := worktree.Reset(&git.ResetOptions{Mode: git.MixedReset, Commit: head.Hash()}); err != nil {
+			return fmt.Errorf("failed to reset index: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// indexPaths returns the index entries for a file, or for every file beneath a folder.
+func (p *LocalProvider) indexPaths(path string) ([]string, error) {
+	idx, err := p.repo.Storer.Index()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+
+	var paths []string
+	for _, entry := range idx.Entries {
+		if entry.Name == path || strings.HasPrefix(entry.Name, path+"/") {
+			paths = append(paths, entry.Name)
+		}
+	}
+
+	return paths, nil
+}
+
+// resolveAuthor determines the commit author, taking each of name and email
+// from the first source that provides it.
+func (p *LocalProvider) resolveAuthor(override *Identity) (Identity, error) {
+	var author Identity
+	if override != nil {
+		author = Identity{Name: strings.TrimSpace(override.Name), Email: strings.TrimSpace(override.Email)}
+	}
+
+	if author.Name == "" {
+		author.Name = p.author.Name
+	}
+	if author.Email == "" {
+		author.Email = p.author.Email
+	}
+
+	if author.Name == "" || author.Email == "" {
+		// Repository config, with user (global) config filling the gaps
+		cfg, err := p.repo.ConfigScoped(config.GlobalScope)
+		if err != nil {
+			return Identity{}, fmt.Errorf("failed to read git config: %w", err)
+		}
+		if author.Name == "" {
+			author.Name = strings.TrimSpace(cfg.User.Name)
+		}
+		if author.Email == "" {
+			author.Email = strings.TrimSpace(cfg.User.Email)
+		}
+	}
+
+	if author.Name == "" {
+		author.Name = fallbackAuthorName
+	}
+	if author.Email == "" {
+		author.Email = fallbackAuthorEmail
+	}
+
+	if err := validateIdentity(author); err != nil {
+		return Identity{}, &CommitError{Message: "invalid author", Err: err}
+	}
+
+	return author, nil
+}
+
+// addCoAuthorTrailers appends a Co-authored-by trailer for each co-author,
+// separated from the message body by a blank line. Duplicates are skipped.
+func addCoAuthorTrailers(message string, coAuthors []Identity) (string, error) {
+	if len(coAuthors) == 0 {
+		return message, nil
+	}
+
+	seen := make(map[string]bool)
+	var trailers []string
+	for _, coAuthor := range coAuthors {
+		coAuthor = Identity{Name: strings.TrimSpace(coAuthor.Name), Email: strings.TrimSpace(coAuthor.Email)}
+		if coAuthor.Name == "" || coAuthor.Email == "" {
+			return "", &CommitError{Message: "invalid co-author: name and email are required"}
+		}
+		if err := validateIdentity(coAuthor); err != nil {
+			return "", &CommitError{Message: "invalid co-author", Err: err}
+		}
+
+		key := strings.ToLower(coAuthor.Email)
+		trailer := "Co-authored-by: " + coAuthor.String()
+		if seen[key] || hasLine(message, trailer) {
+			continue
+		}
+		seen[key] = true
+		trailers = append(trailers, trailer)
+	}
+
+	if len(trailers) == 0 {
+		return message, nil
+	}
+
+	// Extend an existing trailer block rather than starting a new paragraph
+	if lastLineHasPrefix(message, "Co-authored-by: ") {
+		return message + "\n" + strings.Join(trailers, "\n"), nil
+	}
+
+	return message + "\n\n" + strings.Join(trailers, "\n"), nil
+}
+
+// hasLine reports whether text contains line as a whole line.
+func hasLine(text, line string) bool {
+	for _, l := range strings.Split(text, "\n") {
+		if strings.TrimSpace(l) == line {
+			return true
+		}
+	}
+	return false
+}
+
+// lastLineHasPrefix reports whether the last line of text starts with prefix.
+func lastLineHasPrefix(text, prefix string) bool {
+	lines := strings.Split(text, "\n")
+	return strings.HasPrefix(lines[len(lines)-1], prefix)
+}
+
+// validateIdentity rejects names and emails that would corrupt the commit header.
+func validateIdentity(identity Identity) error {
+	if strings.ContainsAny(identity.Name, "<>\n") {
+		return fmt.Errorf("name cannot contain '<', '>' or newlines")
+	}
+	if strings.ContainsAny(identity.Email, "<>\n ") || !strings.Contains(identity.Email, "@") {
+		return fmt.Errorf("email %q is not valid", identity.Email)
+	}
+	return nil
+}
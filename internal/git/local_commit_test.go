@@ -0,0 +1,419 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// isolateGitConfig points the user gitconfig at an empty home directory,
+// optionally writing a global config with the given content.
+func isolateGitConfig(t *testing.T, globalConfig string) {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+
+	if globalConfig != "" {
+		if err := os.WriteFile(filepath.Join(home, ".gitconfig"), []byte(globalConfig), 0644); err != nil {
+			t.Fatalf("failed to write global gitconfig: %v", err)
+		}
+	}
+}
+
+// commitAndLoad writes a file, commits it with the given options, and returns
+// the author name, author email, and message of the new commit.
+func commitAndLoad(t *testing.T, provider *LocalProvider, opts CommitOptions) (string, string, string) {
+	t.Helper()
+
+	if err := provider.WriteFile("page.md", []byte(opts.Message)); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	hash, err := provider.CommitWithOptions(opts)
+	if err != nil {
+		t.Fatalf("CommitWithOptions failed: %v", err)
+	}
+
+	commit, err := provider.repo.CommitObject(plumbing.NewHash(hash))
+	if err != nil {
+		t.Fatalf("failed to load commit: %v", err)
+	}
+
+	return commit.Author.Name, commit.Author.Email, commit.Message
+}
+
+func TestCommitWithOptions_AuthorResolution(t *testing.T) {
+	isolateGitConfig(t, "[user]\n\tname = Global User\n\temail = global@example.com\n")
+
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	// User gitconfig
+	name, email, _ := commitAndLoad(t, provider, CommitOptions{Message: "global"})
+	if name != "Global User" || email != "global@example.com" {
+		t.Errorf("expected global identity, got %s <%s>", name, email)
+	}
+
+	// Repository gitconfig overrides the user gitconfig, field by field
+	cfg, err := provider.repo.Config()
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+	cfg.User.Name = "Repo User"
+	if err := provider.repo.SetConfig(cfg); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	name, email, _ = commitAndLoad(t, provider, CommitOptions{Message: "repo"})
+	if name != "Repo User" || email != "global@example.com" {
+		t.Errorf("expected repo name with global email, got %s <%s>", name, email)
+	}
+
+	// Giki config overrides gitconfig
+	provider.SetDefaultAuthor("Config User", "config@example.com")
+
+	name, email, _ = commitAndLoad(t, provider, CommitOptions{Message: "config"})
+	if name != "Config User" || email != "config@example.com" {
+		t.Errorf("expected config identity, got %s <%s>", name, email)
+	}
+
+	// Per-commit override wins
+	name, email, _ = commitAndLoad(t, provider, CommitOptions{
+		Message: "override",
+		Author:  &Identity{Name: "Request User", Email: "request@example.com"},
+	})
+	if name != "Request User" || email != "request@example.com" {
+		t.Errorf("expected request identity, got %s <%s>", name, email)
+	}
+}
+
+func TestCommitWithOptions_FallbackAuthor(t *testing.T) {
+	isolateGitConfig(t, "")
+
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	name, email, _ := commitAndLoad(t, provider, CommitOptions{Message: "fallback"})
+	if name != fallbackAuthorName || email != fallbackAuthorEmail {
+		t.Errorf("expected fallback identity, got %s <%s>", name, email)
+	}
+}
+
+func TestCommitWithOptions_CoAuthors(t *testing.T) {
+	isolateGitConfig(t, "")
+
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	_, _, message := commitAndLoad(t, provider, CommitOptions{
+		Message: "Pair on docs",
+		CoAuthors: []Identity{
+			{Name: "Grace Hopper", Email: "grace@example.com"},
+			{Name: "Alan Turing", Email: "alan@example.com"},
+			{Name: "Grace H.", Email: "GRACE@example.com"},
+		},
+	})
+
+	expected := "Pair on docs\n\n" +
+		"Co-authored-by: Grace Hopper <grace@example.com>\n" +
+		"Co-authored-by: Alan Turing <alan@example.com>"
+	if message != expected {
+		t.Errorf("unexpected message:\n%s\nexpected:\n%s", message, expected)
+	}
+}
+
+func TestCommitWithOptions_Errors(t *testing.T) {
+	isolateGitConfig(t, "")
+
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	if err := provider.WriteFile("page.md", []byte("content")); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		opts        CommitOptions
+		errContains string
+	}{
+		{"empty message", CommitOptions{Message: "  "}, "cannot be empty"},
+		{"author name with brackets", CommitOptions{Message: "m", Author: &Identity{Name: "Evil <x>"}}, "invalid author"},
+		{"author email without at", CommitOptions{Message: "m", Author: &Identity{Email: "nobody"}}, "invalid author"},
+		{"co-author missing email", CommitOptions{Message: "m", CoAuthors: []Identity{{Name: "Grace"}}}, "invalid co-author"},
+		{"co-author newline", CommitOptions{Message: "m", CoAuthors: []Identity{{Name: "A\nB", Email: "a@example.com"}}}, "invalid co-author"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := provider.CommitWithOptions(tt.opts)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("expected error containing %q, got %v", tt.errContains, err)
+			}
+			var commitErr *CommitError
+			if !errors.As(err, &commitErr) {
+				t.Errorf("expected *CommitError, got %T", err)
+			}
+		})
+	}
+}
+
+// committedFiles returns the paths in the tree of the HEAD commit.
+func committedFiles(t *testing.T, provider *LocalProvider) map[string]bool {
+	t.Helper()
+
+	tree, err := provider.headTree()
+	if err != nil {
+		t.Fatalf("failed to get HEAD tree: %v", err)
+	}
+
+	files := make(map[string]bool)
+	iter := tree.Files()
+	defer iter.Close()
+	for {
+		f, err := iter.Next()
+		if err != nil {
+			break
+		}
+		files[f.Name] = true
+	}
+	return files
+}
+
+func TestCommitWithOptions_Paths(t *testing.T) {
+	isolateGitConfig(t, "")
+
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	for _, path := range []string{"docs/a.md", "docs/b.md", "old.md"} {
+		if err := provider.WriteFile(path, []byte(path)); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+	if _, err := provider.Commit("Add files"); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	// A page to commit, a deleted file and folder, and scratch files to leave alone
+	if err := provider.WriteFile("page.md", []byte("page")); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := provider.WriteFile("scratch.md", []byte("scratch")); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := provider.DeleteFile("old.md"); err != nil {
+		t.Fatalf("DeleteFile failed: %v", err)
+	}
+	if err := os.RemoveAll(filepath.Join(tempDir, "docs")); err != nil {
+		t.Fatalf("failed to remove docs: %v", err)
+	}
+
+	// Stage the scratch file behind giki's back; it must still be left out
+	worktree, err := provider.repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	if _, err := worktree.Add("scratch.md"); err != nil {
+		t.Fatalf("failed to stage scratch.md: %v", err)
+	}
+
+	if _, err := provider.CommitWithOptions(CommitOptions{
+		Message: "Commit page",
+		Paths:   []string{"/page.md", "old.md", "docs/"},
+	}); err != nil {
+		t.Fatalf("CommitWithOptions failed: %v", err)
+	}
+
+	files := committedFiles(t, provider)
+	if !files["page.md"] {
+		t.Error("expected page.md to be committed")
+	}
+	for _, path := range []string{"scratch.md", "old.md", "docs/a.md", "docs/b.md"} {
+		if files[path] {
+			t.Errorf("expected %s not to be in the commit", path)
+		}
+	}
+
+	// The scratch file is still there, uncommitted
+	status, err := worktree.Status()
+	if err != nil {
+		t.Fatalf("failed to get status: %v", err)
+	}
+	if status.IsClean() || len(status) != 1 {
+		t.Errorf("expected only scratch.md to remain changed, got %v", status)
+	}
+	if _, ok := status["scratch.md"]; !ok {
+		t.Errorf("expected scratch.md to remain changed, got %v", status)
+	}
+}
+
+func TestCommitWithOptions_PathErrors(t *testing.T) {
+	isolateGitConfig(t, "")
+
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	if err := provider.WriteFile("page.md", []byte("page")); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		paths       []string
+		errContains string
+	}{
+		{"unknown path", []string{"missing.md"}, "path not found"},
+		{"path traversal", []string{"../outside.md"}, "invalid path"},
+		{"empty path", []string{"/"}, "invalid path"},
+		{"unchanged path", []string{".gitkeep"}, "nothing to commit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := provider.CommitWithOptions(CommitOptions{Message: "m", Paths: tt.paths})
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("expected error containing %q, got %v", tt.errContains, err)
+			}
+
+			// Unknown paths are not found; everything else is an invalid commit
+			var commitErr *CommitError
+			if notFound := errors.Is(err, ErrPathNotFound); notFound != (tt.name == "unknown path") || notFound == errors.As(err, &commitErr) {
+				t.Errorf("unexpected error type %T: %v", err, err)
+			}
+		})
+	}
+}
+
+func TestCommitWithOptions_Amend(t *testing.T) {
+	isolateGitConfig(t, "")
+
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	if err := provider.WriteFile("page.md", []byte("first")); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	original, err := provider.CommitWithOptions(CommitOptions{
+		Message: "Add page",
+		Author:  &Identity{Name: "Original Author", Email: "original@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("CommitWithOptions failed: %v", err)
+	}
+	originalCommit, err := provider.repo.CommitObject(plumbing.NewHash(original))
+	if err != nil {
+		t.Fatalf("failed to load commit: %v", err)
+	}
+
+	// Amend with new content, keeping the message
+	provider.SetDefaultAuthor("Amending User", "amender@example.com")
+	if err := provider.WriteFile("page.md", []byte("second")); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	amended, err := provider.CommitWithOptions(CommitOptions{Amend: true})
+	if err != nil {
+		t.Fatalf("amend failed: %v", err)
+	}
+
+	commit, err := provider.repo.CommitObject(plumbing.NewHash(amended))
+	if err != nil {
+		t.Fatalf("failed to load commit: %v", err)
+	}
+
+	if commit.Message != "Add page" {
+		t.Errorf("expected message to be kept, got %q", commit.Message)
+	}
+	if commit.Author.Name != "Original Author" {
+		t.Errorf("expected original author to be kept, got %s", commit.Author.Name)
+	}
+	if commit.Committer.Name != "Amending User" {
+		t.Errorf("expected amending user as committer, got %s", commit.Committer.Name)
+	}
+	if len(commit.ParentHashes) != 1 || commit.ParentHashes[0] != originalCommit.ParentHashes[0] {
+		t.Errorf("expected amended commit to replace the original, parents %v", commit.ParentHashes)
+	}
+
+	content, err := provider.readFileFromCommit(amended, "page.md")
+	if err != nil {
+		t.Fatalf("failed to read page.md: %v", err)
+	}
+	if string(content) != "second" {
+		t.Errorf("expected amended content, got %q", content)
+	}
+
+	// Rewording without changes is allowed
+	reworded, err := provider.CommitWithOptions(CommitOptions{Message: "Add the page", Amend: true})
+	if err != nil {
+		t.Fatalf("reword failed: %v", err)
+	}
+	commit, err = provider.repo.CommitObject(plumbing.NewHash(reworded))
+	if err != nil {
+		t.Fatalf("failed to load commit: %v", err)
+	}
+	if commit.Message != "Add the page" {
+		t.Errorf("expected reworded message, got %q", commit.Message)
+	}
+}
+
+func TestAddCoAuthorTrailers_ExistingTrailers(t *testing.T) {
+	message := "Add page\n\nCo-authored-by: Grace Hopper <grace@example.com>"
+
+	got, err := addCoAuthorTrailers(message, []Identity{
+		{Name: "Grace Hopper", Email: "grace@example.com"},
+		{Name: "Alan Turing", Email: "alan@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("addCoAuthorTrailers failed: %v", err)
+	}
+
+	expected := message + "\nCo-authored-by: Alan Turing <alan@example.com>"
+	if got != expected {
+		t.Errorf("unexpected message:\n%s\nexpected:\n%s", got, expected)
+	}
+}
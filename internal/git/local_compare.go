@@ -0,0 +1,106 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Compare returns the changes introduced on head since it diverged from base,
+// like a pull request preview: the diff is taken from the merge base of the two
+// revisions to head, so unrelated changes made on base are not shown.
+// If the revisions share no history, head is compared directly against base.
+func (p *LocalProvider) Compare(base, head string) (*Comparison, error) {
+	if base == "" || head == "" {
+		return nil, fmt.Errorf("base and head are required")
+	}
+
+	baseCommit, err := p.resolveCommit(base)
+	if err != nil {
+		return nil, err
+	}
+
+	headCommit, err := p.resolveCommit(head)
+	if err != nil {
+		return nil, err
+	}
+
+	// Diff from the common ancestor, falling back to base for unrelated histories
+	fromCommit := baseCommit
+	mergeBases, err := baseCommit.MergeBase(headCommit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find merge base: %w", err)
+	}
+	if len(mergeBases) > 0 {
+		fromCommit = mergeBases[0]
+	}
+
+	fromTree, err := fromCommit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree: %w", err)
+	}
+	toTree, err := headCommit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree: %w", err)
+	}
+
+	changes, err := object.DiffTreeWithOptions(context.Background(), fromTree, toTree, object.DefaultDiffTreeOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff trees: %w", err)
+	}
+
+	comparison := &Comparison{
+		Base:      baseCommit.Hash.String(),
+		Head:      headCommit.Hash.String(),
+		MergeBase: fromCommit.Hash.String(),
+		Files:     make([]FileDiff, 0, len(changes)),
+	}
+
+	for _, change := range changes {
+		from, to, err := change.Files()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read changed files: %w", err)
+		}
+
+		oldContent, err := fileBytes(from)
+		if err != nil {
+			return nil, err
+		}
+		newContent, err := fileBytes(to)
+		if err != nil {
+			return nil, err
+		}
+
+		fd := buildFileDiff(change.From.Name, change.To.Name, oldContent, newContent)
+		comparison.Additions += fd.Additions
+		comparison.Deletions += fd.Deletions
+		comparison.Files = append(comparison.Files, fd)
+	}
+
+	sort.Slice(comparison.Files, func(i, j int) bool {
+		return comparison.Files[i].Path < comparison.Files[j].Path
+	})
+
+	return comparison, nil
+}
+
+// fileBytes returns the content of a file object, or nil if the file is nil.
+func fileBytes(file *object.File) ([]byte, error) {
+	if file == nil {
+		return nil, nil
+	}
+
+	content, err := file.Contents()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", file.Name, err)
+	}
+
+	// Empty files must still be distinguishable from missing ones
+	if content == "" {
+		return []byte{}, nil
+	}
+
+	return []byte(content), nil
+}
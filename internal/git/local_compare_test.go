@@ -0,0 +1,166 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// createDivergedRepo creates a repository where "develop" branches off the
+// default branch and both sides gain commits afterwards.
+// Returns the repo dir, the default branch name, and the fork-point commit hash.
+func createDivergedRepo(t *testing.T) (string, string, string) {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	repo, err := git.PlainInit(tempDir, false)
+	if err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	commitFile(t, repo, tempDir, "README.md", "# Project\n", "Add readme", base)
+	forkPoint := commitFile(t, repo, tempDir, "old-name.md", "stable content\n", "Add page", base.Add(time.Minute))
+
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v", err)
+	}
+	mainBranch := head.Name().Short()
+
+	w, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+
+	// develop: edit README, add a doc, rename a page
+	if err := w.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("develop"), Create: true}); err != nil {
+		t.Fatalf("failed to create develop: %v", err)
+	}
+	commitFile(t, repo, tempDir, "README.md", "# Project\n\nDevelop notes.\n", "Document develop", base.Add(time.Hour))
+	commitFile(t, repo, tempDir, "docs/new.md", "new page\n", "Add new page", base.Add(2*time.Hour))
+	if err := os.Rename(filepath.Join(tempDir, "old-name.md"), filepath.Join(tempDir, "new-name.md")); err != nil {
+		t.Fatalf("failed to rename: %v", err)
+	}
+	if _, err := w.Remove("old-name.md"); err != nil {
+		t.Fatalf("failed to stage removal: %v", err)
+	}
+	commitFile(t, repo, tempDir, "new-name.md", "stable content\n", "Rename page", base.Add(3*time.Hour))
+
+	// main: an unrelated change after the fork point
+	if err := w.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName(mainBranch)}); err != nil {
+		t.Fatalf("failed to check out %s: %v", mainBranch, err)
+	}
+	commitFile(t, repo, tempDir, "CHANGELOG.md", "- main only\n", "Main only change", base.Add(4*time.Hour))
+
+	return tempDir, mainBranch, forkPoint
+}
+
+func TestCompare_Branches(t *testing.T) {
+	tempDir, mainBranch, forkPoint := createDivergedRepo(t)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	comparison, err := provider.Compare(mainBranch, "develop")
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+
+	if comparison.MergeBase != forkPoint {
+		t.Errorf("expected merge base %s, got %s", forkPoint, comparison.MergeBase)
+	}
+
+	byPath := make(map[string]FileDiff)
+	for _, f := range comparison.Files {
+		byPath[f.Path] = f
+	}
+
+	if len(comparison.Files) != 3 {
+		t.Fatalf("expected 3 changed files, got %d: %+v", len(comparison.Files), comparison.Files)
+	}
+	if _, ok := byPath["CHANGELOG.md"]; ok {
+		t.Error("changes made only on base should not be included")
+	}
+	if f := byPath["README.md"]; f.Status != FileModified || f.Additions != 2 || f.Deletions != 0 {
+		t.Errorf("unexpected README.md diff: %+v", f)
+	}
+	if f := byPath["docs/new.md"]; f.Status != FileAdded {
+		t.Errorf("expected docs/new.md to be added, got %+v", f)
+	}
+	if f := byPath["new-name.md"]; f.Status != FileRenamed || f.OldPath != "old-name.md" {
+		t.Errorf("expected old-name.md renamed to new-name.md, got %+v", f)
+	}
+
+	if comparison.Additions != 3 || comparison.Deletions != 0 {
+		t.Errorf("expected totals +3 -0, got +%d -%d", comparison.Additions, comparison.Deletions)
+	}
+	if !strings.Contains(byPath["README.md"].Patch, "+Develop notes.") {
+		t.Errorf("expected README patch to contain added line:\n%s", byPath["README.md"].Patch)
+	}
+}
+
+func TestCompare_TagsAndHashes(t *testing.T) {
+	tempDir, _, forkPoint := createDivergedRepo(t)
+
+	repo, err := git.PlainOpen(tempDir)
+	if err != nil {
+		t.Fatalf("failed to open repo: %v", err)
+	}
+	if _, err := repo.CreateTag("v1.0", plumbing.NewHash(forkPoint), nil); err != nil {
+		t.Fatalf("failed to create tag: %v", err)
+	}
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	byTag, err := provider.Compare("v1.0", "develop")
+	if err != nil {
+		t.Fatalf("Compare by tag failed: %v", err)
+	}
+	byHash, err := provider.Compare(forkPoint[:10], "develop")
+	if err != nil {
+		t.Fatalf("Compare by abbreviated hash failed: %v", err)
+	}
+
+	if byTag.Base != forkPoint || byHash.Base != forkPoint {
+		t.Errorf("expected base %s, got %s (tag) and %s (hash)", forkPoint, byTag.Base, byHash.Base)
+	}
+	if len(byTag.Files) != len(byHash.Files) {
+		t.Errorf("expected same files for tag and hash, got %d and %d", len(byTag.Files), len(byHash.Files))
+	}
+}
+
+func TestCompare_Errors(t *testing.T) {
+	tempDir, mainBranch, _ := createDivergedRepo(t)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	if _, err := provider.Compare(mainBranch, "nonexistent"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+	if _, err := provider.Compare("", "develop"); err == nil {
+		t.Error("expected error for empty base")
+	}
+
+	// Comparing a revision with itself yields no changes
+	comparison, err := provider.Compare("develop", "develop")
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if len(comparison.Files) != 0 {
+		t.Errorf("expected no changes, got %d", len(comparison.Files))
+	}
+}
@@ -0,0 +1,95 @@
+package git
+
+import (
+	"fmt"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// ConflictError reports that a file changed since the version an edit was based on.
+type ConflictError struct {
+	Path        string
+	BaseHash    string // hash the edit was based on
+	CurrentHash string // hash of the current content; empty if the file is gone
+	Content     []byte // current content; nil if the file is gone
+}
+
+func (e *ConflictError) Error() string {
+	if e.Content == nil {
+		return fmt.Sprintf("conflict: %s was deleted since it was loaded", e.Path)
+	}
+	return fmt.Sprintf("conflict: %s has changed since it was loaded", e.Path)
+}
+
+// BlobHash returns the git blob hash of content, which identifies a version of a file.
+// It matches the hash git assigns the file when it is committed.
+func BlobHash(content []byte) string {
+	return plumbing.ComputeHash(plumbing.BlobObject, content).String()
+}
+
+// checkBaseHash returns a *ConflictError unless the working tree file at path
+// still has the content identified by baseHash. An empty baseHash always matches.
+func (p *LocalProvider) checkBaseHash(path, baseHash string) error {
+	if baseHash == "" {
+		return nil
+	}
+
+	// The conflict carries the current content, so never read outside the repository
+	path, err := normalizeChangePath(path)
+	if err != nil {
+		return err
+	}
+
+	content, err := p.readWorkingFile(path)
+	if err != nil {
+		return err
+	}
+
+	if content == nil {
+		return &ConflictError{Path: path, BaseHash: baseHash}
+	}
+
+	if current := BlobHash(content); current != baseHash {
+		return &ConflictError{Path: path, BaseHash: baseHash, CurrentHash: current, Content: content}
+	}
+
+	return nil
+}
+
+// WriteFileIfUnchanged writes content to a file like WriteFile, unless the file
+// no longer has the content identified by baseHash, in which case it returns a
+// *ConflictError. The check and the write happen under the provider's lock, so
+// two edits based on the same version cannot both succeed.
+func (p *LocalProvider) WriteFileIfUnchanged(path string, content []byte, baseHash string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.checkBaseHash(path, baseHash); err != nil {
+		return err
+	}
+	return p.WriteFile(path, content)
+}
+
+// DeleteFileIfUnchanged removes a file like DeleteFile, unless it no longer has
+// the content identified by baseHash; see WriteFileIfUnchanged.
+func (p *LocalProvider) DeleteFileIfUnchanged(path, baseHash string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.checkBaseHash(path, baseHash); err != nil {
+		return err
+	}
+	return p.DeleteFile(path)
+}
+
+// MoveFileIfUnchanged moves a file like MoveFile, unless the file at oldPath no
+// longer has the content identified by baseHash; see WriteFileIfUnchanged.
+func (p *LocalProvider) MoveFileIfUnchanged(oldPath, newPath, baseHash string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.checkBaseHash(oldPath, baseHash); err != nil {
+		return err
+	}
+	return p.MoveFile(oldPath, newPath)
+}
@@ -0,0 +1,151 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestBlobHash(t *testing.T) {
+	_, provider := createChangeSetProvider(t)
+
+	// The hash matches the blob git stored for the committed file
+	tree, err := provider.headTree()
+	if err != nil {
+		t.Fatalf("failed to get HEAD tree: %v", err)
+	}
+	entry, err := tree.FindEntry("old.md")
+	if err != nil {
+		t.Fatalf("failed to find old.md: %v", err)
+	}
+
+	if got := BlobHash([]byte("old.md")); got != entry.Hash.String() {
+		t.Errorf("expected %s, got %s", entry.Hash, got)
+	}
+}
+
+func TestApplyChangeSet_BaseHash(t *testing.T) {
+	tempDir, provider := createChangeSetProvider(t)
+
+	loaded := BlobHash([]byte("old.md"))
+
+	// Editing the version that is on disk succeeds
+	if _, err := provider.ApplyChangeSet([]ChangeOp{{Op: OpWrite, Path: "old.md", Content: "mine", BaseHash: loaded}}, ""); err != nil {
+		t.Fatalf("expected write with current base hash to succeed, got %v", err)
+	}
+
+	// A second edit based on the same, now outdated, version conflicts
+	_, err := provider.ApplyChangeSet([]ChangeOp{{Op: OpWrite, Path: "old.md", Content: "theirs", BaseHash: loaded}}, "")
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected ConflictError, got %v", err)
+	}
+	if conflict.Path != "old.md" || string(conflict.Content) != "mine" || conflict.CurrentHash != BlobHash([]byte("mine")) {
+		t.Errorf("unexpected conflict details: %+v", conflict)
+	}
+	if got := readTestFile(t, tempDir, "old.md"); got != "mine" {
+		t.Errorf("expected conflicting write not to be applied, got %q", got)
+	}
+
+	// Moves and deletes are checked against the source file
+	for _, op := range []ChangeOp{
+		{Op: OpMove, OldPath: "old.md", NewPath: "new.md", BaseHash: loaded},
+		{Op: OpDelete, Path: "old.md", BaseHash: loaded},
+	} {
+		if _, err := provider.ApplyChangeSet([]ChangeOp{op}, ""); !errors.As(err, &conflict) {
+			t.Errorf("expected %s to conflict, got %v", op.Op, err)
+		}
+	}
+
+	// A file deleted since it was loaded conflicts with no content
+	if err := provider.DeleteFile("docs/a.md"); err != nil {
+		t.Fatalf("DeleteFile failed: %v", err)
+	}
+	_, err = provider.ApplyChangeSet([]ChangeOp{{Op: OpWrite, Path: "docs/a.md", Content: "a", BaseHash: BlobHash([]byte("docs/a.md"))}}, "")
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected ConflictError, got %v", err)
+	}
+	if conflict.Content != nil || conflict.CurrentHash != "" {
+		t.Errorf("expected conflict for a deleted file, got %+v", conflict)
+	}
+}
+
+func TestCheckBaseHash(t *testing.T) {
+	_, provider := createChangeSetProvider(t)
+
+	if err := provider.checkBaseHash("old.md", BlobHash([]byte("old.md"))); err != nil {
+		t.Errorf("expected current base hash to match, got %v", err)
+	}
+	if err := provider.checkBaseHash("/old.md", ""); err != nil {
+		t.Errorf("expected empty base hash to match, got %v", err)
+	}
+
+	var conflict *ConflictError
+	if err := provider.checkBaseHash("old.md", BlobHash([]byte("outdated"))); !errors.As(err, &conflict) {
+		t.Errorf("expected ConflictError for an outdated base hash, got %v", err)
+	}
+	if err := provider.checkBaseHash("missing.md", BlobHash([]byte("missing"))); !errors.As(err, &conflict) || conflict.Content != nil {
+		t.Errorf("expected ConflictError for a missing file, got %v", err)
+	}
+
+	// Paths outside the repository are rejected rather than read
+	if err := provider.checkBaseHash("../outside.md", BlobHash([]byte("x"))); err == nil || errors.As(err, &conflict) {
+		t.Errorf("expected invalid path error, got %v", err)
+	}
+}
+
+func TestFileIfUnchanged(t *testing.T) {
+	_, provider := createChangeSetProvider(t)
+
+	var conflict *ConflictError
+	if err := provider.WriteFileIfUnchanged("old.md", []byte("new"), BlobHash([]byte("outdated"))); !errors.As(err, &conflict) {
+		t.Errorf("expected ConflictError for an outdated write, got %v", err)
+	}
+	if err := provider.WriteFileIfUnchanged("old.md", []byte("new"), BlobHash([]byte("old.md"))); err != nil {
+		t.Errorf("WriteFileIfUnchanged failed: %v", err)
+	}
+	if err := provider.MoveFileIfUnchanged("old.md", "moved.md", BlobHash([]byte("old.md"))); !errors.As(err, &conflict) {
+		t.Errorf("expected ConflictError for an outdated move, got %v", err)
+	}
+	if err := provider.MoveFileIfUnchanged("old.md", "moved.md", BlobHash([]byte("new"))); err != nil {
+		t.Errorf("MoveFileIfUnchanged failed: %v", err)
+	}
+	if err := provider.DeleteFileIfUnchanged("moved.md", BlobHash([]byte("old.md"))); !errors.As(err, &conflict) {
+		t.Errorf("expected ConflictError for an outdated delete, got %v", err)
+	}
+	if err := provider.DeleteFileIfUnchanged("moved.md", BlobHash([]byte("new"))); err != nil {
+		t.Errorf("DeleteFileIfUnchanged failed: %v", err)
+	}
+}
+
+func TestWriteFileIfUnchanged_Concurrent(t *testing.T) {
+	_, provider := createChangeSetProvider(t)
+
+	// Edits based on the same version race; only one of them may win
+	base := BlobHash([]byte("old.md"))
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = provider.WriteFileIfUnchanged("old.md", []byte(fmt.Sprintf("edit %d", i)), base)
+		}()
+	}
+	wg.Wait()
+
+	written := 0
+	for _, err := range errs {
+		var conflict *ConflictError
+		switch {
+		case err == nil:
+			written++
+		case !errors.As(err, &conflict):
+			t.Errorf("expected ConflictError, got %v", err)
+		}
+	}
+	if written != 1 {
+		t.Errorf("expected exactly 1 write to succeed, got %d", written)
+	}
+}
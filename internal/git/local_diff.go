@@ -0,0 +1,171 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Diff returns the uncommitted changes in the working tree compared to HEAD.
+// Both staged and unstaged changes are included, as well as untracked files
+// that are not ignored. Renames are detected when a deleted file's content
+// reappears unchanged under a new path.
+func (p *LocalProvider) Diff() ([]FileDiff, error) {
+	worktree, err := p.repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worktree: %w", err)
+	}
+
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status: %w", err)
+	}
+
+	headTree, err := p.headTree()
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect the old (HEAD) and new (disk) content of every changed path
+	type change struct {
+		path       string
+		oldContent []byte
+		newContent []byte
+	}
+	var changes []change
+
+	for path, fileStatus := range status {
+		if fileStatus.Staging == git.Unmodified && fileStatus.Worktree == git.Unmodified {
+			continue
+		}
+
+		oldContent, err := readTreeFile(headTree, path)
+		if err != nil {
+			return nil, err
+		}
+
+		newContent, err := p.readWorkingFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		// Nothing to show if the content is identical (e.g. only staged mode changes)
+		if oldContent != nil && newContent != nil && string(oldContent) == string(newContent) {
+			continue
+		}
+		if oldContent == nil && newContent == nil {
+			continue
+		}
+
+		changes = append(changes, change{path: path, oldContent: oldContent, newContent: newContent})
+	}
+
+	// Pair deletions with additions of identical content as renames
+	deletedByHash := make(map[plumbing.Hash]int)
+	for i, c := range changes {
+		if c.newContent == nil {
+			deletedByHash[plumbing.ComputeHash(plumbing.BlobObject, c.oldContent)] = i
+		}
+	}
+
+	renamedFrom := make(map[int]int) // addition index -> deletion index
+	consumed := make(map[int]bool)   // deletion indexes that became renames
+	for i, c := range changes {
+		if c.oldContent != nil {
+			continue
+		}
+		if j, ok := deletedByHash[plumbing.ComputeHash(plumbing.BlobObject, c.newContent)]; ok && !consumed[j] {
+			renamedFrom[i] = j
+			consumed[j] = true
+		}
+	}
+
+	diffs := make([]FileDiff, 0, len(changes))
+	for i, c := range changes {
+		if consumed[i] {
+			continue
+		}
+		if j, ok := renamedFrom[i]; ok {
+			from := changes[j]
+			diffs = append(diffs, buildFileDiff(from.path, c.path, from.oldContent, c.newContent))
+			continue
+		}
+		diffs = append(diffs, buildFileDiff(c.path, c.path, c.oldContent, c.newContent))
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Path < diffs[j].Path
+	})
+
+	return diffs, nil
+}
+
+// headTree returns the tree of the HEAD commit, or nil if the repository has no commits yet.
+func (p *LocalProvider) headTree() (*object.Tree, error) {
+	head, err := p.repo.Head()
+	if err != nil {
+		if err == plumbing.ErrReferenceNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+
+	commit, err := p.repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit: %w", err)
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree: %w", err)
+	}
+
+	return tree, nil
+}
+
+// readTreeFile returns the content of a file in a tree, or nil if the tree
+// is nil or does not contain the file.
+func readTreeFile(tree *object.Tree, path string) ([]byte, error) {
+	if tree == nil {
+		return nil, nil
+	}
+
+	file, err := tree.File(path)
+	if err != nil {
+		if err == object.ErrFileNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get file %s: %w", path, err)
+	}
+
+	return fileBytes(file)
+}
+
+// readWorkingFile returns the content of a file in the working tree,
+// or nil if the file does not exist.
+func (p *LocalProvider) readWorkingFile(path string) ([]byte, error) {
+	fullPath := filepath.Join(p.path, filepath.FromSlash(path))
+
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to stat file %s: %w", path, err)
+	}
+	if info.IsDir() {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", path, err)
+	}
+
+	return content, nil
+}
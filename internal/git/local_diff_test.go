@@ -0,0 +1,148 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiff_WorkingTreeChanges(t *testing.T) {
+	tempDir := t.TempDir()
+	repo := createTestRepoWithCommit(t, tempDir)
+
+	// Commit a few files to change afterwards
+	files := map[string]string{
+		"keep.md":   "unchanged\n",
+		"edit.md":   "before\n",
+		"remove.md": "going away\n",
+		"move.md":   "moving content\n",
+	}
+	w, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		if _, err := w.Add(name); err != nil {
+			t.Fatalf("failed to add %s: %v", name, err)
+		}
+	}
+	if _, err := w.Commit("Add files", testCommitOptions()); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	// Modify, delete, rename and add on disk
+	if err := os.WriteFile(filepath.Join(tempDir, "edit.md"), []byte("after\n"), 0644); err != nil {
+		t.Fatalf("failed to edit file: %v", err)
+	}
+	if err := os.Remove(filepath.Join(tempDir, "remove.md")); err != nil {
+		t.Fatalf("failed to remove file: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(tempDir, "docs"), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.Rename(filepath.Join(tempDir, "move.md"), filepath.Join(tempDir, "docs", "moved.md")); err != nil {
+		t.Fatalf("failed to move file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "new.md"), []byte("brand new\n"), 0644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	diffs, err := provider.Diff()
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+
+	byPath := make(map[string]FileDiff)
+	for _, d := range diffs {
+		byPath[d.Path] = d
+	}
+
+	if len(diffs) != 4 {
+		t.Fatalf("expected 4 changed files, got %d: %+v", len(diffs), diffs)
+	}
+
+	if d := byPath["edit.md"]; d.Status != FileModified || d.Additions != 1 || d.Deletions != 1 {
+		t.Errorf("unexpected diff for edit.md: %+v", d)
+	}
+	if d := byPath["remove.md"]; d.Status != FileDeleted {
+		t.Errorf("expected remove.md to be deleted, got %+v", d)
+	}
+	if d := byPath["docs/moved.md"]; d.Status != FileRenamed || d.OldPath != "move.md" {
+		t.Errorf("expected move.md renamed to docs/moved.md, got %+v", d)
+	}
+	if d := byPath["new.md"]; d.Status != FileAdded || d.Additions != 1 {
+		t.Errorf("expected new.md to be added, got %+v", d)
+	}
+	if _, ok := byPath["keep.md"]; ok {
+		t.Error("unchanged file should not be reported")
+	}
+
+	// Sorted by path
+	for i := 1; i < len(diffs); i++ {
+		if diffs[i-1].Path > diffs[i].Path {
+			t.Errorf("diffs not sorted: %s before %s", diffs[i-1].Path, diffs[i].Path)
+		}
+	}
+}
+
+func TestDiff_CleanTree(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	diffs, err := provider.Diff()
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if len(diffs) != 0 {
+		t.Errorf("expected no changes, got %+v", diffs)
+	}
+}
+
+func TestDiff_RespectsGitignore(t *testing.T) {
+	tempDir := t.TempDir()
+	repo := createTestRepoWithCommit(t, tempDir)
+
+	if err := os.WriteFile(filepath.Join(tempDir, ".gitignore"), []byte("*.log\n"), 0644); err != nil {
+		t.Fatalf("failed to write .gitignore: %v", err)
+	}
+	w, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	if _, err := w.Add(".gitignore"); err != nil {
+		t.Fatalf("failed to add .gitignore: %v", err)
+	}
+	if _, err := w.Commit("Ignore logs", testCommitOptions()); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(tempDir, "debug.log"), []byte("noise\n"), 0644); err != nil {
+		t.Fatalf("failed to write log: %v", err)
+	}
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	diffs, err := provider.Diff()
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if len(diffs) != 0 {
+		t.Errorf("expected ignored files to be excluded, got %+v", diffs)
+	}
+}
@@ -0,0 +1,140 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gitkeepFile is the placeholder written into new folders, since git does
+// not track empty directories.
+const gitkeepFile = ".gitkeep"
+
+// CreateFolder creates a folder at the given path, with a .gitkeep file so the
+// folder is kept when committed. Parent folders are created as needed.
+// Returns an error if the path already exists.
+func (p *LocalProvider) CreateFolder(path string) error {
+	fullPath, err := p.folderPath(path)
+	if err != nil {
+		return err
+	}
+
+	// Check if the path already exists
+	if info, err := os.Stat(fullPath); err == nil {
+		if info.IsDir() {
+			return fmt.Errorf("folder already exists")
+		}
+		return fmt.Errorf("a file already exists at that path")
+	}
+
+	// Create the folder and any missing parents
+	if err := os.MkdirAll(fullPath, 0755); err != nil {
+		return fmt.Errorf("failed to create folder: %w", err)
+	}
+
+	// Add the placeholder so git tracks the folder
+	if err := os.WriteFile(filepath.Join(fullPath, gitkeepFile), nil, 0644); err != nil {
+		return fmt.Errorf("failed to create %s: %w", gitkeepFile, err)
+	}
+
+	return nil
+}
+
+// DeleteFolder removes the folder at the given path. Unless recursive is true,
+// the folder must be empty apart from a .gitkeep file.
+// Returns an error if the folder doesn't exist or is a file.
+func (p *LocalProvider) DeleteFolder(path string, recursive bool) error {
+	fullPath, err := p.folderPath(path)
+	if err != nil {
+		return err
+	}
+
+	// Check if folder exists and is a directory
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("folder not found")
+		}
+		return fmt.Errorf("failed to stat folder: %w", err)
+	}
+
+	if !info.IsDir() {
+		return fmt.Errorf("path is not a directory")
+	}
+
+	if recursive {
+		if err := os.RemoveAll(fullPath); err != nil {
+			return fmt.Errorf("failed to delete folder: %w", err)
+		}
+		return nil
+	}
+
+	// Only a placeholder may remain in a folder deleted non-recursively
+	entries, err := os.ReadDir(fullPath)
+	if err != nil {
+		return fmt.Errorf("failed to read folder: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.Name() != gitkeepFile || entry.IsDir() {
+			return fmt.Errorf("folder is not empty")
+		}
+	}
+
+	if err := os.Remove(filepath.Join(fullPath, gitkeepFile)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete %s: %w", gitkeepFile, err)
+	}
+	if err := os.Remove(fullPath); err != nil {
+		return fmt.Errorf("failed to delete folder: %w", err)
+	}
+
+	return nil
+}
+
+// folderPath validates a folder path from a client and returns its absolute
+// location. The path must name something strictly inside the repository root
+// and outside .git, so the root itself ("." or "./") is rejected.
+func (p *LocalProvider) folderPath(path string) (string, error) {
+	// Normalize path: strip leading/trailing slashes, convert to forward slashes
+	path = strings.Trim(filepath.ToSlash(path), "/")
+
+	// Validate path is not empty
+	if path == "" {
+		return "", fmt.Errorf("path cannot be empty")
+	}
+
+	// Security: validate path doesn't escape repository root
+	if strings.Contains(path, "..") {
+		return "", fmt.Errorf("invalid path: cannot contain '..'")
+	}
+
+	// Collapse "." segments so "./" and "./.git" are caught below
+	path = filepath.ToSlash(filepath.Clean(filepath.FromSlash(path)))
+	if path == "." {
+		return "", fmt.Errorf("invalid path: cannot modify the repository root")
+	}
+
+	if IsGitDirPath(path) {
+		return "", fmt.Errorf("invalid path: cannot modify .git")
+	}
+
+	// Convert to OS-specific path separator
+	root := filepath.Clean(p.path)
+	fullPath := filepath.Join(root, filepath.FromSlash(path))
+
+	// The result must be strictly inside the repository root
+	rel, err := filepath.Rel(root, fullPath)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid path: must be inside the repository")
+	}
+
+	return fullPath, nil
+}
+
+// IsGitDirPath reports whether a slash-separated path relative to the
+// repository root is the .git directory or inside it, e.g. ".git/hooks" or
+// "./.git". Such paths must never be written to.
+func IsGitDirPath(path string) bool {
+	path = filepath.ToSlash(filepath.Clean(filepath.FromSlash(strings.Trim(path, "/"))))
+	return path == ".git" || strings.HasPrefix(path, ".git/")
+}
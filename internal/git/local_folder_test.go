@@ -0,0 +1,217 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCreateFolder(t *testing.T) {
+	// Create temporary git repository with initial commit
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	// Create a nested folder whose parent doesn't exist yet
+	if err := provider.CreateFolder("/docs/guides/"); err != nil {
+		t.Fatalf("CreateFolder failed: %v", err)
+	}
+
+	// Verify the folder exists with a .gitkeep file
+	gitkeepPath := filepath.Join(provider.path, "docs", "guides", ".gitkeep")
+	info, err := os.Stat(gitkeepPath)
+	if err != nil {
+		t.Fatalf(".gitkeep not found in new folder: %v", err)
+	}
+	if info.Size() != 0 {
+		t.Errorf("expected empty .gitkeep, got %d bytes", info.Size())
+	}
+
+	// Verify the folder survives a commit
+	isolateGitConfig(t, "")
+	if _, err := provider.Commit("Add guides folder"); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if !committedFiles(t, provider)["docs/guides/.gitkeep"] {
+		t.Error("expected docs/guides/.gitkeep to be committed")
+	}
+}
+
+func TestCreateFolder_AlreadyExists(t *testing.T) {
+	// Create temporary git repository with initial commit
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	// Create existing folder and file
+	if err := os.MkdirAll(filepath.Join(provider.path, "existing"), 0755); err != nil {
+		t.Fatalf("Failed to create folder: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(provider.path, "file.txt"), []byte("content"), 0644); err != nil {
+		t.Fatalf("Failed to create file.txt: %v", err)
+	}
+
+	err = provider.CreateFolder("existing")
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("Expected error when folder exists, got %v", err)
+	}
+
+	err = provider.CreateFolder("file.txt")
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("Expected error when a file exists, got %v", err)
+	}
+}
+
+func TestCreateFolder_InvalidPath(t *testing.T) {
+	// Create temporary git repository with initial commit
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	for _, path := range []string{"", "/", ".", "./", "../outside", ".git/hooks", "./.git", "./.git/hooks"} {
+		if err := provider.CreateFolder(path); err == nil {
+			t.Errorf("Expected error creating folder %q, got nil", path)
+		}
+	}
+}
+
+func TestDeleteFolder(t *testing.T) {
+	// Create temporary git repository with initial commit
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	// Create folder with files
+	srcDir := filepath.Join(provider.path, "oldfolder")
+	nestedDir := filepath.Join(srcDir, "nested")
+	if err := os.MkdirAll(nestedDir, 0755); err != nil {
+		t.Fatalf("Failed to create nested folder: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "file1.txt"), []byte("content1"), 0644); err != nil {
+		t.Fatalf("Failed to create file1.txt: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(nestedDir, "file2.txt"), []byte("content2"), 0644); err != nil {
+		t.Fatalf("Failed to create file2.txt: %v", err)
+	}
+
+	// A folder with contents is only deleted recursively
+	err = provider.DeleteFolder("oldfolder", false)
+	if err == nil || !strings.Contains(err.Error(), "not empty") {
+		t.Fatalf("Expected not empty error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(nestedDir, "file2.txt")); err != nil {
+		t.Errorf("Folder contents were removed: %v", err)
+	}
+
+	if err := provider.DeleteFolder("oldfolder", true); err != nil {
+		t.Fatalf("DeleteFolder failed: %v", err)
+	}
+
+	// Verify folder doesn't exist
+	if _, err := os.Stat(srcDir); !os.IsNotExist(err) {
+		t.Errorf("Folder still exists")
+	}
+}
+
+func TestDeleteFolder_OnlyGitkeep(t *testing.T) {
+	// Create temporary git repository with initial commit
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	if err := provider.CreateFolder("empty"); err != nil {
+		t.Fatalf("CreateFolder failed: %v", err)
+	}
+
+	// A folder holding only .gitkeep counts as empty
+	if err := provider.DeleteFolder("empty", false); err != nil {
+		t.Fatalf("DeleteFolder failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(provider.path, "empty")); !os.IsNotExist(err) {
+		t.Errorf("Folder still exists")
+	}
+}
+
+func TestDeleteFolder_NotFound(t *testing.T) {
+	// Create temporary git repository with initial commit
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	err = provider.DeleteFolder("nonexistent", true)
+	if err == nil || !strings.Contains(err.Error(), "folder not found") {
+		t.Fatalf("Expected folder not found error, got %v", err)
+	}
+}
+
+func TestDeleteFolder_InvalidPath(t *testing.T) {
+	// Create temporary git repository with initial commit
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(provider.path, "file.txt"), []byte("content"), 0644); err != nil {
+		t.Fatalf("Failed to create file.txt: %v", err)
+	}
+
+	for _, path := range []string{"", "/", ".", "./", "../outside", ".git", "./.git", "file.txt"} {
+		if err := provider.DeleteFolder(path, true); err == nil {
+			t.Errorf("Expected error deleting folder %q, got nil", path)
+		}
+	}
+
+	// The repository is untouched
+	if _, err := os.Stat(filepath.Join(provider.path, ".git")); err != nil {
+		t.Errorf(".git was removed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(provider.path, "file.txt")); err != nil {
+		t.Errorf("file.txt was removed: %v", err)
+	}
+}
+
+func TestIsGitDirPath(t *testing.T) {
+	tests := map[string]bool{
+		".git":             true,
+		".git/hooks/x":     true,
+		"/.git/config":     true,
+		"./.git/hooks":     true,
+		"docs/../.git":     true,
+		".gitignore":       false,
+		".github/workflow": false,
+		"docs/.git":        false,
+	}
+	for path, expected := range tests {
+		if got := IsGitDirPath(path); got != expected {
+			t.Errorf("IsGitDirPath(%q) = %v, want %v", path, got, expected)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package git
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// defaultLogLimit is the page size used when Log is called without a limit.
+const defaultLogLimit = 50
+
+// maxLogLimit caps the page size to keep responses reasonably small.
+const maxLogLimit = 500
+
+// Log returns the commit history for the given branch, newest first.
+// If path is non-empty, only commits that changed that file (or any file
+// beneath that directory) are included.
+// The cursor is the hash of the last commit of the previous page; an empty
+// cursor starts from the branch tip.
+func (p *LocalProvider) Log(path, branch string, limit int, cursor string) (*LogPage, error) {
+	// Normalize path: strip leading/trailing slashes, convert to forward slashes
+	path = strings.Trim(path, "/")
+	path = filepath.ToSlash(path)
+
+	// Security: validate path doesn't escape repository root
+	if strings.Contains(path, "..") {
+		return nil, fmt.Errorf("invalid path: cannot contain '..'")
+	}
+
+	if limit <= 0 {
+		limit = defaultLogLimit
+	}
+	if limit > maxLogLimit {
+		limit = maxLogLimit
+	}
+
+	var cursorHash plumbing.Hash
+	if cursor != "" {
+		if !plumbing.IsHash(cursor) {
+			return nil, fmt.Errorf("invalid cursor: %s", cursor)
+		}
+		cursorHash = plumbing.NewHash(cursor)
+	}
+
+	head, err := p.resolveCommit(branch)
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := p.repo.Log(&git.LogOptions{
+		From:  head.Hash,
+		Order: git.LogOrderCommitterTime,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	defer iter.Close()
+
+	page := &LogPage{Commits: []CommitInfo{}}
+	foundCursor := cursor == ""
+
+	err = iter.ForEach(func(c *object.Commit) error {
+		// Skip everything up to and including the cursor commit
+		if !foundCursor {
+			if c.Hash == cursorHash {
+				foundCursor = true
+			}
+			return nil
+		}
+
+		paths, err := changedPaths(c)
+		if err != nil {
+			return err
+		}
+
+		if path != "" && !touchesPath(paths, path) {
+			return nil
+		}
+
+		// One more matching commit than the page holds means there is a next page
+		if len(page.Commits) == limit {
+			page.NextCursor = page.Commits[limit-1].Hash
+			return storer.ErrStop
+		}
+
+		page.Commits = append(page.Commits, newCommitInfo(c, paths, p.signatureInfo(c)))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk history: %w", err)
+	}
+
+	if !foundCursor {
+		return nil, fmt.Errorf("invalid cursor: commit %s is not in the history of this branch", cursor)
+	}
+
+	return page, nil
+}
+
+// changedPaths returns the paths changed by a commit relative to its first parent.
+// For a root commit, every file in the tree is reported as changed.
+func changedPaths(c *object.Commit) ([]string, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree: %w", err)
+	}
+
+	var parentTree *object.Tree
+	if c.NumParents() > 0 {
+		parent, err := c.Parent(0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get parent commit: %w", err)
+		}
+		parentTree, err = parent.Tree()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get parent tree: %w", err)
+		}
+	}
+
+	changes, err := object.DiffTree(parentTree, tree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff commit: %w", err)
+	}
+
+	paths := make([]string, 0, len(changes))
+	for _, change := range changes {
+		// Renames report both sides; deletions only have a From name
+		if change.From.Name != "" {
+			paths = append(paths, change.From.Name)
+		}
+		if change.To.Name != "" && change.To.Name != change.From.Name {
+			paths = append(paths, change.To.Name)
+		}
+	}
+
+	return paths, nil
+}
+
+// touchesPath reports whether any of the changed paths is the given file
+// or lies beneath the given directory.
+func touchesPath(changed []string, path string) bool {
+	for _, p := range changed {
+		if p == path || strings.HasPrefix(p, path+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// newCommitInfo converts a commit object into its API representation.
+func newCommitInfo(c *object.Commit, paths []string, signature SignatureInfo) CommitInfo {
+	return CommitInfo{
+		Hash:      c.Hash.String(),
+		Author:    c.Author.Name,
+		Email:     c.Author.Email,
+		Date:      c.Author.When,
+		Message:   c.Message,
+		Paths:     paths,
+		Signature: signature,
+	}
+}
@@ -0,0 +1,244 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// commitFile writes a file into the repository and commits it with a fixed author time,
+// so history ordering is deterministic.
+func commitFile(t *testing.T, repo *git.Repository, dir, path, content, message string, when time.Time) string {
+	t.Helper()
+
+	fullPath := filepath.Join(dir, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		t.Fatalf("failed to create directories: %v", err)
+	}
+	if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+
+	w, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	if _, err := w.Add(path); err != nil {
+		t.Fatalf("failed to add %s: %v", path, err)
+	}
+
+	sig := &object.Signature{Name: "Test Author", Email: "test@example.com", When: when}
+	hash, err := w.Commit(message, &git.CommitOptions{Author: sig, Committer: sig})
+	if err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	return hash.String()
+}
+
+// createHistoryRepo creates a repository with a small, time-ordered history:
+// README.md, docs/guide.md, docs/api.md, then an edit to README.md.
+func createHistoryRepo(t *testing.T) (string, *git.Repository, []string) {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	repo, err := git.PlainInit(tempDir, false)
+	if err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	hashes := []string{
+		commitFile(t, repo, tempDir, "README.md", "# Readme\n", "Add readme", base),
+		commitFile(t, repo, tempDir, "docs/guide.md", "# Guide\n", "Add guide", base.Add(time.Hour)),
+		commitFile(t, repo, tempDir, "docs/api.md", "# API\n", "Add api docs", base.Add(2*time.Hour)),
+		commitFile(t, repo, tempDir, "README.md", "# Readme\n\nMore.\n", "Update readme", base.Add(3*time.Hour)),
+	}
+
+	return tempDir, repo, hashes
+}
+
+func TestLog_AllCommits(t *testing.T) {
+	tempDir, _, hashes := createHistoryRepo(t)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	page, err := provider.Log("", "", 0, "")
+	if err != nil {
+		t.Fatalf("Log failed: %v", err)
+	}
+
+	if len(page.Commits) != 4 {
+		t.Fatalf("expected 4 commits, got %d", len(page.Commits))
+	}
+
+	// Newest first
+	for i, commit := range page.Commits {
+		expected := hashes[len(hashes)-1-i]
+		if commit.Hash != expected {
+			t.Errorf("commit %d: expected hash %s, got %s", i, expected, commit.Hash)
+		}
+	}
+
+	latest := page.Commits[0]
+	if latest.Author != "Test Author" || latest.Email != "test@example.com" {
+		t.Errorf("unexpected author: %s <%s>", latest.Author, latest.Email)
+	}
+	if strings.TrimSpace(latest.Message) != "Update readme" {
+		t.Errorf("unexpected message: %q", latest.Message)
+	}
+	if len(latest.Paths) != 1 || latest.Paths[0] != "README.md" {
+		t.Errorf("expected paths [README.md], got %v", latest.Paths)
+	}
+
+	if page.NextCursor != "" {
+		t.Errorf("expected no next cursor, got %q", page.NextCursor)
+	}
+}
+
+func TestLog_FilterByFile(t *testing.T) {
+	tempDir, _, hashes := createHistoryRepo(t)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	page, err := provider.Log("README.md", "", 0, "")
+	if err != nil {
+		t.Fatalf("Log failed: %v", err)
+	}
+
+	if len(page.Commits) != 2 {
+		t.Fatalf("expected 2 commits touching README.md, got %d", len(page.Commits))
+	}
+	if page.Commits[0].Hash != hashes[3] || page.Commits[1].Hash != hashes[0] {
+		t.Errorf("unexpected commits for README.md: %s, %s", page.Commits[0].Hash, page.Commits[1].Hash)
+	}
+}
+
+func TestLog_FilterByDirectory(t *testing.T) {
+	tempDir, _, hashes := createHistoryRepo(t)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	page, err := provider.Log("/docs/", "", 0, "")
+	if err != nil {
+		t.Fatalf("Log failed: %v", err)
+	}
+
+	if len(page.Commits) != 2 {
+		t.Fatalf("expected 2 commits touching docs/, got %d", len(page.Commits))
+	}
+	if page.Commits[0].Hash != hashes[2] || page.Commits[1].Hash != hashes[1] {
+		t.Errorf("unexpected commits for docs/: %s, %s", page.Commits[0].Hash, page.Commits[1].Hash)
+	}
+
+	// A directory name prefix must not match sibling paths
+	page, err = provider.Log("doc", "", 0, "")
+	if err != nil {
+		t.Fatalf("Log failed: %v", err)
+	}
+	if len(page.Commits) != 0 {
+		t.Errorf("expected no commits for 'doc', got %d", len(page.Commits))
+	}
+}
+
+func TestLog_Pagination(t *testing.T) {
+	tempDir, _, hashes := createHistoryRepo(t)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	var seen []string
+	cursor := ""
+	for pages := 0; pages < 10; pages++ {
+		page, err := provider.Log("", "", 3, cursor)
+		if err != nil {
+			t.Fatalf("Log failed: %v", err)
+		}
+		for _, c := range page.Commits {
+			seen = append(seen, c.Hash)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	if len(seen) != len(hashes) {
+		t.Fatalf("expected %d commits across pages, got %d", len(hashes), len(seen))
+	}
+	for i, hash := range seen {
+		if hash != hashes[len(hashes)-1-i] {
+			t.Errorf("commit %d: expected %s, got %s", i, hashes[len(hashes)-1-i], hash)
+		}
+	}
+}
+
+func TestLog_ExactPageHasNoCursor(t *testing.T) {
+	tempDir, _, _ := createHistoryRepo(t)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	page, err := provider.Log("", "", 4, "")
+	if err != nil {
+		t.Fatalf("Log failed: %v", err)
+	}
+	if len(page.Commits) != 4 {
+		t.Fatalf("expected 4 commits, got %d", len(page.Commits))
+	}
+	if page.NextCursor != "" {
+		t.Errorf("expected no next cursor when history fits the page, got %q", page.NextCursor)
+	}
+}
+
+func TestLog_Errors(t *testing.T) {
+	tempDir, _, _ := createHistoryRepo(t)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		path        string
+		branch      string
+		cursor      string
+		errContains string
+	}{
+		{"malformed cursor", "", "", "not-a-hash", "invalid cursor"},
+		{"unknown cursor", "", "", strings.Repeat("a", 40), "invalid cursor"},
+		{"unknown branch", "", "nonexistent", "", "not found"},
+		{"path traversal", "../etc", "", "", "invalid path"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := provider.Log(tt.path, tt.branch, 0, tt.cursor)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("expected error containing %q, got %v", tt.errContains, err)
+			}
+		})
+	}
+}
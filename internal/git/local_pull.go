@@ -0,0 +1,149 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+// Reasons a pull can be refused, reported in PullBlockedError.Reason.
+const (
+	PullDirtyTree = "dirty-tree" // the working tree has uncommitted changes
+	PullDiverged  = "diverged"   // local and remote histories have diverged
+)
+
+// PullBlockedError is returned when a pull cannot be applied without
+// losing work: the working tree is dirty, or the branch has diverged from its upstream.
+type PullBlockedError struct {
+	Reason string   // PullDirtyTree or PullDiverged
+	Paths  []string // uncommitted paths, for PullDirtyTree
+}
+
+func (e *PullBlockedError) Error() string {
+	switch e.Reason {
+	case PullDirtyTree:
+		return "cannot pull: working tree has uncommitted changes; commit them first"
+	case PullDiverged:
+		return "cannot pull: local and remote branches have diverged and cannot be fast-forwarded"
+	default:
+		return "cannot pull: " + e.Reason
+	}
+}
+
+// Fetch downloads new commits and branches from a remote without changing the working tree.
+// An empty remote means the current branch's upstream remote, or "origin".
+// Progress messages are written to progress if non-nil.
+func (p *LocalProvider) Fetch(ctx context.Context, remote string, progress io.Writer) error {
+	remote, auth, err := p.resolveRemote(remote, p.currentBranch())
+	if err != nil {
+		return err
+	}
+
+	err = p.repo.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: remote,
+		Auth:       auth,
+		Progress:   progress,
+	})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return fmt.Errorf("failed to fetch: %w", err)
+	}
+
+	return nil
+}
+
+// Pull fetches from a remote and fast-forwards the current branch to its upstream.
+// An empty remote means the current branch's upstream remote, or "origin".
+// Progress messages are written to progress if non-nil.
+// Returns a *PullBlockedError if the working tree is dirty or the branch has diverged.
+func (p *LocalProvider) Pull(ctx context.Context, remote string, progress io.Writer) error {
+	branch := p.currentBranch()
+	if branch == "HEAD" {
+		return fmt.Errorf("cannot pull in detached HEAD state")
+	}
+
+	remote, auth, err := p.resolveRemote(remote, branch)
+	if err != nil {
+		return err
+	}
+
+	// Refuse before going to the network when the pull could not be applied anyway
+	if err := p.checkPullClean(); err != nil {
+		return err
+	}
+
+	// Fetch without the lock so reads are not blocked on the network
+	target, err := fetchUpstream(ctx, p.repo, remote, branch, auth, progress)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.branch != branch {
+		return fmt.Errorf("cannot pull: switched to branch '%s' during the pull", p.branch)
+	}
+	// Check again: files may have been edited during the fetch
+	if err := p.checkPullClean(); err != nil {
+		return err
+	}
+
+	return fastForward(p.repo, target)
+}
+
+// checkPullClean returns a *PullBlockedError listing the uncommitted paths
+// if the working tree differs from HEAD.
+func (p *LocalProvider) checkPullClean() error {
+	worktree, err := p.repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree: %w", err)
+	}
+	headTree, err := p.headTree()
+	if err != nil {
+		return err
+	}
+	changes, err := p.worktreeChanges(worktree, headTree)
+	if err != nil {
+		return err
+	}
+	if len(changes) > 0 {
+		paths := make([]string, 0, len(changes))
+		for _, change := range changes {
+			paths = append(paths, change.Path)
+		}
+		return &PullBlockedError{Reason: PullDirtyTree, Paths: paths}
+	}
+	return nil
+}
+
+// resolveRemote returns the remote to use and its authentication.
+// An empty name means the branch's upstream remote, or "origin".
+func (p *LocalProvider) resolveRemote(name, branch string) (string, transport.AuthMethod, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = defaultRemote
+
+		cfg, err := p.repo.Config()
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to read repository config: %w", err)
+		}
+		if upstream := cfg.Branches[branch]; upstream != nil && upstream.Remote != "" {
+			name = upstream.Remote
+		}
+	}
+
+	url, err := p.RemoteURL(name)
+	if err != nil {
+		return "", nil, err
+	}
+
+	auth, err := p.creds.authFor(url)
+	if err != nil {
+		return "", nil, err
+	}
+	return name, auth, nil
+}
@@ -0,0 +1,259 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// pushFromOtherClone clones the remote of the repository at localDir into a new
+// directory, commits a file there, and pushes it. Returns the new commit hash.
+func pushFromOtherClone(t *testing.T, localDir, path, content string) string {
+	t.Helper()
+
+	provider, err := NewLocalProvider(localDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	remoteDir, err := provider.RemoteURL("origin")
+	if err != nil {
+		t.Fatalf("RemoteURL failed: %v", err)
+	}
+
+	otherDir := t.TempDir()
+	if err := CloneRemote(remoteDir, otherDir); err != nil {
+		t.Fatalf("clone failed: %v", err)
+	}
+	other, err := git.PlainOpen(otherDir)
+	if err != nil {
+		t.Fatalf("failed to open clone: %v", err)
+	}
+
+	hash := commitFile(t, other, otherDir, path, content, "Remote change", time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC))
+	if err := other.Push(&git.PushOptions{RemoteName: "origin"}); err != nil {
+		t.Fatalf("failed to push from other clone: %v", err)
+	}
+
+	return hash
+}
+
+func TestFetch(t *testing.T) {
+	_, localDir, branch := createPushRepos(t)
+	remoteTip := pushFromOtherClone(t, localDir, "remote.md", "remote\n")
+
+	provider, err := NewLocalProvider(localDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	var progress bytes.Buffer
+	if err := provider.Fetch(context.Background(), "", &progress); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+
+	ref, err := provider.repo.Reference(plumbing.NewRemoteReferenceName("origin", branch), false)
+	if err != nil {
+		t.Fatalf("expected origin/%s after fetch: %v", branch, err)
+	}
+	if ref.Hash().String() != remoteTip {
+		t.Errorf("expected origin/%s at %s, got %s", branch, remoteTip, ref.Hash())
+	}
+
+	// The working tree is untouched
+	if got := readTestFile(t, localDir, "remote.md"); got != "" {
+		t.Errorf("expected fetch not to change the working tree, got remote.md = %q", got)
+	}
+
+	// Fetching again is a no-op
+	if err := provider.Fetch(context.Background(), "origin", nil); err != nil {
+		t.Errorf("expected up-to-date fetch to succeed, got %v", err)
+	}
+}
+
+func TestPull_FastForward(t *testing.T) {
+	_, localDir, _ := createPushRepos(t)
+	remoteTip := pushFromOtherClone(t, localDir, "remote.md", "remote\n")
+
+	provider, err := NewLocalProvider(localDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	if err := provider.Pull(context.Background(), "", nil); err != nil {
+		t.Fatalf("Pull failed: %v", err)
+	}
+
+	head, err := provider.repo.Head()
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v", err)
+	}
+	if head.Hash().String() != remoteTip {
+		t.Errorf("expected HEAD at %s, got %s", remoteTip, head.Hash())
+	}
+	if got := readTestFile(t, localDir, "remote.md"); got != "remote\n" {
+		t.Errorf("expected pulled remote.md, got %q", got)
+	}
+
+	// Pulling again is a no-op
+	if err := provider.Pull(context.Background(), "", nil); err != nil {
+		t.Errorf("expected up-to-date pull to succeed, got %v", err)
+	}
+}
+
+func TestPull_DirtyTree(t *testing.T) {
+	_, localDir, _ := createPushRepos(t)
+	pushFromOtherClone(t, localDir, "remote.md", "remote\n")
+
+	provider, err := NewLocalProvider(localDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	before, err := provider.repo.Head()
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v", err)
+	}
+
+	if err := provider.WriteFile("README.md", []byte("edited\n")); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	err = provider.Pull(context.Background(), "", nil)
+	var blocked *PullBlockedError
+	if !errors.As(err, &blocked) {
+		t.Fatalf("expected PullBlockedError, got %v", err)
+	}
+	if blocked.Reason != PullDirtyTree {
+		t.Errorf("expected reason %s, got %s", PullDirtyTree, blocked.Reason)
+	}
+	if len(blocked.Paths) != 1 || blocked.Paths[0] != "README.md" {
+		t.Errorf("expected dirty paths [README.md], got %v", blocked.Paths)
+	}
+
+	after, err := provider.repo.Head()
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v", err)
+	}
+	if after.Hash() != before.Hash() {
+		t.Error("expected HEAD to be unchanged after a blocked pull")
+	}
+	if got := readTestFile(t, localDir, "README.md"); got != "edited\n" {
+		t.Errorf("expected local edit to be preserved, got %q", got)
+	}
+}
+
+func TestPull_Diverged(t *testing.T) {
+	_, localDir, _ := createPushRepos(t)
+	pushFromOtherClone(t, localDir, "remote.md", "remote\n")
+
+	local, err := git.PlainOpen(localDir)
+	if err != nil {
+		t.Fatalf("failed to open repo: %v", err)
+	}
+	commitFile(t, local, localDir, "local.md", "local\n", "Local change", time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC))
+
+	provider, err := NewLocalProvider(localDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	err = provider.Pull(context.Background(), "", nil)
+	var blocked *PullBlockedError
+	if !errors.As(err, &blocked) {
+		t.Fatalf("expected PullBlockedError, got %v", err)
+	}
+	if blocked.Reason != PullDiverged {
+		t.Errorf("expected reason %s, got %s", PullDiverged, blocked.Reason)
+	}
+}
+
+func TestPull_UnknownRemote(t *testing.T) {
+	_, localDir, _ := createPushRepos(t)
+
+	provider, err := NewLocalProvider(localDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	if err := provider.Pull(context.Background(), "upstream", nil); err == nil {
+		t.Error("expected error for unknown remote")
+	}
+	if err := provider.Fetch(context.Background(), "upstream", nil); err == nil {
+		t.Error("expected error for unknown remote")
+	}
+}
+
+// lockProbe is a progress writer that records whether the provider's branch
+// lock could be read-locked while progress was being written.
+type lockProbe struct {
+	provider *LocalProvider
+	writes   int
+	blocked  bool
+}
+
+func (lp *lockProbe) Write(p []byte) (int, error) {
+	lp.writes++
+	if lp.provider.mu.TryRLock() {
+		lp.provider.mu.RUnlock()
+	} else {
+		lp.blocked = true
+	}
+	return len(p), nil
+}
+
+func TestPull_FetchDoesNotBlockReads(t *testing.T) {
+	_, localDir, _ := createPushRepos(t)
+	pushFromOtherClone(t, localDir, "remote.md", "remote\n")
+
+	provider, err := NewLocalProvider(localDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	probe := &lockProbe{provider: provider}
+	if err := provider.Pull(context.Background(), "", probe); err != nil {
+		t.Fatalf("Pull failed: %v", err)
+	}
+	if probe.writes == 0 {
+		t.Skip("remote reported no progress")
+	}
+	if probe.blocked {
+		t.Error("expected the branch lock to be free while fetching")
+	}
+}
+
+func TestPull_Cancelled(t *testing.T) {
+	_, localDir, _ := createPushRepos(t)
+	pushFromOtherClone(t, localDir, "remote.md", "remote\n")
+
+	provider, err := NewLocalProvider(localDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	before, err := provider.repo.Head()
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := provider.Pull(ctx, "", nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	after, err := provider.repo.Head()
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v", err)
+	}
+	if after.Hash() != before.Hash() {
+		t.Error("expected HEAD to be unchanged after a cancelled pull")
+	}
+	if got := readTestFile(t, localDir, "remote.md"); got != "" {
+		t.Errorf("expected cancelled pull not to change the working tree, got remote.md = %q", got)
+	}
+}
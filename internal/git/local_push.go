@@ -0,0 +1,147 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// defaultRemote is the remote used when none is given and the branch has no upstream.
+const defaultRemote = "origin"
+
+// PushRejectedError is returned when the remote refuses a push,
+// typically because the branch has new commits that are not present locally.
+type PushRejectedError struct {
+	Remote string // remote name, e.g. "origin"
+	Branch string // branch that was pushed
+	Reason string // reason reported for the rejection, e.g. "non-fast-forward"
+}
+
+func (e *PushRejectedError) Error() string {
+	return fmt.Sprintf("push to '%s' rejected for branch '%s': %s", e.Remote, e.Branch, e.Reason)
+}
+
+// SetToken sets the personal access token used to authenticate with HTTP(S) remotes.
+// An empty token disables authentication.
+func (p *LocalProvider) SetToken(token string) {
+	p.SetCredentials(Credentials{Token: token})
+}
+
+// SetCredentials sets the credentials used to authenticate with remotes:
+// the token for HTTP(S) remotes, and the SSH options for SSH remotes.
+func (p *LocalProvider) SetCredentials(creds Credentials) {
+	p.creds = creds
+}
+
+// RemoteURL returns the first configured URL of the named remote.
+func (p *LocalProvider) RemoteURL(name string) (string, error) {
+	remote, err := p.repo.Remote(name)
+	if err != nil {
+		return "", fmt.Errorf("remote '%s' not found", name)
+	}
+
+	urls := remote.Config().URLs
+	if len(urls) == 0 {
+		return "", fmt.Errorf("remote '%s' has no URL", name)
+	}
+
+	return urls[0], nil
+}
+
+// Push pushes a local branch to a remote.
+// If branch is empty, the current branch is pushed. If remote is empty, the
+// branch's upstream remote is used, falling back to "origin". A branch without
+// an upstream is set to track the branch of the same name on the remote.
+// Pushing a branch that is already up to date is not an error.
+// Returns a *PushRejectedError if the remote refuses the update.
+func (p *LocalProvider) Push(remote, branch string) error {
+	branch = strings.TrimSpace(branch)
+	if branch == "" {
+		branch = p.currentBranch()
+	}
+	if branch == "HEAD" {
+		return fmt.Errorf("cannot push in detached HEAD state")
+	}
+
+	ref := plumbing.NewBranchReferenceName(branch)
+	if _, err := p.repo.Reference(ref, false); err != nil {
+		return fmt.Errorf("branch '%s' not found", branch)
+	}
+
+	cfg, err := p.repo.Config()
+	if err != nil {
+		return fmt.Errorf("failed to read repository config: %w", err)
+	}
+
+	upstream := cfg.Branches[branch]
+	remote = strings.TrimSpace(remote)
+	if remote == "" {
+		remote = defaultRemote
+		if upstream != nil && upstream.Remote != "" {
+			remote = upstream.Remote
+		}
+	}
+
+	url, err := p.RemoteURL(remote)
+	if err != nil {
+		return err
+	}
+
+	pushOpts := &git.PushOptions{
+		RemoteName: remote,
+		RefSpecs:   []config.RefSpec{config.RefSpec(fmt.Sprintf("%s:%s", ref, ref))},
+	}
+
+	if pushOpts.Auth, err = p.creds.authFor(url); err != nil {
+		return err
+	}
+
+	err = p.repo.Push(pushOpts)
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		if reason, ok := pushRejection(err); ok {
+			return &PushRejectedError{Remote: remote, Branch: branch, Reason: reason}
+		}
+		return fmt.Errorf("failed to push: %w", err)
+	}
+
+	// Track the remote branch so later pushes and pulls know where to go
+	if upstream == nil {
+		err := p.repo.CreateBranch(&config.Branch{
+			Name:   branch,
+			Remote: remote,
+			Merge:  ref,
+		})
+		if err != nil && err != git.ErrBranchExists {
+			return fmt.Errorf("failed to set upstream for '%s': %w", branch, err)
+		}
+	}
+
+	return nil
+}
+
+// pushRejection reports whether a push error is a rejection by the remote
+// rather than a transport failure, and returns the reason.
+func pushRejection(err error) (string, bool) {
+	msg := err.Error()
+
+	switch {
+	case strings.Contains(msg, "non-fast-forward"):
+		return "non-fast-forward: the remote branch has commits that are not present locally; pull first", true
+	case strings.HasPrefix(msg, "command error on "):
+		// Rejected by the remote, e.g. by a hook or branch protection
+		if _, reason, ok := strings.Cut(msg, ": "); ok {
+			return reason, true
+		}
+		return msg, true
+	}
+
+	return "", false
+}
+
+// isHTTPURL reports whether a remote URL uses HTTP or HTTPS.
+func isHTTPURL(url string) bool {
+	return strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://")
+}
@@ -0,0 +1,183 @@
+package git
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// createPushRepos creates a bare repository acting as the remote and a clone of it
+// with one commit already pushed. Returns the bare repository, the clone directory,
+// and the clone's branch name.
+func createPushRepos(t *testing.T) (*git.Repository, string, string) {
+	t.Helper()
+
+	remoteDir := t.TempDir()
+	remote, err := git.PlainInit(remoteDir, true)
+	if err != nil {
+		t.Fatalf("failed to init bare repo: %v", err)
+	}
+
+	localDir := t.TempDir()
+	local, err := git.PlainInit(localDir, false)
+	if err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+	if _, err := local.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{remoteDir}}); err != nil {
+		t.Fatalf("failed to add remote: %v", err)
+	}
+
+	commitFile(t, local, localDir, "README.md", "# Readme\n", "Initial", time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	if err := local.Push(&git.PushOptions{RemoteName: "origin"}); err != nil {
+		t.Fatalf("failed to push initial commit: %v", err)
+	}
+
+	head, err := local.Head()
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v", err)
+	}
+
+	return remote, localDir, head.Name().Short()
+}
+
+func TestPush(t *testing.T) {
+	remote, localDir, branch := createPushRepos(t)
+
+	local, err := git.PlainOpen(localDir)
+	if err != nil {
+		t.Fatalf("failed to open repo: %v", err)
+	}
+	tip := commitFile(t, local, localDir, "page.md", "page\n", "Add page", time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC))
+
+	provider, err := NewLocalProvider(localDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	if err := provider.Push("", ""); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+
+	ref, err := remote.Reference(plumbing.NewBranchReferenceName(branch), false)
+	if err != nil {
+		t.Fatalf("expected %s on remote: %v", branch, err)
+	}
+	if ref.Hash().String() != tip {
+		t.Errorf("expected remote %s at %s, got %s", branch, tip, ref.Hash())
+	}
+
+	// The pushed branch now tracks the remote
+	cfg, err := local.Config()
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+	if upstream := cfg.Branches[branch]; upstream == nil || upstream.Remote != "origin" {
+		t.Errorf("expected %s to track origin, got %+v", branch, upstream)
+	}
+
+	// Pushing again is a no-op
+	if err := provider.Push("origin", branch); err != nil {
+		t.Errorf("expected up-to-date push to succeed, got %v", err)
+	}
+}
+
+func TestPush_NewBranch(t *testing.T) {
+	remote, localDir, _ := createPushRepos(t)
+
+	provider, err := NewLocalProvider(localDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	if err := provider.CreateBranch("topic", ""); err != nil {
+		t.Fatalf("CreateBranch failed: %v", err)
+	}
+	if err := provider.Push("", "topic"); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+
+	if _, err := remote.Reference(plumbing.NewBranchReferenceName("topic"), false); err != nil {
+		t.Errorf("expected topic on remote: %v", err)
+	}
+}
+
+func TestPush_RejectedNonFastForward(t *testing.T) {
+	_, localDir, branch := createPushRepos(t)
+
+	provider, err := NewLocalProvider(localDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	remoteDir, err := provider.RemoteURL("origin")
+	if err != nil {
+		t.Fatalf("RemoteURL failed: %v", err)
+	}
+
+	// Someone else pushes first
+	otherDir := t.TempDir()
+	if err := CloneRemote(remoteDir, otherDir); err != nil {
+		t.Fatalf("clone failed: %v", err)
+	}
+	other, err := git.PlainOpen(otherDir)
+	if err != nil {
+		t.Fatalf("failed to open clone: %v", err)
+	}
+	commitFile(t, other, otherDir, "theirs.md", "theirs\n", "Their change", time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC))
+	if err := other.Push(&git.PushOptions{RemoteName: "origin"}); err != nil {
+		t.Fatalf("failed to push from other clone: %v", err)
+	}
+
+	local, err := git.PlainOpen(localDir)
+	if err != nil {
+		t.Fatalf("failed to open repo: %v", err)
+	}
+	commitFile(t, local, localDir, "ours.md", "ours\n", "Our change", time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC))
+
+	err = provider.Push("", "")
+	var rejected *PushRejectedError
+	if !errors.As(err, &rejected) {
+		t.Fatalf("expected PushRejectedError, got %v", err)
+	}
+	if rejected.Remote != "origin" || rejected.Branch != branch {
+		t.Errorf("unexpected rejection details: %+v", rejected)
+	}
+	if !strings.Contains(rejected.Reason, "non-fast-forward") {
+		t.Errorf("expected non-fast-forward reason, got %q", rejected.Reason)
+	}
+}
+
+func TestPush_Errors(t *testing.T) {
+	_, localDir, _ := createPushRepos(t)
+
+	provider, err := NewLocalProvider(localDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		remote      string
+		branch      string
+		errContains string
+	}{
+		{"unknown remote", "upstream", "", "remote 'upstream' not found"},
+		{"unknown branch", "", "nonexistent", "branch 'nonexistent' not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := provider.Push(tt.remote, tt.branch)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("expected error containing %q, got %v", tt.errContains, err)
+			}
+		})
+	}
+}
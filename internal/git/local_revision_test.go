@@ -0,0 +1,168 @@
+package git
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// createTaggedRepo creates a repository with three versions of page.md,
+// a lightweight tag "v1.0" on the first commit and an annotated tag "v2.0" on the second.
+func createTaggedRepo(t *testing.T) (string, []string) {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	repo, err := git.PlainInit(tempDir, false)
+	if err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	hashes := []string{
+		commitFile(t, repo, tempDir, "page.md", "version 1\n", "v1", base),
+		commitFile(t, repo, tempDir, "page.md", "version 2\n", "v2", base.Add(time.Hour)),
+		commitFile(t, repo, tempDir, "extra.md", "extra\n", "v3", base.Add(2*time.Hour)),
+	}
+
+	if _, err := repo.CreateTag("v1.0", plumbing.NewHash(hashes[0]), nil); err != nil {
+		t.Fatalf("failed to create lightweight tag: %v", err)
+	}
+	if _, err := repo.CreateTag("v2.0", plumbing.NewHash(hashes[1]), &git.CreateTagOptions{
+		Tagger:  &object.Signature{Name: "Tagger", Email: "tagger@example.com", When: base.Add(5 * time.Hour)},
+		Message: "Release 2.0",
+	}); err != nil {
+		t.Fatalf("failed to create annotated tag: %v", err)
+	}
+
+	return tempDir, hashes
+}
+
+func TestFileContent_Revisions(t *testing.T) {
+	tempDir, hashes := createTaggedRepo(t)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		rev      string
+		expected string
+	}{
+		{"lightweight tag", "v1.0", "version 1\n"},
+		{"annotated tag", "v2.0", "version 2\n"},
+		{"full hash", hashes[0], "version 1\n"},
+		{"abbreviated hash", hashes[1][:7], "version 2\n"},
+		{"relative revision", "HEAD~2", "version 1\n"},
+		{"parent revision", "HEAD^", "version 2\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := provider.FileContent("page.md", tt.rev)
+			if err != nil {
+				t.Fatalf("FileContent(%q) failed: %v", tt.rev, err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("FileContent(%q) = %q, want %q", tt.rev, content, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTree_Revisions(t *testing.T) {
+	tempDir, _ := createTaggedRepo(t)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	// extra.md only exists from the third commit on
+	tree, err := provider.Tree("v2.0")
+	if err != nil {
+		t.Fatalf("Tree(v2.0) failed: %v", err)
+	}
+	if len(tree.Children) != 1 || tree.Children[0].Name != "page.md" {
+		t.Errorf("expected only page.md at v2.0, got %+v", tree.Children)
+	}
+
+	tree, err = provider.Tree("HEAD~0")
+	if err != nil {
+		t.Fatalf("Tree(HEAD~0) failed: %v", err)
+	}
+	if len(tree.Children) != 2 {
+		t.Errorf("expected 2 files at HEAD~0, got %+v", tree.Children)
+	}
+
+	if _, err := provider.Tree("v9.9"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not found error for unknown tag, got %v", err)
+	}
+}
+
+func TestTags(t *testing.T) {
+	tempDir, hashes := createTaggedRepo(t)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	tags, err := provider.Tags()
+	if err != nil {
+		t.Fatalf("Tags failed: %v", err)
+	}
+
+	if len(tags) != 2 {
+		t.Fatalf("expected 2 tags, got %d", len(tags))
+	}
+
+	// Annotated v2.0 has the newer tagger date, so it comes first
+	v2, v1 := tags[0], tags[1]
+	if v2.Name != "v2.0" || v1.Name != "v1.0" {
+		t.Fatalf("expected [v2.0 v1.0], got [%s %s]", v2.Name, v1.Name)
+	}
+
+	if v2.Hash != hashes[1] {
+		t.Errorf("expected v2.0 to target %s, got %s", hashes[1], v2.Hash)
+	}
+	if !v2.Annotated || v2.Message != "Release 2.0" {
+		t.Errorf("expected annotated v2.0 with message, got %+v", v2)
+	}
+	if !v2.Date.Equal(time.Date(2024, 1, 1, 17, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected v2.0 tagger date, got %v", v2.Date)
+	}
+
+	if v1.Hash != hashes[0] {
+		t.Errorf("expected v1.0 to target %s, got %s", hashes[0], v1.Hash)
+	}
+	if v1.Annotated || v1.Message != "" {
+		t.Errorf("expected lightweight v1.0, got %+v", v1)
+	}
+	if !v1.Date.Equal(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected v1.0 commit date, got %v", v1.Date)
+	}
+}
+
+func TestTags_NoTags(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	tags, err := provider.Tags()
+	if err != nil {
+		t.Fatalf("Tags failed: %v", err)
+	}
+	if tags == nil || len(tags) != 0 {
+		t.Errorf("expected empty non-nil tag list, got %v", tags)
+	}
+}
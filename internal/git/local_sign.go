@@ -0,0 +1,424 @@
+package git
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	pgperrors "github.com/ProtonMail/go-crypto/openpgp/errors"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"golang.org/x/crypto/ssh"
+)
+
+// Signing key formats.
+const (
+	SigningFormatOpenPGP = "openpgp" // armored or binary OpenPGP private key
+	SigningFormatSSH     = "ssh"     // OpenSSH private key, signed as git's gpg.format=ssh does
+)
+
+// Signature statuses reported for commits.
+const (
+	SignatureNone    = "none"    // the commit is not signed
+	SignatureGood    = "good"    // signed with the configured signing key and verified
+	SignatureBad     = "bad"     // claims the configured signing key but does not verify
+	SignatureUnknown = "unknown" // signed with a key giki cannot check
+)
+
+// Armor headers identifying the signature format.
+const (
+	pgpSignatureHeader = "-----BEGIN PGP SIGNATURE-----"
+	sshSignatureHeader = "-----BEGIN SSH SIGNATURE-----"
+	sshSignatureFooter = "-----END SSH SIGNATURE-----"
+)
+
+// SSH signature (SSHSIG) parameters, matching what git and ssh-keygen produce.
+const (
+	sshSigMagic     = "SSHSIG"
+	sshSigVersion   = 1
+	sshSigNamespace = "git"
+	sshSigHash      = "sha512"
+)
+
+// SignatureInfo describes the signature on a commit.
+type SignatureInfo struct {
+	Status string `json:"status"`           // one of the Signature* statuses
+	Format string `json:"format,omitempty"` // openpgp or ssh; empty when unsigned
+	KeyID  string `json:"keyId,omitempty"`  // OpenPGP key ID or SSH key fingerprint
+}
+
+// commitSigner signs commits and verifies signatures made with the same key.
+type commitSigner interface {
+	// Sign returns the armored signature of message.
+	Sign(message io.Reader) ([]byte, error)
+	// format returns the signing key format.
+	format() string
+	// verify checks an armored signature of message. It returns SignatureUnknown
+	// when the signature was made with a different key.
+	verify(message io.Reader, signature string) string
+}
+
+// SetSigningKey loads the private key used to sign commits.
+// Format is SigningFormatOpenPGP or SigningFormatSSH; empty detects it from the key file.
+// The passphrase is only needed for encrypted keys.
+func (p *LocalProvider) SetSigningKey(path, format, passphrase string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read signing key: %w", err)
+	}
+
+	if format == "" {
+		format = detectKeyFormat(data)
+	}
+
+	var signer commitSigner
+	switch format {
+	case SigningFormatOpenPGP:
+		signer, err = newPGPSigner(data, passphrase)
+	case SigningFormatSSH:
+		signer, err = newSSHSigner(data, passphrase)
+	default:
+		return fmt.Errorf("invalid signing format: %s", format)
+	}
+	if err != nil {
+		return err
+	}
+
+	p.signer = signer
+	return nil
+}
+
+// CommitSignature returns the signature status of a commit.
+func (p *LocalProvider) CommitSignature(hash string) (SignatureInfo, error) {
+	commit, err := p.resolveCommit(hash)
+	if err != nil {
+		return SignatureInfo{}, err
+	}
+
+	return p.signatureInfo(commit), nil
+}
+
+// signatureInfo checks a commit's signature against the configured signing key.
+func (p *LocalProvider) signatureInfo(c *object.Commit) SignatureInfo {
+	signature := c.PGPSignature
+	if signature == "" {
+		return SignatureInfo{Status: SignatureNone}
+	}
+
+	var info SignatureInfo
+	switch {
+	case strings.HasPrefix(signature, sshSignatureHeader):
+		info.Format = SigningFormatSSH
+		if sig, err := parseSSHSignature(signature); err == nil {
+			if pub, err := ssh.ParsePublicKey(sig.PublicKey); err == nil {
+				info.KeyID = ssh.FingerprintSHA256(pub)
+			}
+		}
+	case strings.HasPrefix(signature, pgpSignatureHeader):
+		info.Format = SigningFormatOpenPGP
+		info.KeyID = pgpIssuer(signature)
+	}
+
+	if p.signer == nil || p.signer.format() != info.Format {
+		info.Status = SignatureUnknown
+		return info
+	}
+
+	// The signature covers the commit as it was encoded before signing
+	encoded := &plumbing.MemoryObject{}
+	if err := c.EncodeWithoutSignature(encoded); err != nil {
+		info.Status = SignatureBad
+		return info
+	}
+	message, err := encoded.Reader()
+	if err != nil {
+		info.Status = SignatureBad
+		return info
+	}
+	defer message.Close()
+
+	info.Status = p.signer.verify(message, signature)
+	return info
+}
+
+// detectKeyFormat guesses the format of a private key file, defaulting to OpenPGP.
+func detectKeyFormat(data []byte) string {
+	text := strings.TrimSpace(string(data))
+	if strings.HasPrefix(text, "-----BEGIN") && !strings.HasPrefix(text, "-----BEGIN PGP") {
+		return SigningFormatSSH
+	}
+	return SigningFormatOpenPGP
+}
+
+// pgpSigner signs with an OpenPGP key.
+type pgpSigner struct {
+	entity *openpgp.Entity
+}
+
+// newPGPSigner reads an armored or binary OpenPGP private key.
+func newPGPSigner(data []byte, passphrase string) (*pgpSigner, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+	if err != nil {
+		entities, err = openpgp.ReadKeyRing(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing key: %w", err)
+	}
+
+	for _, entity := range entities {
+		if entity.PrivateKey == nil {
+			continue
+		}
+
+		if err := entity.DecryptPrivateKeys([]byte(passphrase)); err != nil {
+			return nil, fmt.Errorf("failed to decrypt signing key: %w", err)
+		}
+		return &pgpSigner{entity: entity}, nil
+	}
+
+	return nil, fmt.Errorf("invalid signing key: no private key found")
+}
+
+// Sign returns an armored detached signature of message.
+func (s *pgpSigner) Sign(message io.Reader) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&buf, s.entity, message, nil); err != nil {
+		return nil, fmt.Errorf("failed to sign commit: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *pgpSigner) format() string {
+	return SigningFormatOpenPGP
+}
+
+func (s *pgpSigner) verify(message io.Reader, signature string) string {
+	_, err := openpgp.CheckArmoredDetachedSignature(openpgp.EntityList{s.entity}, message, strings.NewReader(signature), nil)
+	switch {
+	case err == nil:
+		return SignatureGood
+	case errors.Is(err, pgperrors.ErrUnknownIssuer):
+		return SignatureUnknown
+	default:
+		return SignatureBad
+	}
+}
+
+// pgpIssuer returns the ID of the key that made an armored OpenPGP signature,
+// or "" if it cannot be read.
+func pgpIssuer(signature string) string {
+	block, err := armor.Decode(strings.NewReader(signature))
+	if err != nil {
+		return ""
+	}
+
+	pkt, err := packet.Read(block.Body)
+	if err != nil {
+		return ""
+	}
+
+	sig, ok := pkt.(*packet.Signature)
+	if !ok || sig.IssuerKeyId == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%016X", *sig.IssuerKeyId)
+}
+
+// sshSigner signs with an SSH key in the SSHSIG format used by git.
+type sshSigner struct {
+	signer ssh.Signer
+}
+
+// sshSignature is the SSHSIG signature blob, after the magic preamble.
+type sshSignature struct {
+	Version       uint32
+	PublicKey     []byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     []byte
+}
+
+// sshSignedData is what an SSHSIG signature actually signs, after the magic preamble.
+type sshSignedData struct {
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Hash          []byte
+}
+
+// newSSHSigner reads an OpenSSH (or PEM) private key.
+func newSSHSigner(data []byte, passphrase string) (*sshSigner, error) {
+	var signer ssh.Signer
+	var err error
+	if passphrase != "" {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(data, []byte(passphrase))
+	} else {
+		signer, err = ssh.ParsePrivateKey(data)
+	}
+	if err != nil {
+		var missing *ssh.PassphraseMissingError
+		if errors.As(err, &missing) {
+			return nil, fmt.Errorf("failed to decrypt signing key: passphrase required")
+		}
+		return nil, fmt.Errorf("invalid signing key: %w", err)
+	}
+
+	return &sshSigner{signer: signer}, nil
+}
+
+// Sign returns an armored SSHSIG signature of message.
+func (s *sshSigner) Sign(message io.Reader) ([]byte, error) {
+	digest, err := sshSigDigest(sshSigHash, message)
+	if err != nil {
+		return nil, err
+	}
+
+	signed := sshSigPayload(sshSignedData{
+		Namespace:     sshSigNamespace,
+		HashAlgorithm: sshSigHash,
+		Hash:          digest,
+	})
+
+	var sig *ssh.Signature
+	if algSigner, ok := s.signer.(ssh.AlgorithmSigner); ok && s.signer.PublicKey().Type() == ssh.KeyAlgoRSA {
+		// SSHSIG forbids SHA-1 RSA signatures
+		sig, err = algSigner.SignWithAlgorithm(rand.Reader, signed, ssh.KeyAlgoRSASHA512)
+	} else {
+		sig, err = s.signer.Sign(rand.Reader, signed)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign commit: %w", err)
+	}
+
+	blob := sshSigPayload(sshSignature{
+		Version:       sshSigVersion,
+		PublicKey:     s.signer.PublicKey().Marshal(),
+		Namespace:     sshSigNamespace,
+		HashAlgorithm: sshSigHash,
+		Signature:     ssh.Marshal(sig),
+	})
+
+	return armorSSHSignature(blob), nil
+}
+
+func (s *sshSigner) format() string {
+	return SigningFormatSSH
+}
+
+func (s *sshSigner) verify(message io.Reader, signature string) string {
+	sig, err := parseSSHSignature(signature)
+	if err != nil {
+		return SignatureBad
+	}
+
+	if !bytes.Equal(sig.PublicKey, s.signer.PublicKey().Marshal()) {
+		return SignatureUnknown
+	}
+
+	if sig.Namespace != sshSigNamespace {
+		return SignatureBad
+	}
+
+	digest, err := sshSigDigest(sig.HashAlgorithm, message)
+	if err != nil {
+		return SignatureBad
+	}
+
+	var inner ssh.Signature
+	if err := ssh.Unmarshal(sig.Signature, &inner); err != nil {
+		return SignatureBad
+	}
+
+	signed := sshSigPayload(sshSignedData{
+		Namespace:     sig.Namespace,
+		Reserved:      sig.Reserved,
+		HashAlgorithm: sig.HashAlgorithm,
+		Hash:          digest,
+	})
+	if err := s.signer.PublicKey().Verify(signed, &inner); err != nil {
+		return SignatureBad
+	}
+
+	return SignatureGood
+}
+
+// sshSigDigest hashes message with one of the hash algorithms SSHSIG allows.
+func sshSigDigest(algorithm string, message io.Reader) ([]byte, error) {
+	var h hash.Hash
+	switch algorithm {
+	case "sha512":
+		h = sha512.New()
+	case "sha256":
+		h = sha256.New()
+	default:
+		return nil, fmt.Errorf("unsupported signature hash: %s", algorithm)
+	}
+
+	if _, err := io.Copy(h, message); err != nil {
+		return nil, fmt.Errorf("failed to hash commit: %w", err)
+	}
+	return h.Sum(nil), nil
+}
+
+// sshSigPayload encodes an SSHSIG structure, prefixed with the magic preamble.
+func sshSigPayload(v any) []byte {
+	return append([]byte(sshSigMagic), ssh.Marshal(v)...)
+}
+
+// armorSSHSignature wraps a signature blob the way ssh-keygen -Y sign does.
+func armorSSHSignature(blob []byte) []byte {
+	encoded := base64.StdEncoding.EncodeToString(blob)
+
+	var buf bytes.Buffer
+	buf.WriteString(sshSignatureHeader + "\n")
+	for len(encoded) > 70 {
+		buf.WriteString(encoded[:70] + "\n")
+		encoded = encoded[70:]
+	}
+	buf.WriteString(encoded + "\n")
+	buf.WriteString(sshSignatureFooter + "\n")
+	return buf.Bytes()
+}
+
+// parseSSHSignature decodes an armored SSHSIG signature.
+func parseSSHSignature(signature string) (*sshSignature, error) {
+	body := strings.TrimSpace(signature)
+	body = strings.TrimPrefix(body, sshSignatureHeader)
+	body, found := strings.CutSuffix(body, sshSignatureFooter)
+	if !found {
+		return nil, fmt.Errorf("invalid ssh signature: missing armor")
+	}
+
+	blob, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(body), ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ssh signature: %w", err)
+	}
+
+	rest, found := bytes.CutPrefix(blob, []byte(sshSigMagic))
+	if !found {
+		return nil, fmt.Errorf("invalid ssh signature: bad magic")
+	}
+
+	var sig sshSignature
+	if err := ssh.Unmarshal(rest, &sig); err != nil {
+		return nil, fmt.Errorf("invalid ssh signature: %w", err)
+	}
+	if sig.Version != sshSigVersion {
+		return nil, fmt.Errorf("invalid ssh signature: unsupported version %d", sig.Version)
+	}
+
+	return &sig, nil
+}
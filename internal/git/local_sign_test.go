@@ -0,0 +1,289 @@
+package git
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"golang.org/x/crypto/ssh"
+)
+
+// writePGPKey generates a throwaway OpenPGP key, writes the armored private key
+// to a file, and returns the file path and the armored public key.
+func writePGPKey(t *testing.T) (string, string) {
+	t.Helper()
+
+	entity, err := openpgp.NewEntity("Test Signer", "", "signer@example.com", &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA})
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	var private bytes.Buffer
+	w, err := armor.Encode(&private, openpgp.PrivateKeyType, nil)
+	if err != nil {
+		t.Fatalf("failed to armor key: %v", err)
+	}
+	if err := entity.SerializePrivate(w, nil); err != nil {
+		t.Fatalf("failed to serialize key: %v", err)
+	}
+	w.Close()
+
+	var public bytes.Buffer
+	w, err = armor.Encode(&public, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatalf("failed to armor key: %v", err)
+	}
+	if err := entity.Serialize(w); err != nil {
+		t.Fatalf("failed to serialize key: %v", err)
+	}
+	w.Close()
+
+	path := filepath.Join(t.TempDir(), "signing.asc")
+	if err := os.WriteFile(path, private.Bytes(), 0600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+
+	return path, public.String()
+}
+
+// writeSSHKey generates a throwaway ed25519 SSH key, encrypted if passphrase is
+// non-empty, and returns the private key file path and the public key.
+func writeSSHKey(t *testing.T, passphrase string) (string, ssh.PublicKey) {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	var block *pem.Block
+	if passphrase != "" {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(priv, "", []byte(passphrase))
+	} else {
+		block, err = ssh.MarshalPrivateKey(priv, "")
+	}
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatalf("failed to convert public key: %v", err)
+	}
+
+	return path, sshPub
+}
+
+// commitSigned writes a file and commits it, returning the new commit.
+func commitSigned(t *testing.T, provider *LocalProvider, path string) *object.Commit {
+	t.Helper()
+
+	if err := provider.WriteFile(path, []byte(path)); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	hash, err := provider.Commit("Add " + path)
+	if err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	commit, err := provider.repo.CommitObject(plumbing.NewHash(hash))
+	if err != nil {
+		t.Fatalf("failed to load commit: %v", err)
+	}
+	return commit
+}
+
+func TestCommitSigning_OpenPGP(t *testing.T) {
+	isolateGitConfig(t, "")
+
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	keyPath, publicKey := writePGPKey(t)
+	if err := provider.SetSigningKey(keyPath, "", ""); err != nil {
+		t.Fatalf("SetSigningKey failed: %v", err)
+	}
+
+	commit := commitSigned(t, provider, "page.md")
+
+	// The signature is a standard detached OpenPGP signature
+	if _, err := commit.Verify(publicKey); err != nil {
+		t.Fatalf("signature does not verify: %v", err)
+	}
+
+	info, err := provider.CommitSignature(commit.Hash.String())
+	if err != nil {
+		t.Fatalf("CommitSignature failed: %v", err)
+	}
+	if info.Status != SignatureGood || info.Format != SigningFormatOpenPGP || info.KeyID == "" {
+		t.Errorf("expected good openpgp signature with key ID, got %+v", info)
+	}
+
+	// The signature status is part of the history
+	page, err := provider.Log("", "", 0, "")
+	if err != nil {
+		t.Fatalf("Log failed: %v", err)
+	}
+	if page.Commits[0].Signature != info {
+		t.Errorf("expected log to report %+v, got %+v", info, page.Commits[0].Signature)
+	}
+	if page.Commits[1].Signature.Status != SignatureNone {
+		t.Errorf("expected earlier commit to be unsigned, got %+v", page.Commits[1].Signature)
+	}
+}
+
+func TestCommitSigning_SSH(t *testing.T) {
+	isolateGitConfig(t, "")
+
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	keyPath, publicKey := writeSSHKey(t, "secret")
+	if err := provider.SetSigningKey(keyPath, SigningFormatSSH, "secret"); err != nil {
+		t.Fatalf("SetSigningKey failed: %v", err)
+	}
+
+	commit := commitSigned(t, provider, "page.md")
+	if !strings.HasPrefix(commit.PGPSignature, "-----BEGIN SSH SIGNATURE-----") {
+		t.Fatalf("expected an SSH signature, got %q", commit.PGPSignature)
+	}
+
+	info, err := provider.CommitSignature(commit.Hash.String())
+	if err != nil {
+		t.Fatalf("CommitSignature failed: %v", err)
+	}
+	expected := SignatureInfo{Status: SignatureGood, Format: SigningFormatSSH, KeyID: ssh.FingerprintSHA256(publicKey)}
+	if info != expected {
+		t.Errorf("expected %+v, got %+v", expected, info)
+	}
+
+	// Amended commits are signed too
+	amended, err := provider.CommitWithOptions(CommitOptions{Message: "Reworded", Amend: true})
+	if err != nil {
+		t.Fatalf("amend failed: %v", err)
+	}
+	info, err = provider.CommitSignature(amended)
+	if err != nil {
+		t.Fatalf("CommitSignature failed: %v", err)
+	}
+	if info.Status != SignatureGood {
+		t.Errorf("expected amended commit to be signed, got %+v", info)
+	}
+}
+
+func TestCommitSignature_OtherKeyAndTampering(t *testing.T) {
+	isolateGitConfig(t, "")
+
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	keyPath, _ := writeSSHKey(t, "")
+	if err := provider.SetSigningKey(keyPath, "", ""); err != nil {
+		t.Fatalf("SetSigningKey failed: %v", err)
+	}
+	commit := commitSigned(t, provider, "page.md")
+
+	// Store a copy of the commit with a different message but the same signature
+	forged := *commit
+	forged.Message = "Something else\n"
+	obj := provider.repo.Storer.NewEncodedObject()
+	if err := forged.Encode(obj); err != nil {
+		t.Fatalf("failed to encode commit: %v", err)
+	}
+	forgedHash, err := provider.repo.Storer.SetEncodedObject(obj)
+	if err != nil {
+		t.Fatalf("failed to store commit: %v", err)
+	}
+
+	info, err := provider.CommitSignature(forgedHash.String())
+	if err != nil {
+		t.Fatalf("CommitSignature failed: %v", err)
+	}
+	if info.Status != SignatureBad {
+		t.Errorf("expected tampered commit to have a bad signature, got %+v", info)
+	}
+
+	// With another key configured the original signature cannot be checked
+	otherKey, _ := writeSSHKey(t, "")
+	if err := provider.SetSigningKey(otherKey, "", ""); err != nil {
+		t.Fatalf("SetSigningKey failed: %v", err)
+	}
+	info, err = provider.CommitSignature(commit.Hash.String())
+	if err != nil {
+		t.Fatalf("CommitSignature failed: %v", err)
+	}
+	if info.Status != SignatureUnknown || info.KeyID == "" {
+		t.Errorf("expected unknown signature with key ID, got %+v", info)
+	}
+}
+
+func TestSetSigningKey_Errors(t *testing.T) {
+	encryptedKey, _ := writeSSHKey(t, "secret")
+	pgpKey, _ := writePGPKey(t)
+
+	garbage := filepath.Join(t.TempDir(), "garbage")
+	if err := os.WriteFile(garbage, []byte("not a key"), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		path        string
+		format      string
+		passphrase  string
+		errContains string
+	}{
+		{"missing file", filepath.Join(t.TempDir(), "missing"), "", "", "failed to read signing key"},
+		{"missing passphrase", encryptedKey, "", "", "passphrase required"},
+		{"wrong passphrase", encryptedKey, "", "wrong", "invalid signing key"},
+		{"wrong format", pgpKey, SigningFormatSSH, "", "invalid signing key"},
+		{"unknown format", pgpKey, "x509", "", "invalid signing format"},
+		{"not a key", garbage, "", "", "invalid signing key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &LocalProvider{}
+			err := provider.SetSigningKey(tt.path, tt.format, tt.passphrase)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("expected error containing %q, got %v", tt.errContains, err)
+			}
+			if provider.signer != nil {
+				t.Error("expected no signer to be set")
+			}
+		})
+	}
+}
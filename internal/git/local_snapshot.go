@@ -0,0 +1,131 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileStamp identifies a version of a working tree file for change detection.
+type FileStamp struct {
+	ModTime time.Time
+	Size    int64
+}
+
+// WorkingTreeSnapshot returns the modification stamp of every file in the working tree,
+// keyed by forward-slash path. Uses the same .gitignore rules as Tree, and skips
+// ignored directories entirely so large ignored folders are cheap to poll.
+func (p *LocalProvider) WorkingTreeSnapshot() (map[string]FileStamp, error) {
+	patterns, err := p.loadGitignorePatterns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load .gitignore: %w", err)
+	}
+
+	files := make(map[string]FileStamp)
+
+	err = filepath.Walk(p.path, func(absPath string, info os.FileInfo, err error) error {
+		if err != nil {
+			// Files can disappear between listing and stat while the tree is being edited
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		relPath, err := filepath.Rel(p.path, absPath)
+		if err != nil {
+			return err
+		}
+
+		// Skip root directory
+		if relPath == "." {
+			return nil
+		}
+
+		// Skip the .git directory itself
+		if relPath == ".git" || strings.HasPrefix(relPath, ".git"+string(filepath.Separator)) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if p.shouldIgnore(relPath, patterns, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		files[filepath.ToSlash(relPath)] = FileStamp{
+			ModTime: info.ModTime(),
+			Size:    info.Size(),
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk filesystem: %w", err)
+	}
+
+	return files, nil
+}
+
+// repoStateFiles are the files under .git whose changes can alter the
+// repository status without touching the working tree: HEAD moves on branch
+// switches, the index on staging and commits, packed-refs on ref packing.
+var repoStateFiles = []string{"HEAD", "index", "packed-refs"}
+
+// RepoStateSnapshot returns the modification stamps of the .git files that
+// commits, pulls, branch switches and stashes update, keyed by path relative
+// to .git. It reads only HEAD, the index and the refs directory, so it is
+// cheap enough to poll alongside WorkingTreeSnapshot.
+func (p *LocalProvider) RepoStateSnapshot() (map[string]FileStamp, error) {
+	gitDir := filepath.Join(p.path, ".git")
+	files := make(map[string]FileStamp)
+
+	for _, name := range repoStateFiles {
+		info, err := os.Stat(filepath.Join(gitDir, name))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to stat %s: %w", name, err)
+		}
+		files[name] = FileStamp{ModTime: info.ModTime(), Size: info.Size()}
+	}
+
+	refsDir := filepath.Join(gitDir, "refs")
+	err := filepath.Walk(refsDir, func(absPath string, info os.FileInfo, err error) error {
+		if err != nil {
+			// Refs are rewritten via lock files and renames while we walk
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(gitDir, absPath)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(relPath)] = FileStamp{
+			ModTime: info.ModTime(),
+			Size:    info.Size(),
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk refs: %w", err)
+	}
+
+	return files, nil
+}
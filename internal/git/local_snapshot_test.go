@@ -0,0 +1,93 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestWorkingTreeSnapshot(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	files := map[string]string{
+		".gitignore":       "build/\n*.log\n",
+		"docs/guide.md":    "# Guide\n",
+		"build/output.txt": "ignored",
+		"debug.log":        "ignored",
+		"notes/draft.md":   "draft",
+	}
+	for path, content := range files {
+		fullPath := filepath.Join(tempDir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("failed to create directories: %v", err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	snapshot, err := provider.WorkingTreeSnapshot()
+	if err != nil {
+		t.Fatalf("WorkingTreeSnapshot failed: %v", err)
+	}
+
+	for _, path := range []string{".gitignore", "docs/guide.md", "notes/draft.md"} {
+		if _, ok := snapshot[path]; !ok {
+			t.Errorf("expected %s in snapshot", path)
+		}
+	}
+	for _, path := range []string{"build/output.txt", "debug.log", ".git/HEAD"} {
+		if _, ok := snapshot[path]; ok {
+			t.Errorf("expected %s to be excluded from snapshot", path)
+		}
+	}
+
+	if stamp := snapshot["docs/guide.md"]; stamp.Size != int64(len("# Guide\n")) {
+		t.Errorf("expected size %d for docs/guide.md, got %d", len("# Guide\n"), stamp.Size)
+	}
+}
+
+func TestRepoStateSnapshot(t *testing.T) {
+	tempDir := t.TempDir()
+	repo := createTestRepoWithCommit(t, tempDir)
+
+	provider, err := NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	before, err := provider.RepoStateSnapshot()
+	if err != nil {
+		t.Fatalf("RepoStateSnapshot failed: %v", err)
+	}
+	for _, path := range []string{"HEAD", "index"} {
+		if _, ok := before[path]; !ok {
+			t.Errorf("expected %s in snapshot", path)
+		}
+	}
+
+	// Creating a branch adds a ref without touching the working tree
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v", err)
+	}
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("feature"), head.Hash())); err != nil {
+		t.Fatalf("failed to create branch: %v", err)
+	}
+
+	after, err := provider.RepoStateSnapshot()
+	if err != nil {
+		t.Fatalf("RepoStateSnapshot failed: %v", err)
+	}
+	if _, ok := after["refs/heads/feature"]; !ok {
+		t.Errorf("expected refs/heads/feature in snapshot, got %v", after)
+	}
+}
@@ -0,0 +1,186 @@
+package git
+
+import (
+	"slices"
+	"strings"
+)
+
+// Conflict markers written around conflicting regions in MergeResult.Merged,
+// in the style of git merge.
+const (
+	conflictOursMarker   = "<<<<<<< ours"
+	conflictBaseMarker   = "||||||| base"
+	conflictSplitMarker  = "======="
+	conflictTheirsMarker = ">>>>>>> theirs"
+)
+
+// MergeHunk is one region of a three-way merge: either text both sides agree
+// on after the merge, or a conflict where they changed the same lines differently.
+// A conflict carries the region as it is in each of the three versions.
+type MergeHunk struct {
+	Conflict bool   `json:"conflict"`
+	Text     string `json:"text,omitempty"` // merged text, when not a conflict
+	Base     string `json:"base,omitempty"`
+	Ours     string `json:"ours,omitempty"`
+	Theirs   string `json:"theirs,omitempty"`
+}
+
+// MergeResult is the outcome of a three-way text merge.
+type MergeResult struct {
+	Merged    string      `json:"merged"`    // merged text, with conflict markers around conflicts
+	Conflicts int         `json:"conflicts"` // number of conflicting hunks
+	Hunks     []MergeHunk `json:"hunks"`     // the merged text region by region
+}
+
+// MergeText merges two versions of a text that were both derived from base,
+// line by line in the manner of diff3. Lines changed on only one side take that
+// side's change; lines changed identically on both sides are merged once; and
+// lines changed differently on both sides become a conflict, marked in the
+// merged text with ours, base and theirs sections.
+func MergeText(base, ours, theirs string) MergeResult {
+	baseLines := splitLines(base)
+	oursLines := splitLines(ours)
+	theirsLines := splitLines(theirs)
+
+	// For each base line, the matching line in ours and theirs, or -1 if changed
+	toOurs := matchLines(base, ours, len(baseLines))
+	toTheirs := matchLines(base, theirs, len(baseLines))
+
+	var m merger
+	i, j, k := 0, 0, 0
+	for i < len(baseLines) || j < len(oursLines) || k < len(theirsLines) {
+		// Copy lines that are unchanged on both sides
+		for i < len(baseLines) && toOurs[i] == j && toTheirs[i] == k {
+			m.resolved(baseLines[i])
+			i, j, k = i+1, j+1, k+1
+		}
+
+		// Find the next base line that is unchanged on both sides
+		nextI, nextJ, nextK := len(baseLines), len(oursLines), len(theirsLines)
+		for n := i; n < len(baseLines); n++ {
+			if toOurs[n] >= 0 && toTheirs[n] >= 0 {
+				nextI, nextJ, nextK = n, toOurs[n], toTheirs[n]
+				break
+			}
+		}
+
+		m.chunk(baseLines[i:nextI], oursLines[j:nextJ], theirsLines[k:nextK])
+		i, j, k = nextI, nextJ, nextK
+	}
+
+	return m.result()
+}
+
+// splitLines splits text into lines, each keeping its trailing newline.
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// matchLines maps each of the n lines of oldText to the index of the same,
+// unchanged line in newText, or -1 if it was changed or deleted.
+func matchLines(oldText, newText string, n int) []int {
+	matches := make([]int, n)
+	i, j := 0, 0
+	for _, line := range diffLines(oldText, newText) {
+		switch line.op {
+		case ' ':
+			matches[i] = j
+			i, j = i+1, j+1
+		case '-':
+			matches[i] = -1
+			i++
+		case '+':
+			j++
+		}
+	}
+	return matches
+}
+
+// merger accumulates the hunks of a merge.
+type merger struct {
+	hunks []MergeHunk
+}
+
+// chunk merges a region in which base, ours and theirs differ in some way.
+func (m *merger) chunk(base, ours, theirs []string) {
+	switch {
+	case slices.Equal(ours, base):
+		m.resolved(theirs...)
+	case slices.Equal(theirs, base), slices.Equal(ours, theirs):
+		m.resolved(ours...)
+	default:
+		// Lines both sides agree on at the edges of the region are not part of the conflict
+		prefix := 0
+		for prefix < len(ours) && prefix < len(theirs) && ours[prefix] == theirs[prefix] {
+			prefix++
+		}
+		suffix := 0
+		for suffix < len(ours)-prefix && suffix < len(theirs)-prefix &&
+			ours[len(ours)-1-suffix] == theirs[len(theirs)-1-suffix] {
+			suffix++
+		}
+
+		m.resolved(ours[:prefix]...)
+		m.hunks = append(m.hunks, MergeHunk{
+			Conflict: true,
+			Base:     strings.Join(base, ""),
+			Ours:     strings.Join(ours[prefix:len(ours)-suffix], ""),
+			Theirs:   strings.Join(theirs[prefix:len(theirs)-suffix], ""),
+		})
+		m.resolved(ours[len(ours)-suffix:]...)
+	}
+}
+
+// resolved appends merged lines, extending the previous hunk if it is also resolved.
+func (m *merger) resolved(lines ...string) {
+	if len(lines) == 0 {
+		return
+	}
+
+	text := strings.Join(lines, "")
+	if n := len(m.hunks); n > 0 && !m.hunks[n-1].Conflict {
+		m.hunks[n-1].Text += text
+		return
+	}
+	m.hunks = append(m.hunks, MergeHunk{Text: text})
+}
+
+// result renders the merged text, marking each conflict.
+func (m *merger) result() MergeResult {
+	result := MergeResult{Hunks: m.hunks}
+	if result.Hunks == nil {
+		result.Hunks = []MergeHunk{}
+	}
+
+	var merged strings.Builder
+	for _, hunk := range m.hunks {
+		if !hunk.Conflict {
+			merged.WriteString(hunk.Text)
+			continue
+		}
+
+		result.Conflicts++
+		merged.WriteString(conflictOursMarker + "\n")
+		merged.WriteString(withNewline(hunk.Ours))
+		merged.WriteString(conflictBaseMarker + "\n")
+		merged.WriteString(withNewline(hunk.Base))
+		merged.WriteString(conflictSplitMarker + "\n")
+		merged.WriteString(withNewline(hunk.Theirs))
+		merged.WriteString(conflictTheirsMarker + "\n")
+	}
+	result.Merged = merged.String()
+
+	return result
+}
+
+// withNewline terminates non-empty text with a newline so a marker can follow it.
+func withNewline(text string) string {
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		return text + "\n"
+	}
+	return text
+}
@@ -0,0 +1,149 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMergeText_Clean(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		ours     string
+		theirs   string
+		expected string
+	}{
+		{
+			name:     "separate lines changed",
+			base:     "a\nb\nc\nd\ne\n",
+			ours:     "A\nb\nc\nd\ne\n",
+			theirs:   "a\nb\nc\nd\nE\n",
+			expected: "A\nb\nc\nd\nE\n",
+		},
+		{
+			name:     "same change on both sides",
+			base:     "a\nb\nc\n",
+			ours:     "a\nB\nc\n",
+			theirs:   "a\nB\nc\n",
+			expected: "a\nB\nc\n",
+		},
+		{
+			name:     "only theirs changed",
+			base:     "a\nb\n",
+			ours:     "a\nb\n",
+			theirs:   "a\nb\nc\n",
+			expected: "a\nb\nc\n",
+		},
+		{
+			name:     "insertion and deletion",
+			base:     "a\nb\nc\nd\n",
+			ours:     "a\nnew\nb\nc\nd\n",
+			theirs:   "a\nb\nc\n",
+			expected: "a\nnew\nb\nc\n",
+		},
+		{
+			name:     "empty base",
+			base:     "",
+			ours:     "",
+			theirs:   "text\n",
+			expected: "text\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := MergeText(tt.base, tt.ours, tt.theirs)
+			if result.Conflicts != 0 {
+				t.Fatalf("expected no conflicts, got %d: %+v", result.Conflicts, result.Hunks)
+			}
+			if result.Merged != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result.Merged)
+			}
+		})
+	}
+}
+
+func TestMergeText_Conflict(t *testing.T) {
+	base := "title\nline\nfooter\n"
+	ours := "title\nmine\nfooter\n"
+	theirs := "title\ntheirs\nfooter\n"
+
+	result := MergeText(base, ours, theirs)
+	if result.Conflicts != 1 {
+		t.Fatalf("expected 1 conflict, got %d", result.Conflicts)
+	}
+
+	expected := "title\n" +
+		"<<<<<<< ours\nmine\n" +
+		"||||||| base\nline\n" +
+		"=======\ntheirs\n" +
+		">>>>>>> theirs\n" +
+		"footer\n"
+	if result.Merged != expected {
+		t.Errorf("expected merged text %q, got %q", expected, result.Merged)
+	}
+
+	if len(result.Hunks) != 3 {
+		t.Fatalf("expected 3 hunks, got %+v", result.Hunks)
+	}
+	if result.Hunks[0].Text != "title\n" || result.Hunks[2].Text != "footer\n" {
+		t.Errorf("unexpected resolved hunks %+v", result.Hunks)
+	}
+	conflict := result.Hunks[1]
+	if !conflict.Conflict || conflict.Base != "line\n" || conflict.Ours != "mine\n" || conflict.Theirs != "theirs\n" {
+		t.Errorf("unexpected conflict hunk %+v", conflict)
+	}
+}
+
+func TestMergeText_ConflictTrimsCommonLines(t *testing.T) {
+	// Both sides replaced the same two lines but agree on the first replacement
+	base := "a\nb\nc\nd\n"
+	ours := "a\nX\nours\nd\n"
+	theirs := "a\nX\ntheirs\nd\n"
+
+	result := MergeText(base, ours, theirs)
+	if result.Conflicts != 1 {
+		t.Fatalf("expected 1 conflict, got %d", result.Conflicts)
+	}
+	if !strings.HasPrefix(result.Merged, "a\nX\n<<<<<<< ours\nours\n") {
+		t.Errorf("expected shared line before the conflict, got %q", result.Merged)
+	}
+
+	for _, hunk := range result.Hunks {
+		if hunk.Conflict && (hunk.Ours != "ours\n" || hunk.Theirs != "theirs\n" || hunk.Base != "b\nc\n") {
+			t.Errorf("unexpected conflict hunk %+v", hunk)
+		}
+	}
+}
+
+func TestMergeText_DeleteVersusEdit(t *testing.T) {
+	base := "a\nb\nc\n"
+	ours := "a\nc\n"
+	theirs := "a\nB\nc\n"
+
+	result := MergeText(base, ours, theirs)
+	if result.Conflicts != 1 {
+		t.Fatalf("expected 1 conflict, got %d", result.Conflicts)
+	}
+
+	expected := "a\n<<<<<<< ours\n||||||| base\nb\n=======\nB\n>>>>>>> theirs\nc\n"
+	if result.Merged != expected {
+		t.Errorf("expected %q, got %q", expected, result.Merged)
+	}
+}
+
+func TestMergeText_NoNewlineAtEnd(t *testing.T) {
+	result := MergeText("a\nb", "a\nours", "a\ntheirs")
+	if result.Conflicts != 1 {
+		t.Fatalf("expected 1 conflict, got %d", result.Conflicts)
+	}
+
+	// Markers always start on their own line
+	expected := "a\n<<<<<<< ours\nours\n||||||| base\nb\n=======\ntheirs\n>>>>>>> theirs\n"
+	if result.Merged != expected {
+		t.Errorf("expected %q, got %q", expected, result.Merged)
+	}
+	if result.Hunks[1].Ours != "ours" {
+		t.Errorf("expected hunk to keep the original text, got %q", result.Hunks[1].Ours)
+	}
+}
@@ -1,24 +1,49 @@
 package git
 
+import (
+	"context"
+	"io"
+	"time"
+)
+
 // GitProvider defines the interface for interacting with git repositories.
 // Implementations include LocalProvider (working tree + git objects) and
-// future remote providers (API-based browsing).
+// GitHubProvider (read-only browsing through the GitHub API).
 type GitProvider interface {
 	// Tree returns the complete file tree for the given branch.
 	// For the current branch, includes uncommitted changes from working tree.
 	// For other branches, reads from git object store (committed state only).
+	// The branch may also be a tag, commit hash, or revision such as "HEAD~2".
 	Tree(branch string) (*TreeNode, error)
 
 	// FileContent returns the raw bytes of a file at the given path on the given branch.
+	// The branch may also be a tag, commit hash, or revision such as "HEAD~2".
 	// Returns an error if the file does not exist.
 	FileContent(path, branch string) ([]byte, error)
 
-	// Branches returns a list of all branches in the repository.
+	// Branches returns a list of all branches in the repository,
+	// including remote-tracking branches.
 	Branches() ([]BranchInfo, error)
 
+	// CreateBranch creates a new branch named name starting at from.
+	// The from revision may be a branch, tag, or commit; empty means HEAD.
+	CreateBranch(name, from string) error
+
+	// DeleteBranch deletes a branch. The current branch cannot be deleted,
+	// and a branch with unmerged commits is only deleted when force is true.
+	DeleteBranch(name string, force bool) error
+
+	// Tags returns all tags in the repository, newest first.
+	Tags() ([]TagInfo, error)
+
 	// Status returns the current repository status (branch, dirty state, etc.).
 	Status() (*RepoStatus, error)
 
+	// Checkout switches the working tree to another branch and makes it the current branch.
+	// Uncommitted changes are handled according to mode: CheckoutClean refuses to switch,
+	// CheckoutStash sets them aside for the old branch, and CheckoutCarry brings them along.
+	Checkout(branch, mode string) error
+
 	// WriteFile writes content to a file at the given path.
 	// Creates parent directories if they don't exist.
 	WriteFile(path string, content []byte) error
@@ -33,10 +58,58 @@ type GitProvider interface {
 	// This operation moves all files within the folder recursively.
 	MoveFolder(oldPath, newPath string) error
 
+	// CreateFolder creates a folder containing a .gitkeep file, so that the
+	// folder survives a commit. Creates parent directories if they don't exist.
+	CreateFolder(path string) error
+
+	// DeleteFolder removes a folder. Unless recursive is true, the folder must be
+	// empty apart from a .gitkeep file.
+	DeleteFolder(path string, recursive bool) error
+
+	// WriteFileIfUnchanged, DeleteFileIfUnchanged and MoveFileIfUnchanged are
+	// WriteFile, DeleteFile and MoveFile for an edit based on the version of the
+	// file identified by baseHash, its ETag. They return a *ConflictError instead
+	// if the file has changed since; an empty baseHash always matches. The check
+	// and the change are atomic with respect to other conditional edits.
+	WriteFileIfUnchanged(path string, content []byte, baseHash string) error
+	DeleteFileIfUnchanged(path, baseHash string) error
+	MoveFileIfUnchanged(oldPath, newPath, baseHash string) error
+
+	// ApplyChangeSet applies write, delete, move and move-folder operations as a unit:
+	// all are validated first, and any failure rolls back the ones already applied.
+	// If message is non-empty the affected paths are committed; returns the commit hash.
+	ApplyChangeSet(ops []ChangeOp, message string) (string, error)
+
 	// Commit creates a git commit with all staged and unstaged changes.
 	// Returns the commit hash on success.
 	Commit(message string) (string, error)
 
+	// CommitWithOptions creates a git commit like Commit, with an optional
+	// author override and Co-authored-by trailers. It can commit only selected
+	// paths, leaving other changes uncommitted, and can amend the HEAD commit.
+	// Returns the commit hash on success.
+	CommitWithOptions(opts CommitOptions) (string, error)
+
+	// CommitSignature reports whether a commit is signed and whether the
+	// signature verifies against the configured signing key.
+	CommitSignature(hash string) (SignatureInfo, error)
+
+	// Push pushes a branch to a remote. Empty branch means the current branch;
+	// empty remote means the branch's upstream remote, or "origin".
+	// Returns a *PushRejectedError if the remote refuses the update.
+	Push(remote, branch string) error
+
+	// Fetch downloads new commits and branches from a remote without changing the working tree.
+	// Empty remote means the current branch's upstream remote, or "origin".
+	// Progress messages are written to progress if non-nil.
+	// Cancelling ctx stops the transfer.
+	Fetch(ctx context.Context, remote string, progress io.Writer) error
+
+	// Pull fetches from a remote and fast-forwards the current branch.
+	// Returns a *PullBlockedError if the working tree is dirty or the branch has diverged.
+	// Cancelling ctx stops the fetch; the branch is only moved once it completes.
+	Pull(ctx context.Context, remote string, progress io.Writer) error
+
 	// SearchFileNames performs fuzzy filename matching against all files in the repository.
 	// Returns paths matching the query, sorted by relevance.
 	SearchFileNames(query string) ([]string, error)
@@ -44,6 +117,25 @@ type GitProvider interface {
 	// SearchContent performs full-text search across all files in the repository.
 	// Returns matches with line numbers and surrounding context.
 	SearchContent(query string) ([]SearchResult, error)
+
+	// Log returns the commit history for the given branch, newest first.
+	// If path is non-empty, only commits touching that file or directory are included.
+	// Results are paginated: pass the NextCursor of a previous page as cursor
+	// to continue where it left off.
+	Log(path, branch string, limit int, cursor string) (*LogPage, error)
+
+	// Blame returns line-level authorship for a file on the given branch,
+	// grouped into ranges of consecutive lines last changed by the same commit.
+	// Only committed content is blamed; uncommitted edits are not reflected.
+	Blame(path, branch string) (*FileBlame, error)
+
+	// Diff returns the uncommitted changes in the working tree compared to HEAD,
+	// one entry per changed file, sorted by path.
+	Diff() ([]FileDiff, error)
+
+	// Compare returns the changes introduced on head since it diverged from base.
+	// Both base and head may be branch names, tags, or commit hashes.
+	Compare(base, head string) (*Comparison, error)
 }
 
 // TreeNode represents a file or directory in the repository tree.
@@ -57,7 +149,19 @@ type TreeNode struct {
 // BranchInfo represents a single git branch.
 type BranchInfo struct {
 	Name      string `json:"name"`
-	IsDefault bool   `json:"isDefault"` // true for HEAD branch
+	IsDefault bool   `json:"isDefault"`        // true for the current branch (HEAD, or the branch being browsed)
+	Remote    bool   `json:"remote,omitempty"` // true for remote-tracking branches, e.g. "origin/main"
+	Ahead     int    `json:"ahead,omitempty"`  // remote only: local branch commits missing from the remote branch
+	Behind    int    `json:"behind,omitempty"` // remote only: remote branch commits missing from the local branch
+}
+
+// TagInfo represents a single git tag.
+type TagInfo struct {
+	Name      string    `json:"name"`              // tag name, e.g. "v1.2"
+	Hash      string    `json:"hash"`              // hash of the commit the tag points to
+	Date      time.Time `json:"date"`              // tagger date for annotated tags, commit date otherwise
+	Annotated bool      `json:"annotated"`         // true for annotated tag objects
+	Message   string    `json:"message,omitempty"` // annotation message, if any
 }
 
 // RepoStatus represents the current state of the repository.
@@ -74,3 +178,71 @@ type SearchResult struct {
 	Context    []string `json:"context"`    // surrounding lines (before, match, after)
 	MatchText  string   `json:"matchText"`  // the matched text for highlighting
 }
+
+// CommitInfo describes a single commit in the repository history.
+type CommitInfo struct {
+	Hash    string    `json:"hash"`    // full commit hash
+	Author  string    `json:"author"`  // author name
+	Email   string    `json:"email"`   // author email
+	Date    time.Time `json:"date"`    // author date
+	Message string    `json:"message"` // full commit message
+	Paths   []string  `json:"paths"`   // paths changed by the commit
+
+	Signature SignatureInfo `json:"signature"` // signature status
+}
+
+// LogPage is a single page of commit history.
+type LogPage struct {
+	Commits    []CommitInfo `json:"commits"`
+	NextCursor string       `json:"nextCursor,omitempty"` // empty when there are no more commits
+}
+
+// FileBlame holds line authorship for a single file.
+type FileBlame struct {
+	Path     string       `json:"path"`     // file path
+	Revision string       `json:"revision"` // commit hash the blame was computed at
+	Ranges   []BlameRange `json:"ranges"`   // consecutive line ranges, in file order
+}
+
+// BlameRange is a run of consecutive lines last changed by the same commit.
+type BlameRange struct {
+	StartLine int       `json:"startLine"` // 1-indexed, inclusive
+	EndLine   int       `json:"endLine"`   // 1-indexed, inclusive
+	Hash      string    `json:"hash"`      // commit that last changed these lines
+	Author    string    `json:"author"`    // author name
+	Email     string    `json:"email"`     // author email
+	Date      time.Time `json:"date"`      // author date
+	Summary   string    `json:"summary"`   // first line of the commit message
+}
+
+// FileDiff describes the changes to a single file.
+type FileDiff struct {
+	Path      string     `json:"path"`              // current path (old path for deletions)
+	OldPath   string     `json:"oldPath,omitempty"` // previous path, set for renames
+	Status    string     `json:"status"`            // added, modified, deleted, or renamed
+	Binary    bool       `json:"binary,omitempty"`  // true if either side is binary (no hunks)
+	Additions int        `json:"additions"`         // number of added lines
+	Deletions int        `json:"deletions"`         // number of deleted lines
+	Hunks     []DiffHunk `json:"hunks"`             // changed regions with context
+	Patch     string     `json:"patch"`             // unified diff text
+}
+
+// DiffHunk is a contiguous region of changes within a file.
+type DiffHunk struct {
+	Header   string   `json:"header"`   // e.g. "@@ -1,3 +1,4 @@"
+	OldStart int      `json:"oldStart"` // 1-indexed first line in the old file
+	OldLines int      `json:"oldLines"` // number of old lines covered
+	NewStart int      `json:"newStart"` // 1-indexed first line in the new file
+	NewLines int      `json:"newLines"` // number of new lines covered
+	Lines    []string `json:"lines"`    // lines prefixed with ' ', '+' or '-'
+}
+
+// Comparison describes the differences between two revisions.
+type Comparison struct {
+	Base      string     `json:"base"`      // resolved base commit hash
+	Head      string     `json:"head"`      // resolved head commit hash
+	MergeBase string     `json:"mergeBase"` // common ancestor the diff is computed from
+	Files     []FileDiff `json:"files"`     // changed files, sorted by path
+	Additions int        `json:"additions"` // total added lines
+	Deletions int        `json:"deletions"` // total deleted lines
+}
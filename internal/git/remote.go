@@ -0,0 +1,547 @@
+package git
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Hosting services whose APIs can be browsed without cloning.
+const (
+	HostGitHub = "github"
+	HostGitLab = "gitlab"
+	HostGitea  = "gitea"
+)
+
+// ErrReadOnly is returned by providers that browse a repository without a
+// working tree, for operations that would modify it.
+var ErrReadOnly = errors.New("repository is read-only; clone it to make changes")
+
+// APIError is a failed request to a hosting provider's API.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API request failed (%d): %s", e.StatusCode, e.Message)
+}
+
+// RateLimitError is returned when a hosting API refuses a request because the
+// client has used up its rate limit.
+type RateLimitError struct {
+	Limit int       // requests allowed per window, if the API said
+	Reset time.Time // when the limit resets; zero if unknown
+}
+
+func (e *RateLimitError) Error() string {
+	msg := "API rate limit exceeded"
+	if e.Limit > 0 {
+		msg += fmt.Sprintf(" (%d requests)", e.Limit)
+	}
+	if !e.Reset.IsZero() {
+		msg += fmt.Sprintf("; it resets at %s", e.Reset.Local().Format("15:04:05"))
+	}
+	return msg
+}
+
+// RetryAfter returns how long until the limit resets, or zero if unknown.
+func (e *RateLimitError) RetryAfter() time.Duration {
+	if e.Reset.IsZero() {
+		return 0
+	}
+	return max(time.Until(e.Reset), 0)
+}
+
+// isNotFound reports whether err is an API error for a missing resource.
+func isNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// isUnprocessable reports whether err is an API error for a request the API
+// could not act on, such as an unknown revision.
+func isUnprocessable(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity
+}
+
+// DefaultAPIURL returns the usual API root of a hosting service on host:
+// the public API for github.com, and the standard path on the host otherwise.
+func DefaultAPIURL(kind, host string) (string, error) {
+	switch kind {
+	case HostGitHub:
+		if host == "github.com" {
+			return GitHubAPIURL, nil
+		}
+		return "https://" + host + "/api/v3", nil
+	case HostGitLab:
+		return "https://" + host + "/api/v4", nil
+	case HostGitea:
+		return "https://" + host + "/api/v1", nil
+	}
+	return "", fmt.Errorf("unknown host type %q", kind)
+}
+
+// NewAPIProvider creates a read-only provider for the repository owner/repo on
+// a hosting service of the given kind (HostGitHub, HostGitLab or HostGitea),
+// whose API is served at apiURL.
+func NewAPIProvider(kind, apiURL, owner, repo, branch, token string) (GitProvider, error) {
+	// Each case returns its own error so a failed constructor's typed nil
+	// pointer never becomes a non-nil GitProvider
+	switch kind {
+	case HostGitHub:
+		p, err := NewGitHubProvider(apiURL, owner, repo, branch, token)
+		if err != nil {
+			return nil, err
+		}
+		return p, nil
+	case HostGitLab:
+		p, err := NewGitLabProvider(apiURL, owner+"/"+repo, branch, token)
+		if err != nil {
+			return nil, err
+		}
+		return p, nil
+	case HostGitea:
+		p, err := NewGiteaProvider(apiURL, owner, repo, branch, token)
+		if err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+	return nil, fmt.Errorf("unknown host type %q", kind)
+}
+
+// apiClient sends GET requests to a hosting provider's REST API.
+type apiClient struct {
+	client  *http.Client
+	baseURL string      // API root
+	header  http.Header // sent with every request, e.g. for authentication
+
+	etags *lruCache // of etagResponse, the responses to revalidate by request URL
+}
+
+// etagCacheSize is the number of bytes of response bodies an apiClient keeps
+// for revalidation.
+const etagCacheSize = 16 << 20
+
+// etagResponse is a remembered response body and the ETag it was served with.
+type etagResponse struct {
+	etag string
+	body []byte
+}
+
+// newAPIClient creates a client for the API at baseURL.
+func newAPIClient(baseURL string, header http.Header) *apiClient {
+	return &apiClient{
+		client:  &http.Client{Timeout: 30 * time.Second},
+		baseURL: strings.TrimRight(baseURL, "/"),
+		header:  header,
+		etags:   newLRUCache(etagCacheSize),
+	}
+}
+
+// getJSON sends a GET request for an API path and decodes the JSON response
+// into v, unless v is nil. Returns an *APIError for non-2xx responses, or a
+// *RateLimitError if the API's rate limit is used up.
+func (c *apiClient) getJSON(apiPath string, query url.Values, v any) error {
+	return c.get(apiPath, query, v, false)
+}
+
+// getJSONRevalidated is like getJSON, but remembers the response and its ETag,
+// and on later requests for the same URL asks the API only whether it has
+// changed, decoding the remembered response if not. Unchanged (304) responses
+// are cheap, and do not count against GitHub's rate limit.
+func (c *apiClient) getJSONRevalidated(apiPath string, query url.Values, v any) error {
+	return c.get(apiPath, query, v, true)
+}
+
+func (c *apiClient) get(apiPath string, query url.Values, v any, revalidate bool) error {
+	reqURL := c.baseURL + apiPath
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
+	if err != nil {
+		return err
+	}
+	for key, values := range c.header {
+		req.Header[key] = values
+	}
+
+	var cached etagResponse
+	if revalidate {
+		if value, ok := c.etags.get(reqURL); ok {
+			cached = value.(etagResponse)
+		}
+		if cached.etag != "" {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var body []byte
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached.etag != "":
+		body = cached.body
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		if rateErr := rateLimitError(resp); rateErr != nil {
+			return rateErr
+		}
+		return &APIError{StatusCode: resp.StatusCode, Message: apiErrorMessage(resp)}
+	default:
+		body, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read API response: %w", err)
+		}
+		if etag := resp.Header.Get("ETag"); revalidate && etag != "" {
+			c.etags.add(reqURL, etagResponse{etag: etag, body: body}, len(body))
+		}
+	}
+
+	if v == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to decode API response: %w", err)
+	}
+	return nil
+}
+
+// rateLimitError returns a *RateLimitError if a failed response was refused
+// for exceeding the rate limit, or nil otherwise. GitHub reports the limit in
+// X-RateLimit-* headers and GitLab in RateLimit-* headers, both with the reset
+// time in Unix seconds; secondary limits and other APIs may send Retry-After.
+func rateLimitError(resp *http.Response) *RateLimitError {
+	remaining := resp.Header.Get("X-RateLimit-Remaining")
+	reset := resp.Header.Get("X-RateLimit-Reset")
+	limit := resp.Header.Get("X-RateLimit-Limit")
+	if remaining == "" {
+		remaining = resp.Header.Get("RateLimit-Remaining")
+		reset = resp.Header.Get("RateLimit-Reset")
+		limit = resp.Header.Get("RateLimit-Limit")
+	}
+	retryAfter := resp.Header.Get("Retry-After")
+
+	exhausted := resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode == http.StatusForbidden && (remaining == "0" || retryAfter != ""))
+	if !exhausted {
+		return nil
+	}
+
+	rateErr := &RateLimitError{}
+	rateErr.Limit, _ = strconv.Atoi(limit)
+	if seconds, err := strconv.Atoi(retryAfter); err == nil {
+		rateErr.Reset = time.Now().Add(time.Duration(seconds) * time.Second)
+	} else if unix, err := strconv.ParseInt(reset, 10, 64); err == nil {
+		rateErr.Reset = time.Unix(unix, 0)
+	}
+	return rateErr
+}
+
+// apiErrorMessage extracts the error message from a failed API response.
+// GitHub and Gitea report it as "message"; GitLab uses "message" or "error".
+func apiErrorMessage(resp *http.Response) string {
+	var body map[string]any
+	json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&body)
+	for _, key := range []string{"message", "error"} {
+		if msg, ok := body[key].(string); ok && msg != "" {
+			return msg
+		}
+	}
+	return http.StatusText(resp.StatusCode)
+}
+
+// escapePath escapes each segment of a slash-separated path for use in a URL path.
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// normalizeRemotePath normalizes a file path the way LocalProvider does,
+// rejecting empty paths and paths that escape the repository root.
+func normalizeRemotePath(path string) (string, error) {
+	path = strings.Trim(strings.ReplaceAll(path, "\\", "/"), "/")
+
+	if path == "" {
+		return "", fmt.Errorf("file not found")
+	}
+	if strings.Contains(path, "..") {
+		return "", fmt.Errorf("invalid path: cannot contain '..'")
+	}
+	return path, nil
+}
+
+// treeFromPaths builds a sorted file tree from a repository's file paths.
+func treeFromPaths(paths []string) *TreeNode {
+	root := &TreeNode{
+		Name:     "",
+		Path:     "",
+		IsDir:    true,
+		Children: []TreeNode{},
+	}
+	for _, path := range paths {
+		addPathToTree(root, path)
+	}
+	sortTree(root)
+	return root
+}
+
+// readContentsFile reads a file through a contents endpoint in the style of
+// GitHub's, which Gitea also serves: it returns an object for a file and an
+// array for a directory. Files the endpoint returns without content, because
+// of their size, are read from the blob endpoint at blobPath(sha) instead.
+func readContentsFile(api *apiClient, contentsPath string, query url.Values, blobPath func(sha string) string) ([]byte, error) {
+	var raw json.RawMessage
+	if err := api.getJSON(contentsPath, query, &raw); err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("file not found")
+		}
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if strings.HasPrefix(strings.TrimSpace(string(raw)), "[") {
+		return nil, fmt.Errorf("path is a directory, not a file")
+	}
+
+	var file struct {
+		Type     string `json:"type"`
+		SHA      string `json:"sha"`
+		Encoding string `json:"encoding"`
+		Content  string `json:"content"`
+	}
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode file: %w", err)
+	}
+	if file.Type != "file" {
+		return nil, fmt.Errorf("file not found")
+	}
+
+	if file.Encoding != "base64" {
+		var blob struct {
+			Encoding string `json:"encoding"`
+			Content  string `json:"content"`
+		}
+		if err := api.getJSON(blobPath(file.SHA), nil, &blob); err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		file.Encoding, file.Content = blob.Encoding, blob.Content
+	}
+
+	return decodeBase64Content(file.Encoding, file.Content)
+}
+
+// decodeBase64Content decodes file content returned by an API, which may wrap
+// base64 across lines.
+func decodeBase64Content(encoding, content string) ([]byte, error) {
+	if encoding != "base64" {
+		return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(content, "\n", ""))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode file content: %w", err)
+	}
+	return data, nil
+}
+
+// errNotSupported reports a feature that needs a local clone.
+func errNotSupported(feature string) error {
+	return fmt.Errorf("%s not supported without a local clone", feature)
+}
+
+// apiRepo holds the state shared by providers that browse a repository
+// through a hosting API, and implements the GitProvider methods that work the
+// same for all of them. There is no working tree: the current branch shows its
+// committed state, and every operation that would modify the repository
+// returns ErrReadOnly.
+type apiRepo struct {
+	api           *apiClient
+	source        string // web URL of the repository
+	defaultBranch string
+
+	mu     sync.RWMutex // guards branch, which changes on Checkout
+	branch string
+}
+
+// currentBranch returns the branch being browsed.
+func (r *apiRepo) currentBranch() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.branch
+}
+
+// revision returns branch, or the branch being browsed if it is empty.
+func (r *apiRepo) revision(branch string) string {
+	if branch == "" {
+		return r.currentBranch()
+	}
+	return branch
+}
+
+// checkout switches the branch being browsed after check confirms it exists.
+func (r *apiRepo) checkout(branch string, check func(string) error) error {
+	if branch == "" {
+		return fmt.Errorf("branch name cannot be empty")
+	}
+	if err := check(branch); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.branch = branch
+	return nil
+}
+
+// Status returns the repository's web URL and the branch being browsed.
+// A repository browsed through the API never has uncommitted changes.
+func (r *apiRepo) Status() (*RepoStatus, error) {
+	return &RepoStatus{
+		Source:  r.source,
+		Branch:  r.currentBranch(),
+		IsDirty: false,
+	}, nil
+}
+
+// Diff returns no changes: a repository browsed through the API has no working tree.
+func (r *apiRepo) Diff() ([]FileDiff, error) {
+	return []FileDiff{}, nil
+}
+
+// Fetch does nothing; the API always serves the remote's latest state.
+func (r *apiRepo) Fetch(ctx context.Context, remote string, progress io.Writer) error {
+	return nil
+}
+
+// Pull does nothing; the API always serves the remote's latest state.
+func (r *apiRepo) Pull(ctx context.Context, remote string, progress io.Writer) error {
+	return nil
+}
+
+// CreateBranch returns ErrReadOnly; creating branches is left to the hosting platform.
+func (r *apiRepo) CreateBranch(name, from string) error {
+	return ErrReadOnly
+}
+
+// DeleteBranch returns ErrReadOnly; the remote's branches are never removed from here.
+func (r *apiRepo) DeleteBranch(name string, force bool) error {
+	return ErrReadOnly
+}
+
+// WriteFile returns ErrReadOnly because files are only read through the API.
+func (r *apiRepo) WriteFile(path string, content []byte) error {
+	return ErrReadOnly
+}
+
+// DeleteFile returns ErrReadOnly because there is no local copy of the file to remove.
+func (r *apiRepo) DeleteFile(path string) error {
+	return ErrReadOnly
+}
+
+// MoveFile returns ErrReadOnly; renaming a file would need a new commit.
+func (r *apiRepo) MoveFile(oldPath, newPath string) error {
+	return ErrReadOnly
+}
+
+// MoveFolder returns ErrReadOnly, as MoveFile does for single files.
+func (r *apiRepo) MoveFolder(oldPath, newPath string) error {
+	return ErrReadOnly
+}
+
+// CreateFolder returns ErrReadOnly since empty folders exist only in a working tree.
+func (r *apiRepo) CreateFolder(path string) error {
+	return ErrReadOnly
+}
+
+// DeleteFolder returns ErrReadOnly, as DeleteFile does for single files.
+func (r *apiRepo) DeleteFolder(path string, recursive bool) error {
+	return ErrReadOnly
+}
+
+// WriteFileIfUnchanged returns ErrReadOnly without comparing baseHash.
+func (r *apiRepo) WriteFileIfUnchanged(path string, content []byte, baseHash string) error {
+	return ErrReadOnly
+}
+
+// DeleteFileIfUnchanged returns ErrReadOnly without comparing baseHash.
+func (r *apiRepo) DeleteFileIfUnchanged(path, baseHash string) error {
+	return ErrReadOnly
+}
+
+// MoveFileIfUnchanged returns ErrReadOnly without comparing baseHash.
+func (r *apiRepo) MoveFileIfUnchanged(oldPath, newPath, baseHash string) error {
+	return ErrReadOnly
+}
+
+// ApplyChangeSet returns ErrReadOnly before applying any of the operations.
+func (r *apiRepo) ApplyChangeSet(ops []ChangeOp, message string) (string, error) {
+	return "", ErrReadOnly
+}
+
+// Commit returns ErrReadOnly since nothing can be staged without a working tree.
+func (r *apiRepo) Commit(message string) (string, error) {
+	return "", ErrReadOnly
+}
+
+// CommitWithOptions returns ErrReadOnly, whatever the options.
+func (r *apiRepo) CommitWithOptions(opts CommitOptions) (string, error) {
+	return "", ErrReadOnly
+}
+
+// Push returns ErrReadOnly because the remote is the only copy of the history.
+func (r *apiRepo) Push(remote, branch string) error {
+	return ErrReadOnly
+}
+
+// Tags is not supported: listing tags needs a local clone.
+func (r *apiRepo) Tags() ([]TagInfo, error) {
+	return nil, errNotSupported("tags")
+}
+
+// CommitSignature is not supported: verifying signatures needs the commit objects of a local clone.
+func (r *apiRepo) CommitSignature(hash string) (SignatureInfo, error) {
+	return SignatureInfo{}, errNotSupported("commit signatures")
+}
+
+// SearchFileNames is not supported: matching file names needs the full tree of a local clone.
+func (r *apiRepo) SearchFileNames(query string) ([]string, error) {
+	return nil, errNotSupported("search")
+}
+
+// SearchContent is not supported: searching file contents needs a local clone.
+func (r *apiRepo) SearchContent(query string) ([]SearchResult, error) {
+	return nil, errNotSupported("search")
+}
+
+// Log is not supported: walking commit history needs a local clone.
+func (r *apiRepo) Log(path, branch string, limit int, cursor string) (*LogPage, error) {
+	return nil, errNotSupported("history")
+}
+
+// Blame is not supported: attributing lines to commits needs a local clone.
+func (r *apiRepo) Blame(path, branch string) (*FileBlame, error) {
+	return nil, errNotSupported("blame")
+}
+
+// Compare is not supported: diffing two revisions needs a local clone.
+func (r *apiRepo) Compare(base, head string) (*Comparison, error) {
+	return nil, errNotSupported("compare")
+}
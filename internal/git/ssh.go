@@ -0,0 +1,257 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/skeema/knownhosts"
+	"golang.org/x/crypto/ssh"
+)
+
+// Host key policies for SSH remotes
+const (
+	// HostKeyStrict only connects to hosts whose key is in known_hosts
+	HostKeyStrict = "strict"
+	// HostKeyAcceptNew adds the key of a host that is not in known_hosts yet,
+	// like OpenSSH's StrictHostKeyChecking=accept-new, but refuses changed keys
+	HostKeyAcceptNew = "accept-new"
+	// HostKeyInsecure connects without verifying the host key
+	HostKeyInsecure = "insecure"
+)
+
+// defaultSSHKeys are the private keys tried, in order, when no key file is
+// configured and no ssh-agent is running, relative to the home directory.
+var defaultSSHKeys = []string{".ssh/id_ed25519", ".ssh/id_ecdsa", ".ssh/id_rsa"}
+
+// SSHOptions configures authentication with SSH remotes.
+type SSHOptions struct {
+	// Private key to authenticate with. When empty, the keys held by
+	// ssh-agent are used if SSH_AUTH_SOCK is set, or else the first of
+	// ~/.ssh/id_ed25519, id_ecdsa, and id_rsa that exists.
+	KeyFile string
+
+	// Passphrase for an encrypted private key
+	Passphrase string
+
+	// known_hosts file that host keys are checked against and new hosts are
+	// added to. When empty, ~/.ssh/known_hosts is used and
+	// /etc/ssh/ssh_known_hosts is also checked.
+	KnownHosts string
+
+	// HostKeyStrict, HostKeyAcceptNew, or HostKeyInsecure; strict when empty
+	HostKeyPolicy string
+}
+
+// Credentials holds what is needed to authenticate with remotes: a token for
+// HTTP(S) remotes, and SSH options for SSH remotes.
+type Credentials struct {
+	Username string // sent with Token; the token is sent alone when empty
+	Token    string // personal access token; HTTP(S) requests are anonymous when empty
+	Host     string // host the token is for; it is sent to every host when empty
+	SSH      SSHOptions
+}
+
+// authFor returns the authentication to use for a remote URL, or nil for none.
+// Tokens only apply to HTTP(S) remotes on the token's host; SSH remotes always
+// authenticate with a key.
+func (c Credentials) authFor(url string) (transport.AuthMethod, error) {
+	if isSSHURL(url) {
+		return c.SSH.auth(url)
+	}
+	if c.Token == "" || !isHTTPURL(url) {
+		return nil, nil
+	}
+	if c.Host != "" {
+		// Never send the token to another host, e.g. a second remote
+		ep, err := transport.NewEndpoint(url)
+		if err != nil || !strings.EqualFold(ep.Host, c.Host) {
+			return nil, nil
+		}
+	}
+	return tokenAuth(c.Username, c.Token), nil
+}
+
+// isSSHURL reports whether a remote URL uses SSH, either as ssh://host/path
+// or in the scp-like form user@host:path.
+func isSSHURL(url string) bool {
+	ep, err := transport.NewEndpoint(url)
+	return err == nil && ep.Protocol == "ssh"
+}
+
+// auth returns the SSH authentication for a remote URL, with its host key
+// checked against known_hosts according to the host key policy.
+func (o SSHOptions) auth(url string) (transport.AuthMethod, error) {
+	ep, err := transport.NewEndpoint(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SSH URL %s: %w", url, err)
+	}
+
+	hostKeys, err := o.hostKeyCallback(ep)
+	if err != nil {
+		return nil, err
+	}
+
+	username := ep.User
+	if username == "" {
+		// Like ssh, log in as the local user when the URL names none
+		current, err := user.Current()
+		if err != nil {
+			return nil, fmt.Errorf("no user in SSH URL %s and the local user is unknown: %w", url, err)
+		}
+		username = current.Username
+	}
+
+	keyFile := o.KeyFile
+	if keyFile == "" {
+		if os.Getenv("SSH_AUTH_SOCK") != "" {
+			agentAuth, err := gitssh.NewSSHAgentAuth(username)
+			if err != nil {
+				return nil, fmt.Errorf("failed to connect to ssh-agent: %w", err)
+			}
+			agentAuth.HostKeyCallbackHelper = hostKeys
+			return agentAuth, nil
+		}
+
+		keyFile, err = defaultSSHKey()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	key, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load SSH key %s: %w", keyFile, err)
+	}
+	if o.Passphrase == "" {
+		var missing *ssh.PassphraseMissingError
+		if _, err := ssh.ParsePrivateKey(key); errors.As(err, &missing) {
+			return nil, fmt.Errorf("SSH key %s is encrypted and no passphrase was given", keyFile)
+		}
+	}
+
+	keyAuth, err := gitssh.NewPublicKeys(username, key, o.Passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load SSH key %s: %w", keyFile, err)
+	}
+	keyAuth.HostKeyCallbackHelper = hostKeys
+	return keyAuth, nil
+}
+
+// defaultSSHKey returns the first of the default private keys that exists.
+func defaultSSHKey() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+
+	for _, name := range defaultSSHKeys {
+		path := filepath.Join(home, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no SSH key found: start ssh-agent or configure a key file")
+}
+
+// hostKeyCallback returns the host key verification for an endpoint. The
+// known_hosts files are read now, so hosts added by one connection are known
+// to the next.
+func (o SSHOptions) hostKeyCallback(ep *transport.Endpoint) (gitssh.HostKeyCallbackHelper, error) {
+	policy := o.HostKeyPolicy
+	if policy == "" {
+		policy = HostKeyStrict
+	}
+
+	switch policy {
+	case HostKeyInsecure:
+		return gitssh.HostKeyCallbackHelper{HostKeyCallback: ssh.InsecureIgnoreHostKey()}, nil
+	case HostKeyStrict, HostKeyAcceptNew:
+	default:
+		return gitssh.HostKeyCallbackHelper{}, fmt.Errorf("unknown SSH host key policy %q (expected %q, %q, or %q)",
+			policy, HostKeyStrict, HostKeyAcceptNew, HostKeyInsecure)
+	}
+
+	knownHostsFile, files, err := o.knownHostsFiles()
+	if err != nil {
+		return gitssh.HostKeyCallbackHelper{}, err
+	}
+
+	db, err := knownhosts.NewDB(files...)
+	if err != nil {
+		return gitssh.HostKeyCallbackHelper{}, fmt.Errorf("failed to read known_hosts: %w", err)
+	}
+	check := db.HostKeyCallback()
+
+	callback := func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		err := check(hostname, remote, key)
+		switch {
+		case err == nil:
+			return nil
+		case knownhosts.IsHostKeyChanged(err):
+			return fmt.Errorf("host key for %s does not match the one in known_hosts; the host may be an impostor, or its key may have been replaced", hostname)
+		case knownhosts.IsHostUnknown(err) && policy == HostKeyAcceptNew:
+			return addKnownHost(knownHostsFile, hostname, remote, key)
+		case knownhosts.IsHostUnknown(err):
+			return fmt.Errorf("host key for %s is not in %s; add it with ssh-keyscan, or use the %q host key policy", hostname, knownHostsFile, HostKeyAcceptNew)
+		}
+		return err
+	}
+
+	// Offer the host's known key types first, so a host with several keys
+	// presents the one that is in known_hosts
+	port := ep.Port
+	if port == 0 {
+		port = gitssh.DefaultPort
+	}
+	algorithms := db.HostKeyAlgorithms(net.JoinHostPort(ep.Host, strconv.Itoa(port)))
+
+	return gitssh.HostKeyCallbackHelper{HostKeyCallback: callback, HostKeyAlgorithms: algorithms}, nil
+}
+
+// knownHostsFiles returns the known_hosts file new hosts are added to, and all
+// existing known_hosts files to check host keys against.
+func (o SSHOptions) knownHostsFiles() (knownHostsFile string, files []string, err error) {
+	candidates := []string{o.KnownHosts}
+	if o.KnownHosts == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to get user home directory: %w", err)
+		}
+		candidates = []string{filepath.Join(home, ".ssh", "known_hosts"), "/etc/ssh/ssh_known_hosts"}
+	}
+
+	// Missing files have no hosts in them
+	for _, file := range candidates {
+		if _, err := os.Stat(file); err == nil {
+			files = append(files, file)
+		}
+	}
+	return candidates[0], files, nil
+}
+
+// addKnownHost appends a host's key to a known_hosts file, creating the file
+// if needed.
+func addKnownHost(knownHostsFile, hostname string, remote net.Addr, key ssh.PublicKey) error {
+	if err := os.MkdirAll(filepath.Dir(knownHostsFile), 0700); err != nil {
+		return fmt.Errorf("failed to create known_hosts directory: %w", err)
+	}
+
+	f, err := os.OpenFile(knownHostsFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open known_hosts: %w", err)
+	}
+	defer f.Close()
+
+	if err := knownhosts.WriteKnownHost(f, hostname, remote, key); err != nil {
+		return fmt.Errorf("failed to add %s to known_hosts: %w", hostname, err)
+	}
+	return nil
+}
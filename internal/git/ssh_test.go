@@ -0,0 +1,673 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/server"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/skeema/knownhosts"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// fakeSSHGit is an in-process SSH server that serves the bare repositories in a
+// directory over git's SSH protocol, as a git host does. It accepts a single
+// client key.
+type fakeSSHGit struct {
+	addr      string             // host:port the server listens on
+	hostKey   ssh.Signer         // key the server identifies itself with
+	clientKey ed25519.PrivateKey // the only key allowed to log in
+	root      string             // directory holding the repositories
+
+	mu     sync.Mutex
+	logins []string // users that logged in
+}
+
+// newFakeSSHGit starts a server for a bare repository wiki.git, seeded with a
+// commit of README.md, and returns the server and the repository's URL.
+func newFakeSSHGit(t *testing.T) (*fakeSSHGit, string) {
+	t.Helper()
+
+	fake := &fakeSSHGit{
+		hostKey:   newHostKey(t),
+		clientKey: newSSHKey(t),
+		root:      t.TempDir(),
+	}
+
+	// Seed the repository through a local clone
+	bareDir := filepath.Join(fake.root, "wiki.git")
+	if _, err := git.PlainInit(bareDir, true); err != nil {
+		t.Fatalf("failed to init bare repo: %v", err)
+	}
+	seedDir := t.TempDir()
+	seed, err := git.PlainInit(seedDir, false)
+	if err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+	if _, err := seed.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{bareDir}}); err != nil {
+		t.Fatalf("failed to add remote: %v", err)
+	}
+	commitFile(t, seed, seedDir, "README.md", "# Wiki\n", "Initial", time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	if err := seed.Push(&git.PushOptions{RemoteName: "origin"}); err != nil {
+		t.Fatalf("failed to push initial commit: %v", err)
+	}
+
+	clientPublicKey, err := ssh.NewPublicKey(fake.clientKey.Public())
+	if err != nil {
+		t.Fatalf("failed to read client public key: %v", err)
+	}
+	serverConfig := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if !bytes.Equal(key.Marshal(), clientPublicKey.Marshal()) {
+				return nil, errors.New("unknown key")
+			}
+			fake.mu.Lock()
+			fake.logins = append(fake.logins, conn.User())
+			fake.mu.Unlock()
+			return nil, nil
+		},
+	}
+	serverConfig.AddHostKey(fake.hostKey)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	fake.addr = listener.Addr().String()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go fake.serveConn(conn, serverConfig)
+		}
+	}()
+
+	return fake, "ssh://git@" + fake.addr + "/wiki.git"
+}
+
+// serveConn runs the git commands requested over one SSH connection.
+func (f *fakeSSHGit) serveConn(conn net.Conn, serverConfig *ssh.ServerConfig) {
+	_, channels, requests, err := ssh.NewServerConn(conn, serverConfig)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(requests)
+
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "only sessions are supported")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go func() {
+			defer channel.Close()
+			for req := range requests {
+				if req.Type != "exec" {
+					req.Reply(false, nil)
+					continue
+				}
+				var exec struct{ Command string }
+				if err := ssh.Unmarshal(req.Payload, &exec); err != nil {
+					req.Reply(false, nil)
+					continue
+				}
+				req.Reply(true, nil)
+
+				status := uint32(0)
+				if err := f.runGitCommand(exec.Command, channel); err != nil {
+					io.WriteString(channel.Stderr(), err.Error()+"\n")
+					status = 1
+				}
+				channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{status}))
+				return
+			}
+		}()
+	}
+}
+
+// runGitCommand serves git-upload-pack or git-receive-pack for a repository
+// under the root directory, like git's own commands do over stdin and stdout.
+func (f *fakeSSHGit) runGitCommand(command string, channel ssh.Channel) error {
+	name, arg, _ := strings.Cut(command, " ")
+	ep, err := transport.NewEndpoint(strings.Trim(arg, "'"))
+	if err != nil {
+		return err
+	}
+	srv := server.NewServer(server.NewFilesystemLoader(osfs.New(f.root)))
+
+	switch name {
+	case "git-upload-pack":
+		session, err := srv.NewUploadPackSession(ep, nil)
+		if err != nil {
+			return err
+		}
+		refs, err := session.AdvertisedReferences()
+		if err != nil {
+			return err
+		}
+		if err := refs.Encode(channel); err != nil {
+			return err
+		}
+		req := packp.NewUploadPackRequest()
+		if err := req.Decode(channel); err != nil {
+			// The client hung up after the refs, having nothing to fetch
+			return nil
+		}
+		resp, err := session.UploadPack(context.Background(), req)
+		if err != nil {
+			return err
+		}
+		return resp.Encode(channel)
+
+	case "git-receive-pack":
+		session, err := srv.NewReceivePackSession(ep, nil)
+		if err != nil {
+			return err
+		}
+		refs, err := session.AdvertisedReferences()
+		if err != nil {
+			return err
+		}
+		if err := refs.Encode(channel); err != nil {
+			return err
+		}
+		// The request closes what it reads the packfile from once done,
+		// which must not close the channel before the status is sent
+		req := packp.NewReferenceUpdateRequest()
+		if err := req.Decode(struct{ io.Reader }{channel}); err != nil {
+			return nil
+		}
+		status, err := session.ReceivePack(context.Background(), req)
+		if status != nil {
+			if err := status.Encode(channel); err != nil {
+				return err
+			}
+		}
+		return err
+	}
+
+	return errors.New("unsupported command: " + command)
+}
+
+// loggedInAs returns the users that logged in, in order.
+func (f *fakeSSHGit) loggedInAs() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.logins...)
+}
+
+// knownHostsLine returns the server's known_hosts entry, for the given key
+// or the server's own if nil.
+func (f *fakeSSHGit) knownHostsLine(key ssh.PublicKey) string {
+	if key == nil {
+		key = f.hostKey.PublicKey()
+	}
+	return knownhosts.Line([]string{knownhosts.Normalize(f.addr)}, key) + "\n"
+}
+
+// writeClientKey writes the client's private key in OpenSSH format to a new
+// file, encrypted if passphrase is non-empty, and returns its path.
+func (f *fakeSSHGit) writeClientKey(t *testing.T, passphrase string) string {
+	t.Helper()
+
+	var block *pem.Block
+	var err error
+	if passphrase == "" {
+		block, err = ssh.MarshalPrivateKey(f.clientKey, "")
+	} else {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(f.clientKey, "", []byte(passphrase))
+	}
+	if err != nil {
+		t.Fatalf("failed to marshal client key: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatalf("failed to write client key: %v", err)
+	}
+	return path
+}
+
+// writeKnownHosts writes a known_hosts file with the given lines and returns its path.
+func writeKnownHosts(t *testing.T, lines ...string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "")), 0600); err != nil {
+		t.Fatalf("failed to write known_hosts: %v", err)
+	}
+	return path
+}
+
+func newSSHKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return key
+}
+
+func newHostKey(t *testing.T) ssh.Signer {
+	t.Helper()
+
+	signer, err := ssh.NewSignerFromKey(newSSHKey(t))
+	if err != nil {
+		t.Fatalf("failed to create signer: %v", err)
+	}
+	return signer
+}
+
+// pushOverSSH clones the remote into a new directory, commits a file there,
+// and pushes it. Returns the new commit hash.
+func pushOverSSH(t *testing.T, url string, creds Credentials, path, content string) string {
+	t.Helper()
+
+	otherDir := t.TempDir()
+	if err := CloneRemoteWithCredentials(url, otherDir, creds); err != nil {
+		t.Fatalf("clone failed: %v", err)
+	}
+	other, err := NewLocalProvider(otherDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	other.SetCredentials(creds)
+
+	hash := commitFile(t, other.repo, otherDir, path, content, "Remote change", time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC))
+	if err := other.Push("", ""); err != nil {
+		t.Fatalf("failed to push from other clone: %v", err)
+	}
+
+	return hash
+}
+
+// readFile returns the content of a file in a working tree.
+func readFile(t *testing.T, dir, path string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(filepath.Join(dir, path))
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return string(content)
+}
+
+func TestSSH_CloneAndPullWithKeyFile(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	fake, url := newFakeSSHGit(t)
+
+	creds := Credentials{
+		Token: "ignored-for-ssh",
+		SSH: SSHOptions{
+			KeyFile:    fake.writeClientKey(t, ""),
+			KnownHosts: writeKnownHosts(t, fake.knownHostsLine(nil)),
+		},
+	}
+
+	cloneDir := filepath.Join(t.TempDir(), "wiki")
+	if err := CloneRemoteWithCredentials(url, cloneDir, creds); err != nil {
+		t.Fatalf("CloneRemoteWithCredentials failed: %v", err)
+	}
+	if content := readFile(t, cloneDir, "README.md"); content != "# Wiki\n" {
+		t.Errorf("expected cloned README, got %q", content)
+	}
+
+	// The user comes from the URL
+	if logins := fake.loggedInAs(); len(logins) == 0 || logins[0] != "git" {
+		t.Errorf("expected to log in as git, got %v", logins)
+	}
+
+	// Pulling picks up commits pushed by others
+	hash := pushOverSSH(t, url, creds, "page.md", "page\n")
+	if err := PullExistingWithCredentials(cloneDir, creds); err != nil {
+		t.Fatalf("PullExistingWithCredentials failed: %v", err)
+	}
+	repo, err := git.PlainOpen(cloneDir)
+	if err != nil {
+		t.Fatalf("failed to open clone: %v", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v", err)
+	}
+	if head.Hash().String() != hash {
+		t.Errorf("expected HEAD at %s after pull, got %s", hash, head.Hash())
+	}
+
+	// Pulling again is a no-op
+	if err := PullExistingWithCredentials(cloneDir, creds); err != nil {
+		t.Errorf("expected up-to-date pull to succeed, got %v", err)
+	}
+}
+
+func TestSSH_PushAndFetchWithProvider(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	fake, url := newFakeSSHGit(t)
+
+	creds := Credentials{SSH: SSHOptions{
+		KeyFile:    fake.writeClientKey(t, ""),
+		KnownHosts: writeKnownHosts(t, fake.knownHostsLine(nil)),
+	}}
+
+	cloneDir := filepath.Join(t.TempDir(), "wiki")
+	if err := CloneRemoteWithCredentials(url, cloneDir, creds); err != nil {
+		t.Fatalf("CloneRemoteWithCredentials failed: %v", err)
+	}
+
+	provider, err := NewLocalProvider(cloneDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	provider.SetCredentials(creds)
+
+	// Push a new commit
+	repo, err := git.PlainOpen(cloneDir)
+	if err != nil {
+		t.Fatalf("failed to open clone: %v", err)
+	}
+	tip := commitFile(t, repo, cloneDir, "notes.md", "notes\n", "Add notes", time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC))
+	if err := provider.Push("", ""); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+
+	bare, err := git.PlainOpen(filepath.Join(fake.root, "wiki.git"))
+	if err != nil {
+		t.Fatalf("failed to open remote: %v", err)
+	}
+	ref, err := bare.Reference(plumbing.NewBranchReferenceName(provider.currentBranch()), false)
+	if err != nil {
+		t.Fatalf("expected branch on remote: %v", err)
+	}
+	if ref.Hash().String() != tip {
+		t.Errorf("expected remote at %s, got %s", tip, ref.Hash())
+	}
+
+	// Fetch and pull what others pushed
+	hash := pushOverSSH(t, url, creds, "page.md", "page\n")
+	if err := provider.Fetch(context.Background(), "", nil); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if err := provider.Pull(context.Background(), "", nil); err != nil {
+		t.Fatalf("Pull failed: %v", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v", err)
+	}
+	if head.Hash().String() != hash {
+		t.Errorf("expected HEAD at %s after pull, got %s", hash, head.Hash())
+	}
+
+	// Without credentials for SSH, the provider cannot log in
+	provider.SetCredentials(Credentials{SSH: SSHOptions{
+		KeyFile:    filepath.Join(t.TempDir(), "missing"),
+		KnownHosts: creds.SSH.KnownHosts,
+	}})
+	if err := provider.Fetch(context.Background(), "", nil); err == nil || !strings.Contains(err.Error(), "failed to load SSH key") {
+		t.Errorf("expected SSH key error, got %v", err)
+	}
+}
+
+func TestSSH_EncryptedKey(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	fake, url := newFakeSSHGit(t)
+
+	creds := Credentials{SSH: SSHOptions{
+		KeyFile:    fake.writeClientKey(t, "correct horse"),
+		KnownHosts: writeKnownHosts(t, fake.knownHostsLine(nil)),
+	}}
+
+	err := CloneRemoteWithCredentials(url, filepath.Join(t.TempDir(), "wiki"), creds)
+	if err == nil || !strings.Contains(err.Error(), "encrypted and no passphrase was given") {
+		t.Errorf("expected missing passphrase error, got %v", err)
+	}
+
+	creds.SSH.Passphrase = "wrong"
+	if err := CloneRemoteWithCredentials(url, filepath.Join(t.TempDir(), "wiki"), creds); err == nil {
+		t.Error("expected error with a wrong passphrase")
+	}
+
+	creds.SSH.Passphrase = "correct horse"
+	if err := CloneRemoteWithCredentials(url, filepath.Join(t.TempDir(), "wiki"), creds); err != nil {
+		t.Errorf("CloneRemoteWithCredentials failed with passphrase: %v", err)
+	}
+}
+
+func TestSSH_UnauthorizedKey(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	fake, url := newFakeSSHGit(t)
+
+	// A key the server does not accept
+	other := &fakeSSHGit{clientKey: newSSHKey(t)}
+	creds := Credentials{SSH: SSHOptions{
+		KeyFile:    other.writeClientKey(t, ""),
+		KnownHosts: writeKnownHosts(t, fake.knownHostsLine(nil)),
+	}}
+
+	err := CloneRemoteWithCredentials(url, filepath.Join(t.TempDir(), "wiki"), creds)
+	if err == nil || !strings.Contains(err.Error(), "unable to authenticate") {
+		t.Errorf("expected authentication error, got %v", err)
+	}
+}
+
+func TestSSH_Agent(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("ssh-agent is reached through a named pipe on Windows")
+	}
+	fake, url := newFakeSSHGit(t)
+
+	// Serve the client key from an in-process agent
+	keyring := agent.NewKeyring()
+	if err := keyring.Add(agent.AddedKey{PrivateKey: fake.clientKey}); err != nil {
+		t.Fatalf("failed to add key to agent: %v", err)
+	}
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				agent.ServeAgent(keyring, conn)
+			}()
+		}
+	}()
+	t.Setenv("SSH_AUTH_SOCK", socket)
+
+	// No key file, so the agent's keys are used
+	creds := Credentials{SSH: SSHOptions{KnownHosts: writeKnownHosts(t, fake.knownHostsLine(nil))}}
+	cloneDir := filepath.Join(t.TempDir(), "wiki")
+	if err := CloneRemoteWithCredentials(url, cloneDir, creds); err != nil {
+		t.Fatalf("CloneRemoteWithCredentials failed: %v", err)
+	}
+	if content := readFile(t, cloneDir, "README.md"); content != "# Wiki\n" {
+		t.Errorf("expected cloned README, got %q", content)
+	}
+}
+
+func TestSSH_DefaultKey(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	fake, url := newFakeSSHGit(t)
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	creds := Credentials{SSH: SSHOptions{KnownHosts: writeKnownHosts(t, fake.knownHostsLine(nil))}}
+
+	// Without an agent or any key in ~/.ssh there is nothing to log in with
+	err := CloneRemoteWithCredentials(url, filepath.Join(t.TempDir(), "wiki"), creds)
+	if err == nil || !strings.Contains(err.Error(), "no SSH key found") {
+		t.Errorf("expected no SSH key error, got %v", err)
+	}
+
+	// ~/.ssh/id_ed25519 is used when present
+	key, err := os.ReadFile(fake.writeClientKey(t, ""))
+	if err != nil {
+		t.Fatalf("failed to read client key: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(home, ".ssh"), 0700); err != nil {
+		t.Fatalf("failed to create ~/.ssh: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".ssh", "id_ed25519"), key, 0600); err != nil {
+		t.Fatalf("failed to write default key: %v", err)
+	}
+	if err := CloneRemoteWithCredentials(url, filepath.Join(t.TempDir(), "wiki"), creds); err != nil {
+		t.Errorf("CloneRemoteWithCredentials failed with default key: %v", err)
+	}
+}
+
+func TestSSH_HostKeyPolicy(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	fake, url := newFakeSSHGit(t)
+	keyFile := fake.writeClientKey(t, "")
+
+	clone := func(policy, knownHosts string) error {
+		t.Helper()
+		creds := Credentials{SSH: SSHOptions{KeyFile: keyFile, KnownHosts: knownHosts, HostKeyPolicy: policy}}
+		return CloneRemoteWithCredentials(url, filepath.Join(t.TempDir(), "wiki"), creds)
+	}
+
+	t.Run("strict refuses unknown hosts", func(t *testing.T) {
+		knownHosts := filepath.Join(t.TempDir(), "known_hosts")
+		for _, policy := range []string{"", HostKeyStrict} {
+			err := clone(policy, knownHosts)
+			if err == nil || !strings.Contains(err.Error(), "is not in "+knownHosts) {
+				t.Errorf("expected unknown host error for policy %q, got %v", policy, err)
+			}
+		}
+		if _, err := os.Stat(knownHosts); !os.IsNotExist(err) {
+			t.Errorf("expected known_hosts to be left alone, got %v", err)
+		}
+	})
+
+	t.Run("accept-new adds unknown hosts", func(t *testing.T) {
+		knownHosts := filepath.Join(t.TempDir(), "ssh", "known_hosts")
+		if err := clone(HostKeyAcceptNew, knownHosts); err != nil {
+			t.Fatalf("clone failed: %v", err)
+		}
+
+		content, err := os.ReadFile(knownHosts)
+		if err != nil {
+			t.Fatalf("expected known_hosts to be created: %v", err)
+		}
+		if !strings.HasPrefix(string(content), strings.TrimSuffix(fake.knownHostsLine(nil), "\n")) {
+			t.Errorf("expected host to be added to known_hosts, got %q", content)
+		}
+
+		// Now that the host is known, strict checking accepts it
+		if err := clone(HostKeyStrict, knownHosts); err != nil {
+			t.Errorf("expected added host to be known, got %v", err)
+		}
+	})
+
+	t.Run("changed host keys are refused", func(t *testing.T) {
+		knownHosts := writeKnownHosts(t, fake.knownHostsLine(newHostKey(t).PublicKey()))
+		for _, policy := range []string{HostKeyStrict, HostKeyAcceptNew} {
+			err := clone(policy, knownHosts)
+			if err == nil || !strings.Contains(err.Error(), "does not match the one in known_hosts") {
+				t.Errorf("expected changed host key error for policy %q, got %v", policy, err)
+			}
+		}
+	})
+
+	t.Run("insecure skips verification", func(t *testing.T) {
+		knownHosts := writeKnownHosts(t, fake.knownHostsLine(newHostKey(t).PublicKey()))
+		if err := clone(HostKeyInsecure, knownHosts); err != nil {
+			t.Errorf("expected insecure policy to connect, got %v", err)
+		}
+	})
+
+	t.Run("unknown policy", func(t *testing.T) {
+		err := clone("trust-me", writeKnownHosts(t))
+		if err == nil || !strings.Contains(err.Error(), `unknown SSH host key policy "trust-me"`) {
+			t.Errorf("expected unknown policy error, got %v", err)
+		}
+	})
+}
+
+func TestCredentials_AuthFor(t *testing.T) {
+	creds := Credentials{
+		Token: "secret",
+		SSH:   SSHOptions{HostKeyPolicy: HostKeyInsecure},
+	}
+
+	// Tokens go to HTTP(S) remotes only
+	auth, err := creds.authFor("https://github.com/org/repo.git")
+	if err != nil {
+		t.Fatalf("authFor failed: %v", err)
+	}
+	if basic, ok := auth.(*http.BasicAuth); !ok || basic.Username != "secret" {
+		t.Errorf("expected token basic auth for HTTPS, got %#v", auth)
+	}
+
+	for _, url := range []string{"/srv/git/repo.git", "file:///srv/git/repo.git"} {
+		if auth, err := creds.authFor(url); err != nil || auth != nil {
+			t.Errorf("expected no authentication for %s, got %#v, %v", url, auth, err)
+		}
+	}
+	if auth, err := (Credentials{}).authFor("https://github.com/org/repo.git"); err != nil || auth != nil {
+		t.Errorf("expected no authentication without a token, got %#v, %v", auth, err)
+	}
+
+	// A token for one host is never sent to another
+	hostCreds := Credentials{Token: "secret", Host: "github.com"}
+	if auth, err := hostCreds.authFor("https://GitHub.com:443/org/repo.git"); err != nil || auth == nil {
+		t.Errorf("expected token auth for the token's host, got %#v, %v", auth, err)
+	}
+	if auth, err := hostCreds.authFor("https://mirror.example.com/org/repo.git"); err != nil || auth != nil {
+		t.Errorf("expected no authentication for another host, got %#v, %v", auth, err)
+	}
+
+	// SSH remotes in either form authenticate with a key, as the URL's user
+	t.Setenv("SSH_AUTH_SOCK", "")
+	creds.SSH.KeyFile = (&fakeSSHGit{clientKey: newSSHKey(t)}).writeClientKey(t, "")
+	for _, url := range []string{"git@github.com:org/repo.git", "ssh://deploy@git.example.com:2222/org/repo.git"} {
+		auth, err := creds.authFor(url)
+		if err != nil {
+			t.Fatalf("authFor(%s) failed: %v", url, err)
+		}
+		keys, ok := auth.(*gitssh.PublicKeys)
+		if !ok {
+			t.Fatalf("expected public key auth for %s, got %#v", url, auth)
+		}
+		if expected, _, _ := strings.Cut(strings.TrimPrefix(url, "ssh://"), "@"); keys.User != expected {
+			t.Errorf("expected user %s for %s, got %s", expected, url, keys.User)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// handleBlame handles GET /api/blame/<path>?branch=<branch>
+// Returns line ranges of the file grouped by the commit that last changed them.
+func (s *Server) handleBlame(w http.ResponseWriter, r *http.Request) {
+	// Extract path from URL (everything after /api/blame/)
+	path := strings.TrimPrefix(r.URL.Path, "/api/blame/")
+	path = strings.Trim(path, "/")
+
+	// Get branch from query parameter (empty string uses current branch)
+	branch := r.URL.Query().Get("branch")
+
+	blame, err := s.provider.Blame(path, branch)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "invalid path") || strings.Contains(err.Error(), "binary file") {
+			status = http.StatusBadRequest
+		} else if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	// Return as JSON
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(blame); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
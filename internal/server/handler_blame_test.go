@@ -0,0 +1,97 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buckleypaul/giki/internal/git"
+	gogit "github.com/go-git/go-git/v5"
+)
+
+func TestHandleBlame(t *testing.T) {
+	tempDir := t.TempDir()
+	repo := createTestRepoWithCommit(t, tempDir)
+
+	w, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(tempDir, "docs"), 0755); err != nil {
+		t.Fatalf("failed to create docs dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "docs", "page.md"), []byte("line one\nline two\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if _, err := w.Add("docs/page.md"); err != nil {
+		t.Fatalf("failed to add file: %v", err)
+	}
+	hash, err := w.Commit("Add page", &gogit.CommitOptions{Author: testSignature()})
+	if err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	server := New(4242, provider)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/blame/docs/page.md", nil)
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var blame git.FileBlame
+	if err := json.NewDecoder(rec.Body).Decode(&blame); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(blame.Ranges) != 1 {
+		t.Fatalf("expected 1 range, got %d", len(blame.Ranges))
+	}
+	r := blame.Ranges[0]
+	if r.StartLine != 1 || r.EndLine != 2 {
+		t.Errorf("expected lines 1-2, got %d-%d", r.StartLine, r.EndLine)
+	}
+	if r.Hash != hash.String() {
+		t.Errorf("expected hash %s, got %s", hash, r.Hash)
+	}
+	if r.Summary != "Add page" {
+		t.Errorf("expected summary 'Add page', got %q", r.Summary)
+	}
+}
+
+func TestHandleBlame_NotFound(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	server := New(4242, provider)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/blame/missing.md", nil)
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", rec.Code)
+	}
+
+	var resp ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Error == "" {
+		t.Error("expected error message in response")
+	}
+}
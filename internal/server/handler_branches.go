@@ -2,9 +2,20 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/buckleypaul/giki/internal/git"
 )
 
+// CreateBranchRequest represents the JSON payload for POST /api/branches
+type CreateBranchRequest struct {
+	Name string `json:"name"`
+	From string `json:"from"` // branch, tag, or commit to start from; empty = HEAD
+}
+
 // handleBranches handles GET /api/branches requests.
 // Returns a JSON array of all branches in the repository.
 // The current HEAD branch is marked with isDefault: true.
@@ -23,3 +34,85 @@ func (s *Server) handleBranches(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// handleCreateBranch handles POST /api/branches requests.
+// Creates a new branch without checking it out.
+func (s *Server) handleCreateBranch(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var req CreateBranchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "invalid request body"})
+		return
+	}
+
+	// Create branch
+	if err := s.provider.CreateBranch(req.Name, req.From); err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, git.ErrReadOnly):
+			status = http.StatusForbidden
+		case strings.Contains(err.Error(), "invalid branch name"),
+			strings.Contains(err.Error(), "cannot be empty"):
+			status = http.StatusBadRequest
+		case strings.Contains(err.Error(), "already exists"):
+			status = http.StatusConflict
+		case strings.Contains(err.Error(), "not found"):
+			status = http.StatusNotFound
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	// Return the new branch
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(git.BranchInfo{Name: strings.TrimSpace(req.Name)})
+}
+
+// handleDeleteBranch handles DELETE /api/branches/{name} requests.
+// Unmerged branches are only deleted with ?force=true.
+func (s *Server) handleDeleteBranch(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	force := false
+	if forceParam := r.URL.Query().Get("force"); forceParam != "" {
+		var err error
+		force, err = strconv.ParseBool(forceParam)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: "invalid force parameter"})
+			return
+		}
+	}
+
+	// Delete branch
+	if err := s.provider.DeleteBranch(name, force); err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, git.ErrReadOnly):
+			status = http.StatusForbidden
+		case strings.Contains(err.Error(), "cannot be empty"):
+			status = http.StatusBadRequest
+		case strings.Contains(err.Error(), "not found"):
+			status = http.StatusNotFound
+		case strings.Contains(err.Error(), "current branch"),
+			strings.Contains(err.Error(), "not fully merged"):
+			status = http.StatusConflict
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	// Return success response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(SuccessResponse{Success: true})
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"bytes"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -199,3 +200,150 @@ func TestHandleBranches_SingleBranch(t *testing.T) {
 		t.Error("expected branch name to be non-empty")
 	}
 }
+
+// TestHandleCreateBranch tests creating branches via POST /api/branches.
+func TestHandleCreateBranch(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	server := New(4242, provider)
+
+	body, _ := json.Marshal(CreateBranchRequest{Name: "topic/intro"})
+	req := httptest.NewRequest(http.MethodPost, "/api/branches", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var branch git.BranchInfo
+	if err := json.NewDecoder(rec.Body).Decode(&branch); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if branch.Name != "topic/intro" {
+		t.Errorf("expected branch topic/intro, got %q", branch.Name)
+	}
+
+	// The new branch is listed
+	branches, err := provider.Branches()
+	if err != nil {
+		t.Fatalf("Branches failed: %v", err)
+	}
+	found := false
+	for _, b := range branches {
+		if b.Name == "topic/intro" && !b.IsDefault {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected non-default branch topic/intro in %+v", branches)
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"invalid body", "not json", http.StatusBadRequest},
+		{"empty name", `{"name":""}`, http.StatusBadRequest},
+		{"invalid name", `{"name":"bad..name"}`, http.StatusBadRequest},
+		{"already exists", `{"name":"topic/intro"}`, http.StatusConflict},
+		{"unknown start", `{"name":"other","from":"nonexistent"}`, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/branches", bytes.NewReader([]byte(tt.body)))
+			rec := httptest.NewRecorder()
+			server.mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+// TestHandleDeleteBranch tests deleting branches via DELETE /api/branches/{name}.
+func TestHandleDeleteBranch(t *testing.T) {
+	tempDir := t.TempDir()
+	repo := createTestRepoWithCommit(t, tempDir)
+
+	headRef, err := repo.Head()
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v", err)
+	}
+	currentBranch := headRef.Name().Short()
+
+	// "topic/merged" points at HEAD; "unmerged" has a commit of its own
+	mergedRef := plumbing.NewHashReference(plumbing.NewBranchReferenceName("topic/merged"), headRef.Hash())
+	if err := repo.Storer.SetReference(mergedRef); err != nil {
+		t.Fatalf("failed to create branch: %v", err)
+	}
+
+	w, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	if err := w.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("unmerged"), Create: true}); err != nil {
+		t.Fatalf("failed to create branch: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "draft.md"), []byte("draft"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if _, err := w.Add("draft.md"); err != nil {
+		t.Fatalf("failed to add file: %v", err)
+	}
+	if _, err := w.Commit("Draft", testCommitOptions()); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	if err := w.Checkout(&gogit.CheckoutOptions{Branch: headRef.Name()}); err != nil {
+		t.Fatalf("failed to check out: %v", err)
+	}
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	server := New(4242, provider)
+
+	tests := []struct {
+		name       string
+		url        string
+		wantStatus int
+	}{
+		{"merged branch with slash", "/api/branches/topic/merged", http.StatusOK},
+		{"already deleted", "/api/branches/topic/merged", http.StatusNotFound},
+		{"current branch", "/api/branches/" + currentBranch, http.StatusConflict},
+		{"unmerged branch", "/api/branches/unmerged", http.StatusConflict},
+		{"invalid force", "/api/branches/unmerged?force=maybe", http.StatusBadRequest},
+		{"unmerged branch forced", "/api/branches/unmerged?force=true", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, tt.url, nil)
+			rec := httptest.NewRecorder()
+			server.mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	branches, err := provider.Branches()
+	if err != nil {
+		t.Fatalf("Branches failed: %v", err)
+	}
+	if len(branches) != 1 || branches[0].Name != currentBranch {
+		t.Errorf("expected only %s to remain, got %+v", currentBranch, branches)
+	}
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/buckleypaul/giki/internal/git"
+)
+
+// ChangeSetRequest represents the JSON payload for POST /api/changeset
+type ChangeSetRequest struct {
+	Operations []git.ChangeOp `json:"operations"`
+	Message    string         `json:"message,omitempty"` // commit the changes when set
+}
+
+// ChangeSetResponse represents the response from POST /api/changeset
+type ChangeSetResponse struct {
+	Success bool   `json:"success"`
+	Hash    string `json:"hash,omitempty"` // commit hash, when a message was given
+}
+
+// ChangeSetErrorResponse is returned when a change set is rejected or rolled back
+type ChangeSetErrorResponse struct {
+	Error string `json:"error"`
+	Index *int   `json:"index,omitempty"` // operation that failed, if any
+}
+
+// handleChangeSet handles POST /api/changeset requests.
+// Applies a batch of write, delete, move and move-folder operations atomically,
+// optionally committing them.
+func (s *Server) handleChangeSet(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var req ChangeSetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "invalid request body"})
+		return
+	}
+
+	// Apply the change set
+	hash, err := s.provider.ApplyChangeSet(req.Operations, strings.TrimSpace(req.Message))
+	if err != nil {
+		resp := ChangeSetErrorResponse{Error: err.Error()}
+		var opErr *git.ChangeSetError
+		if errors.As(err, &opErr) {
+			resp.Index = &opErr.Index
+		}
+
+		if writeConflict(w, err, resp.Index) {
+			return
+		}
+
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, git.ErrReadOnly):
+			status = http.StatusForbidden
+		case strings.Contains(err.Error(), "rollback failed"):
+			// The working tree may be partially modified; report it as a server error
+		case strings.Contains(err.Error(), "invalid"),
+			strings.Contains(err.Error(), "nothing to"),
+			strings.Contains(err.Error(), "cannot"),
+			strings.Contains(err.Error(), "is a directory"),
+			strings.Contains(err.Error(), "not a directory"):
+			status = http.StatusBadRequest
+		case strings.Contains(err.Error(), "not found"):
+			status = http.StatusNotFound
+		case strings.Contains(err.Error(), "already exists"),
+			strings.Contains(err.Error(), "is a file"):
+			status = http.StatusConflict
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	// Return success response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ChangeSetResponse{Success: true, Hash: hash})
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buckleypaul/giki/internal/git"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// postChangeSet sends a change set request through the server's mux.
+func postChangeSet(t *testing.T, server *Server, body []byte) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/changeset", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandleChangeSet(t *testing.T) {
+	// Create temp directory and git repo
+	tempDir := t.TempDir()
+	repo := createTestRepoWithCommit(t, tempDir)
+
+	if err := os.WriteFile(filepath.Join(tempDir, "old.md"), []byte("old"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	server := New(4242, provider)
+
+	bodyJSON, _ := json.Marshal(ChangeSetRequest{
+		Operations: []git.ChangeOp{
+			{Op: git.OpWrite, Path: "docs/page.md", Content: "# Page"},
+			{Op: git.OpMove, OldPath: "old.md", NewPath: "docs/old.md"},
+			{Op: git.OpMoveFolder, OldPath: "docs", NewPath: "guides"},
+		},
+		Message: "Add guides",
+	})
+
+	rec := postChangeSet(t, server, bodyJSON)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp ChangeSetResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !resp.Success || resp.Hash == "" {
+		t.Fatalf("expected success with a commit hash, got %+v", resp)
+	}
+
+	commitObj, err := repo.CommitObject(plumbing.NewHash(resp.Hash))
+	if err != nil {
+		t.Fatalf("commit not found in repository: %v", err)
+	}
+	for _, path := range []string{"guides/page.md", "guides/old.md"} {
+		if _, err := commitObj.File(path); err != nil {
+			t.Errorf("expected %s in commit: %v", path, err)
+		}
+	}
+}
+
+func TestHandleChangeSet_FailedOperation(t *testing.T) {
+	// Create temp directory and git repo
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	server := New(4242, provider)
+
+	bodyJSON, _ := json.Marshal(ChangeSetRequest{
+		Operations: []git.ChangeOp{
+			{Op: git.OpWrite, Path: "page.md", Content: "# Page"},
+			{Op: git.OpDelete, Path: "missing.md"},
+		},
+	})
+
+	rec := postChangeSet(t, server, bodyJSON)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp ChangeSetErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Index == nil || *resp.Index != 1 {
+		t.Errorf("expected failing operation index 1, got %+v", resp)
+	}
+
+	// The earlier write was not applied
+	if _, err := os.Stat(filepath.Join(tempDir, "page.md")); !os.IsNotExist(err) {
+		t.Errorf("expected page.md not to be written, got %v", err)
+	}
+}
+
+func TestHandleChangeSet_BadRequest(t *testing.T) {
+	// Create temp directory and git repo
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	server := New(4242, provider)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"invalid json", "invalid"},
+		{"no operations", `{"operations": []}`},
+		{"unknown operation", `{"operations": [{"op": "copy", "path": "a.md"}]}`},
+		{"path traversal", `{"operations": [{"op": "write", "path": "../a.md"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := postChangeSet(t, server, []byte(tt.body))
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d: %s", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleChangeSet_Conflict(t *testing.T) {
+	// Create temp directory and git repo
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	if err := os.WriteFile(filepath.Join(tempDir, "page.md"), []byte("edited elsewhere"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	server := New(4242, provider)
+
+	bodyJSON, _ := json.Marshal(ChangeSetRequest{
+		Operations: []git.ChangeOp{
+			{Op: git.OpWrite, Path: "other.md", Content: "other"},
+			{Op: git.OpWrite, Path: "page.md", Content: "mine", BaseHash: git.BlobHash([]byte("loaded"))},
+		},
+	})
+
+	rec := postChangeSet(t, server, bodyJSON)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp ConflictResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Index == nil || *resp.Index != 1 || resp.Path != "page.md" || resp.Content != "edited elsewhere" {
+		t.Errorf("unexpected conflict response %+v", resp)
+	}
+}
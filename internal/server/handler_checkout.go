@@ -0,0 +1,67 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// CheckoutRequest represents the JSON payload for POST /api/checkout
+type CheckoutRequest struct {
+	Branch string `json:"branch"`
+	Mode   string `json:"mode"` // "" (refuse if dirty), "stash", or "carry"
+}
+
+// handleCheckout handles POST /api/checkout requests.
+// Switches the working tree to another branch and returns the new repository status.
+func (s *Server) handleCheckout(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var req CheckoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "invalid request body"})
+		return
+	}
+
+	// Validate branch is not empty
+	if req.Branch == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "branch cannot be empty"})
+		return
+	}
+
+	// Switch branches
+	if err := s.provider.Checkout(req.Branch, req.Mode); err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case strings.Contains(err.Error(), "invalid checkout mode"):
+			status = http.StatusBadRequest
+		case strings.Contains(err.Error(), "not found"):
+			status = http.StatusNotFound
+		case strings.Contains(err.Error(), "uncommitted changes"),
+			strings.Contains(err.Error(), "conflict"),
+			strings.Contains(err.Error(), "ambiguous"),
+			strings.Contains(err.Error(), "detached HEAD"):
+			status = http.StatusConflict
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	// Return the new status
+	repoStatus, err := s.provider.Status()
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(repoStatus)
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/buckleypaul/giki/internal/git"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// createCheckoutTestServer creates a repository with a second branch "feature"
+// pointing at the initial commit and returns a server backed by it.
+func createCheckoutTestServer(t *testing.T) (*Server, git.GitProvider) {
+	tempDir := t.TempDir()
+	repo := createTestRepoWithCommit(t, tempDir)
+
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v", err)
+	}
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("feature"), head.Hash())); err != nil {
+		t.Fatalf("failed to create branch: %v", err)
+	}
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	return New(4242, provider), provider
+}
+
+func TestHandleCheckout_SwitchBranch(t *testing.T) {
+	server, _ := createCheckoutTestServer(t)
+
+	body, _ := json.Marshal(CheckoutRequest{Branch: "feature"})
+	req := httptest.NewRequest(http.MethodPost, "/api/checkout", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var status git.RepoStatus
+	if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if status.Branch != "feature" {
+		t.Errorf("expected branch feature, got %s", status.Branch)
+	}
+
+	// The status endpoint reports the new branch too
+	req = httptest.NewRequest(http.MethodGet, "/api/status", nil)
+	rec = httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+
+	if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+		t.Fatalf("failed to decode status: %v", err)
+	}
+	if status.Branch != "feature" {
+		t.Errorf("expected /api/status to report feature, got %s", status.Branch)
+	}
+}
+
+func TestHandleCheckout_DirtyTree(t *testing.T) {
+	server, provider := createCheckoutTestServer(t)
+
+	if err := provider.WriteFile("notes.md", []byte("unsaved")); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	// Refused without a mode
+	body, _ := json.Marshal(CheckoutRequest{Branch: "feature"})
+	req := httptest.NewRequest(http.MethodPost, "/api/checkout", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// Allowed when carrying the changes
+	body, _ = json.Marshal(CheckoutRequest{Branch: "feature", Mode: git.CheckoutCarry})
+	req = httptest.NewRequest(http.MethodPost, "/api/checkout", bytes.NewReader(body))
+	rec = httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var status git.RepoStatus
+	if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if status.Branch != "feature" || !status.IsDirty {
+		t.Errorf("expected dirty tree on feature, got %+v", status)
+	}
+}
+
+func TestHandleCheckout_Errors(t *testing.T) {
+	server, _ := createCheckoutTestServer(t)
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"invalid body", "not json", http.StatusBadRequest},
+		{"empty branch", `{"branch":""}`, http.StatusBadRequest},
+		{"invalid mode", `{"branch":"feature","mode":"merge"}`, http.StatusBadRequest},
+		{"unknown branch", `{"branch":"nonexistent"}`, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/checkout", bytes.NewReader([]byte(tt.body)))
+			rec := httptest.NewRecorder()
+			server.mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+
+			var resp ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode error response: %v", err)
+			}
+			if resp.Error == "" {
+				t.Error("expected error message in response")
+			}
+		})
+	}
+}
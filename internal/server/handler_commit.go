@@ -2,21 +2,31 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
+
+	"github.com/buckleypaul/giki/internal/git"
 )
 
 // CommitRequest represents the JSON payload for POST /api/commit
 type CommitRequest struct {
-	Message string `json:"message"`
+	Message   string         `json:"message"`
+	Author    *git.Identity  `json:"author,omitempty"`    // overrides the configured author
+	CoAuthors []git.Identity `json:"coAuthors,omitempty"` // added as Co-authored-by trailers
+	Paths     []string       `json:"paths,omitempty"`     // commit only these files/folders; empty = everything
+	Amend     bool           `json:"amend,omitempty"`     // replace the last commit; message may be empty to keep it
 }
 
 // CommitResponse represents the response from POST /api/commit
 type CommitResponse struct {
-	Hash string `json:"hash"`
+	Hash      string            `json:"hash"`
+	Signature git.SignatureInfo `json:"signature"`
 }
 
 // handleCommit handles POST /api/commit requests.
-// Creates a git commit with all staged and unstaged changes.
+// Creates a git commit with all staged and unstaged changes,
+// or only with the changes to the requested paths.
 func (s *Server) handleCommit(w http.ResponseWriter, r *http.Request) {
 	// Parse request body
 	var req CommitRequest
@@ -27,8 +37,8 @@ func (s *Server) handleCommit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate message is not empty
-	if req.Message == "" {
+	// Validate message is not empty (amending may keep the previous message)
+	if req.Message == "" && !req.Amend {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(ErrorResponse{Error: "commit message cannot be empty"})
@@ -36,15 +46,39 @@ func (s *Server) handleCommit(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create commit
-	hash, err := s.provider.Commit(req.Message)
+	hash, err := s.provider.CommitWithOptions(git.CommitOptions{
+		Message:   req.Message,
+		Author:    req.Author,
+		CoAuthors: req.CoAuthors,
+		Paths:     req.Paths,
+		Amend:     req.Amend,
+	})
 	if err != nil {
+		status := http.StatusInternalServerError
+		var commitErr *git.CommitError
+		switch {
+		case errors.Is(err, git.ErrReadOnly):
+			status = http.StatusForbidden
+		case errors.Is(err, git.ErrPathNotFound):
+			status = http.StatusNotFound
+		case errors.As(err, &commitErr):
+			status = http.StatusBadRequest
+		}
+
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(status)
 		json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
 		return
 	}
 
+	// Report whether the new commit was signed. The commit exists either way,
+	// so failing to check the signature must not turn this into an error.
+	signature, err := s.provider.CommitSignature(hash)
+	if err != nil {
+		log.Printf("Error checking signature of commit %s: %v", hash, err)
+	}
+
 	// Return commit hash
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(CommitResponse{Hash: hash})
+	json.NewEncoder(w).Encode(CommitResponse{Hash: hash, Signature: signature})
 }
@@ -2,7 +2,11 @@ package server
 
 import (
 	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
 	"encoding/json"
+	"encoding/pem"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -12,6 +16,7 @@ import (
 	"github.com/buckleypaul/giki/internal/git"
 	gogit "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"golang.org/x/crypto/ssh"
 )
 
 
@@ -264,3 +269,311 @@ func TestHandleCommit_IntegrationFlow(t *testing.T) {
 		t.Error("expected clean repository after commit, got isDirty: true")
 	}
 }
+
+// TestHandleCommit_AuthorAndCoAuthors tests the author override and Co-authored-by trailers.
+func TestHandleCommit_AuthorAndCoAuthors(t *testing.T) {
+	// Create temp directory and git repo
+	tempDir := t.TempDir()
+	repo := createTestRepoWithCommit(t, tempDir)
+
+	// Create provider with a configured default author
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	provider.SetDefaultAuthor("Config User", "config@example.com")
+
+	if err := provider.WriteFile("test.md", []byte("content")); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	server := New(4242, provider)
+
+	// Override the author and add a co-author
+	reqBody := CommitRequest{
+		Message:   "Pair on docs",
+		Author:    &git.Identity{Name: "Request User", Email: "request@example.com"},
+		CoAuthors: []git.Identity{{Name: "Grace Hopper", Email: "grace@example.com"}},
+	}
+	bodyJSON, _ := json.Marshal(reqBody)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/commit", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	server.handleCommit(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp CommitResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	commitObj, err := repo.CommitObject(plumbing.NewHash(resp.Hash))
+	if err != nil {
+		t.Fatalf("commit not found in repository: %v", err)
+	}
+
+	if commitObj.Author.Name != "Request User" || commitObj.Author.Email != "request@example.com" {
+		t.Errorf("expected request author, got %s <%s>", commitObj.Author.Name, commitObj.Author.Email)
+	}
+	expected := "Pair on docs\n\nCo-authored-by: Grace Hopper <grace@example.com>"
+	if commitObj.Message != expected {
+		t.Errorf("expected commit message %q, got %q", expected, commitObj.Message)
+	}
+}
+
+// TestHandleCommit_InvalidAuthor tests that a malformed author returns 400.
+func TestHandleCommit_InvalidAuthor(t *testing.T) {
+	// Create temp directory and git repo
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	server := New(4242, provider)
+
+	reqBody := CommitRequest{
+		Message: "Test commit",
+		Author:  &git.Identity{Name: "Evil <x>", Email: "evil@example.com"},
+	}
+	bodyJSON, _ := json.Marshal(reqBody)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/commit", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	server.handleCommit(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+// TestHandleCommit_SelectedPaths tests that only the requested paths are committed.
+func TestHandleCommit_SelectedPaths(t *testing.T) {
+	// Create temp directory and git repo
+	tempDir := t.TempDir()
+	repo := createTestRepoWithCommit(t, tempDir)
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	if err := provider.WriteFile("page.md", []byte("page")); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := provider.WriteFile("scratch.md", []byte("scratch")); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	server := New(4242, provider)
+
+	reqBody := CommitRequest{Message: "Add page", Paths: []string{"page.md"}}
+	bodyJSON, _ := json.Marshal(reqBody)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/commit", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	server.handleCommit(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp CommitResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	commitObj, err := repo.CommitObject(plumbing.NewHash(resp.Hash))
+	if err != nil {
+		t.Fatalf("commit not found in repository: %v", err)
+	}
+	if _, err := commitObj.File("page.md"); err != nil {
+		t.Errorf("expected page.md in commit: %v", err)
+	}
+	if _, err := commitObj.File("scratch.md"); err == nil {
+		t.Error("expected scratch.md to be left out of the commit")
+	}
+
+	// The scratch file is still an uncommitted change
+	status, err := provider.Status()
+	if err != nil {
+		t.Fatalf("failed to get status: %v", err)
+	}
+	if !status.IsDirty {
+		t.Error("expected working tree to remain dirty")
+	}
+
+	// Unknown paths are reported as not found
+	reqBody = CommitRequest{Message: "Add missing", Paths: []string{"missing.md"}}
+	bodyJSON, _ = json.Marshal(reqBody)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/commit", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+
+	server.handleCommit(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+// TestHandleCommit_Amend tests rewording the last commit.
+func TestHandleCommit_Amend(t *testing.T) {
+	// Create temp directory and git repo
+	tempDir := t.TempDir()
+	repo := createTestRepoWithCommit(t, tempDir)
+
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v", err)
+	}
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	server := New(4242, provider)
+
+	reqBody := CommitRequest{Message: "Reworded", Amend: true}
+	bodyJSON, _ := json.Marshal(reqBody)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/commit", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	server.handleCommit(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp CommitResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if resp.Hash == head.Hash().String() {
+		t.Fatal("expected amend to create a new commit")
+	}
+
+	commitObj, err := repo.CommitObject(plumbing.NewHash(resp.Hash))
+	if err != nil {
+		t.Fatalf("commit not found in repository: %v", err)
+	}
+	if commitObj.Message != "Reworded" {
+		t.Errorf("expected reworded message, got %q", commitObj.Message)
+	}
+	if commitObj.NumParents() != 0 {
+		t.Errorf("expected amended root commit to have no parents, got %d", commitObj.NumParents())
+	}
+}
+
+// TestHandleCommit_Signed tests that commits are signed with the configured key
+// and that the response reports the signature.
+func TestHandleCommit_Signed(t *testing.T) {
+	// Create temp directory and git repo
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	// Generate a throwaway SSH signing key
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	if err := provider.SetSigningKey(keyPath, git.SigningFormatSSH, ""); err != nil {
+		t.Fatalf("failed to set signing key: %v", err)
+	}
+	if err := provider.WriteFile("test.md", []byte("content")); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	server := New(4242, provider)
+
+	bodyJSON, _ := json.Marshal(CommitRequest{Message: "Signed commit"})
+	req := httptest.NewRequest(http.MethodPost, "/api/commit", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	server.handleCommit(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp CommitResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if resp.Signature.Status != git.SignatureGood || resp.Signature.Format != git.SigningFormatSSH {
+		t.Errorf("expected good ssh signature, got %+v", resp.Signature)
+	}
+}
+
+// failingSignatureProvider is a provider whose signature checks always fail.
+type failingSignatureProvider struct {
+	git.GitProvider
+}
+
+func (p failingSignatureProvider) CommitSignature(hash string) (git.SignatureInfo, error) {
+	return git.SignatureInfo{}, errors.New("signature check failed")
+}
+
+func TestHandleCommit_SignatureCheckFails(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	if err := provider.WriteFile("test.md", []byte("content")); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	server := New(4242, failingSignatureProvider{provider})
+
+	bodyJSON, _ := json.Marshal(CommitRequest{Message: "Commit"})
+	req := httptest.NewRequest(http.MethodPost, "/api/commit", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	server.handleCommit(rec, req)
+
+	// The commit was made, so its hash is still returned
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp CommitResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Hash == "" {
+		t.Error("expected commit hash in response")
+	}
+}
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// handleCompare handles GET /api/compare?base=<rev>&head=<rev>
+// Returns the files changed on head since it diverged from base, with stats and patches.
+func (s *Server) handleCompare(w http.ResponseWriter, r *http.Request) {
+	base := r.URL.Query().Get("base")
+	head := r.URL.Query().Get("head")
+
+	// Validate both revisions are provided
+	if base == "" || head == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "base and head are required"})
+		return
+	}
+
+	comparison, err := s.provider.Compare(base, head)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	// Return as JSON
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(comparison); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
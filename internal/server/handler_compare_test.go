@@ -0,0 +1,108 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buckleypaul/giki/internal/git"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestHandleCompare(t *testing.T) {
+	tempDir := t.TempDir()
+	repo := createTestRepoWithCommit(t, tempDir)
+
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v", err)
+	}
+
+	w, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+
+	// Add a page on a develop branch, then return to the default branch
+	if err := w.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("develop"), Create: true}); err != nil {
+		t.Fatalf("failed to create develop: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "page.md"), []byte("draft\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if _, err := w.Add("page.md"); err != nil {
+		t.Fatalf("failed to add file: %v", err)
+	}
+	if _, err := w.Commit("Add page", &gogit.CommitOptions{Author: testSignature()}); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	if err := w.Checkout(&gogit.CheckoutOptions{Branch: head.Name()}); err != nil {
+		t.Fatalf("failed to check out default branch: %v", err)
+	}
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	server := New(4242, provider)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/compare?base="+head.Name().Short()+"&head=develop", nil)
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var comparison git.Comparison
+	if err := json.NewDecoder(rec.Body).Decode(&comparison); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(comparison.Files) != 1 {
+		t.Fatalf("expected 1 changed file, got %d", len(comparison.Files))
+	}
+	if f := comparison.Files[0]; f.Path != "page.md" || f.Status != git.FileAdded || f.Additions != 1 {
+		t.Errorf("unexpected file diff: %+v", f)
+	}
+	if comparison.Additions != 1 {
+		t.Errorf("expected 1 total addition, got %d", comparison.Additions)
+	}
+}
+
+func TestHandleCompare_Errors(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	server := New(4242, provider)
+
+	tests := []struct {
+		name       string
+		url        string
+		wantStatus int
+	}{
+		{"missing head", "/api/compare?base=HEAD", http.StatusBadRequest},
+		{"missing base", "/api/compare?head=HEAD", http.StatusBadRequest},
+		{"unknown revision", "/api/compare?base=HEAD&head=nonexistent", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			rec := httptest.NewRecorder()
+			server.mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// handleDiff handles GET /api/diff requests.
+// Returns a per-file unified diff of the working tree against HEAD.
+func (s *Server) handleDiff(w http.ResponseWriter, r *http.Request) {
+	diffs, err := s.provider.Diff()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Return as JSON
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(diffs); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
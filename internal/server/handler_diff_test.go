@@ -0,0 +1,74 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/buckleypaul/giki/internal/git"
+)
+
+func TestHandleDiff(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	// Edit made on disk, outside the browser
+	if err := os.WriteFile(filepath.Join(tempDir, "notes.md"), []byte("# Notes\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	server := New(4242, provider)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/diff", nil)
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var diffs []git.FileDiff
+	if err := json.NewDecoder(rec.Body).Decode(&diffs); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(diffs) != 1 {
+		t.Fatalf("expected 1 changed file, got %d", len(diffs))
+	}
+	if diffs[0].Path != "notes.md" || diffs[0].Status != git.FileAdded {
+		t.Errorf("expected notes.md added, got %s %s", diffs[0].Path, diffs[0].Status)
+	}
+	if !strings.Contains(diffs[0].Patch, "+# Notes") {
+		t.Errorf("expected patch to contain added line, got:\n%s", diffs[0].Patch)
+	}
+}
+
+func TestHandleDiff_Clean(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	server := New(4242, provider)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/diff", nil)
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if body := strings.TrimSpace(rec.Body.String()); body != "[]" {
+		t.Errorf("expected empty array, got %s", body)
+	}
+}
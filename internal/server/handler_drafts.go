@@ -0,0 +1,239 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/buckleypaul/giki/internal/drafts"
+	"github.com/buckleypaul/giki/internal/git"
+)
+
+// DraftRequest represents the JSON payload for PUT /api/drafts
+type DraftRequest struct {
+	Path     string `json:"path"`
+	Branch   string `json:"branch,omitempty"` // empty means the current branch
+	Content  string `json:"content"`
+	BaseHash string `json:"baseHash,omitempty"` // ETag of the version being edited
+}
+
+// DraftInfo is a saved draft along with whether the file has changed underneath it
+type DraftInfo struct {
+	drafts.Draft
+	Stale       bool   `json:"stale"`                 // the file no longer matches the draft's base
+	CurrentHash string `json:"currentHash,omitempty"` // empty if the file does not exist
+}
+
+// newDraftStore returns the drafts store for the provider's repository,
+// or nil if its drafts directory cannot be determined.
+func newDraftStore(provider git.GitProvider) *drafts.Store {
+	status, err := provider.Status()
+	if err != nil {
+		log.Printf("Drafts disabled: %v", err)
+		return nil
+	}
+
+	dir, err := drafts.DefaultDir(status.Source)
+	if err != nil {
+		log.Printf("Drafts disabled: %v", err)
+		return nil
+	}
+
+	return drafts.NewStore(dir)
+}
+
+// handleGetDrafts handles GET /api/drafts?branch=<branch>&path=<path>
+// Returns the drafts saved for a branch (default: current branch), or the
+// single draft for path, each marked stale if the file changed since the
+// draft was started.
+func (s *Server) handleGetDrafts(w http.ResponseWriter, r *http.Request) {
+	store, branch, ok := s.draftsFor(w, r.URL.Query().Get("branch"))
+	if !ok {
+		return
+	}
+
+	// A single draft
+	if rawPath := r.URL.Query().Get("path"); rawPath != "" {
+		path, ok := draftPath(w, rawPath)
+		if !ok {
+			return
+		}
+
+		draft, err := store.Get(branch, path)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, drafts.ErrNotFound) {
+				status = http.StatusNotFound
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		info, err := s.draftInfo(*draft)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(info)
+		return
+	}
+
+	// Every draft on the branch
+	list, err := store.List(branch)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	infos := make([]DraftInfo, 0, len(list))
+	for _, draft := range list {
+		info, err := s.draftInfo(draft)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		infos = append(infos, info)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(infos); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// handlePutDraft handles PUT /api/drafts requests.
+// Saves the unsaved content of a file, replacing any earlier draft for it.
+func (s *Server) handlePutDraft(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var req DraftRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "invalid request body"})
+		return
+	}
+
+	path, ok := draftPath(w, req.Path)
+	if !ok {
+		return
+	}
+
+	store, branch, ok := s.draftsFor(w, req.Branch)
+	if !ok {
+		return
+	}
+
+	draft := drafts.Draft{
+		Path:     path,
+		Branch:   branch,
+		Content:  req.Content,
+		BaseHash: parseETag(req.BaseHash),
+		Updated:  time.Now().UTC(),
+	}
+	if err := store.Put(draft); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	info, err := s.draftInfo(draft)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(info)
+}
+
+// handleDeleteDraft handles DELETE /api/drafts?branch=<branch>&path=<path>
+// Discards the draft for a file, e.g. once it has been saved.
+func (s *Server) handleDeleteDraft(w http.ResponseWriter, r *http.Request) {
+	path, ok := draftPath(w, r.URL.Query().Get("path"))
+	if !ok {
+		return
+	}
+
+	store, branch, ok := s.draftsFor(w, r.URL.Query().Get("branch"))
+	if !ok {
+		return
+	}
+
+	if err := store.Delete(branch, path); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(SuccessResponse{Success: true})
+}
+
+// draftsFor returns the drafts store and the branch a request refers to,
+// resolving an empty branch to the current one. On failure it writes the
+// error response and returns false.
+func (s *Server) draftsFor(w http.ResponseWriter, branch string) (*drafts.Store, string, bool) {
+	if s.drafts == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "drafts are not available"})
+		return nil, "", false
+	}
+
+	if branch == "" {
+		status, err := s.provider.Status()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return nil, "", false
+		}
+		branch = status.Branch
+	}
+
+	return s.drafts, branch, true
+}
+
+// draftPath normalizes a draft's file path. On failure it writes a 400
+// response and returns false.
+func draftPath(w http.ResponseWriter, path string) (string, bool) {
+	path = filepath.ToSlash(strings.Trim(path, "/"))
+
+	var msg string
+	switch {
+	case path == "":
+		msg = "path cannot be empty"
+	case strings.Contains(path, ".."):
+		msg = "invalid path: cannot contain '..'"
+	default:
+		return path, true
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(ErrorResponse{Error: msg})
+	return "", false
+}
+
+// draftInfo compares a draft's base hash with the file as it is now on the
+// draft's branch. A draft for a new file is stale once the file exists.
+func (s *Server) draftInfo(draft drafts.Draft) (DraftInfo, error) {
+	info := DraftInfo{Draft: draft}
+
+	content, err := s.provider.FileContent(draft.Path, draft.Branch)
+	if err != nil {
+		if !strings.Contains(err.Error(), "file not found") && !strings.Contains(err.Error(), "path is a directory") {
+			return info, err
+		}
+	} else {
+		info.CurrentHash = git.BlobHash(content)
+	}
+
+	info.Stale = info.CurrentHash != draft.BaseHash
+	return info, nil
+}
@@ -0,0 +1,199 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buckleypaul/giki/internal/drafts"
+	"github.com/buckleypaul/giki/internal/git"
+)
+
+// newDraftsTestServer creates a server for a repository with page.md
+// committed, keeping its drafts in a temporary directory.
+func newDraftsTestServer(t *testing.T) (string, *Server) {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	if err := os.WriteFile(filepath.Join(tempDir, "page.md"), []byte("original"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	server := New(4242, provider)
+	server.drafts = drafts.NewStore(t.TempDir())
+
+	return tempDir, server
+}
+
+// serveDrafts sends a request to /api/drafts through the server's mux.
+func serveDrafts(t *testing.T, server *Server, method, query string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var reader *bytes.Reader
+	if body != nil {
+		bodyJSON, _ := json.Marshal(body)
+		reader = bytes.NewReader(bodyJSON)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req := httptest.NewRequest(method, "/api/drafts"+query, reader)
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandleDrafts(t *testing.T) {
+	_, server := newDraftsTestServer(t)
+
+	// Save a draft of page.md based on its current content
+	rec := serveDrafts(t, server, http.MethodPut, "", DraftRequest{
+		Path:     "/page.md",
+		Content:  "edited",
+		BaseHash: formatETag(git.BlobHash([]byte("original"))),
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var saved DraftInfo
+	if err := json.NewDecoder(rec.Body).Decode(&saved); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if saved.Path != "page.md" || saved.Branch == "" || saved.Content != "edited" || saved.Stale {
+		t.Errorf("unexpected saved draft %+v", saved)
+	}
+
+	// The draft is listed for the current branch
+	rec = serveDrafts(t, server, http.MethodGet, "", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var list []DraftInfo
+	if err := json.NewDecoder(rec.Body).Decode(&list); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(list) != 1 || list[0].Path != "page.md" {
+		t.Fatalf("expected one draft, got %+v", list)
+	}
+
+	// ...but not for another branch
+	rec = serveDrafts(t, server, http.MethodGet, "?branch=other", nil)
+	list = nil
+	json.NewDecoder(rec.Body).Decode(&list)
+	if len(list) != 0 {
+		t.Errorf("expected no drafts on another branch, got %+v", list)
+	}
+
+	// Fetch it by path
+	rec = serveDrafts(t, server, http.MethodGet, "?path=page.md", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// Discard it
+	rec = serveDrafts(t, server, http.MethodDelete, "?path=page.md", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	rec = serveDrafts(t, server, http.MethodGet, "?path=page.md", nil)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 after delete, got %d", rec.Code)
+	}
+}
+
+func TestHandleDrafts_Stale(t *testing.T) {
+	tempDir, server := newDraftsTestServer(t)
+
+	for _, req := range []DraftRequest{
+		{Path: "page.md", Content: "edited", BaseHash: git.BlobHash([]byte("original"))},
+		{Path: "new.md", Content: "new"},
+	} {
+		if rec := serveDrafts(t, server, http.MethodPut, "", req); rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+
+	// The files change on disk after the drafts were saved
+	if err := os.WriteFile(filepath.Join(tempDir, "page.md"), []byte("changed on disk"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "new.md"), []byte("created elsewhere"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	rec := serveDrafts(t, server, http.MethodGet, "", nil)
+	var list []DraftInfo
+	if err := json.NewDecoder(rec.Body).Decode(&list); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("expected two drafts, got %+v", list)
+	}
+	for _, info := range list {
+		if !info.Stale {
+			t.Errorf("expected %s to be stale", info.Path)
+		}
+	}
+	if list[1].Path != "page.md" || list[1].CurrentHash != git.BlobHash([]byte("changed on disk")) {
+		t.Errorf("unexpected current hash for page.md %+v", list[1])
+	}
+
+	// A deleted file also makes its draft stale
+	if err := os.Remove(filepath.Join(tempDir, "page.md")); err != nil {
+		t.Fatalf("failed to remove file: %v", err)
+	}
+	rec = serveDrafts(t, server, http.MethodGet, "?path=page.md", nil)
+	var info DraftInfo
+	if err := json.NewDecoder(rec.Body).Decode(&info); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !info.Stale || info.CurrentHash != "" {
+		t.Errorf("expected stale draft of deleted file, got %+v", info)
+	}
+}
+
+func TestHandleDrafts_Errors(t *testing.T) {
+	_, server := newDraftsTestServer(t)
+
+	tests := []struct {
+		name     string
+		method   string
+		query    string
+		body     any
+		expected int
+	}{
+		{"put invalid body", http.MethodPut, "", "invalid", http.StatusBadRequest},
+		{"put empty path", http.MethodPut, "", DraftRequest{Content: "x"}, http.StatusBadRequest},
+		{"put path traversal", http.MethodPut, "", DraftRequest{Path: "../x.md"}, http.StatusBadRequest},
+		{"get missing draft", http.MethodGet, "?path=missing.md", nil, http.StatusNotFound},
+		{"delete without path", http.MethodDelete, "", nil, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveDrafts(t, server, tt.method, tt.query, tt.body)
+			if rec.Code != tt.expected {
+				t.Errorf("expected status %d, got %d: %s", tt.expected, rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	// Without a drafts directory the endpoints are unavailable
+	server.drafts = nil
+	rec := serveDrafts(t, server, http.MethodGet, "", nil)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"net/http"
+	"time"
+)
+
+// eventsKeepAlive is how often a comment is sent to keep idle connections open.
+const eventsKeepAlive = 15 * time.Second
+
+// handleEvents handles GET /api/events requests.
+// Streams working tree change notifications as Server-Sent Events:
+// tree-changed, file-changed, and status-changed.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if s.watcher == nil {
+		http.Error(w, "file watching is not supported for this repository", http.StatusNotImplemented)
+		return
+	}
+
+	events, unsubscribe := s.watcher.subscribe()
+	defer unsubscribe()
+
+	stream, err := newSSEStream(w)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	keepAlive := time.NewTicker(eventsKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-events:
+			if err := stream.send(event.Type, event); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if err := stream.comment("keep-alive"); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/buckleypaul/giki/internal/git"
+)
+
+// readSSEEvent reads the next named event from an SSE stream, skipping comments.
+func readSSEEvent(t *testing.T, reader *bufio.Reader) (string, WatchEvent) {
+	t.Helper()
+
+	var name, data string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("failed to read event stream: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+
+		switch {
+		case line == "":
+			if name != "" {
+				var event WatchEvent
+				if err := json.Unmarshal([]byte(data), &event); err != nil {
+					t.Fatalf("failed to decode event data %q: %v", data, err)
+				}
+				return name, event
+			}
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestHandleEvents_StreamsChanges(t *testing.T) {
+	tempDir := t.TempDir()
+	repo := createTestRepoWithCommit(t, tempDir)
+
+	// Commit the ignore rules so the tree starts clean
+	if err := os.WriteFile(filepath.Join(tempDir, ".gitignore"), []byte("*.log\n"), 0644); err != nil {
+		t.Fatalf("failed to write .gitignore: %v", err)
+	}
+	w, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	if _, err := w.Add(".gitignore"); err != nil {
+		t.Fatalf("failed to add .gitignore: %v", err)
+	}
+	if _, err := w.Commit("Add .gitignore", testCommitOptions()); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	server := New(4242, provider)
+	server.watcher.interval = 20 * time.Millisecond
+	server.watcher.debounce = 40 * time.Millisecond
+
+	ts := httptest.NewServer(server.mux)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/api/events", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Errorf("expected Content-Type text/event-stream, got %s", contentType)
+	}
+
+	// Ignored files must not produce events; the new markdown file must
+	if err := os.WriteFile(filepath.Join(tempDir, "debug.log"), []byte("ignored"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "new.md"), []byte("# New\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+
+	name, event := readSSEEvent(t, reader)
+	if name != EventTreeChanged {
+		t.Fatalf("expected %s event, got %s", EventTreeChanged, name)
+	}
+	if len(event.Paths) != 1 || event.Paths[0] != "new.md" {
+		t.Errorf("expected paths [new.md], got %v", event.Paths)
+	}
+
+	name, event = readSSEEvent(t, reader)
+	if name != EventStatusChanged {
+		t.Fatalf("expected %s event, got %s", EventStatusChanged, name)
+	}
+	if event.Status == nil || !event.Status.IsDirty {
+		t.Errorf("expected dirty status, got %+v", event.Status)
+	}
+
+	// Modifying an existing file reports a file change
+	if err := os.WriteFile(filepath.Join(tempDir, "new.md"), []byte("# New\n\nMore content.\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	name, event = readSSEEvent(t, reader)
+	if name != EventFileChanged {
+		t.Fatalf("expected %s event, got %s", EventFileChanged, name)
+	}
+	if len(event.Paths) != 1 || event.Paths[0] != "new.md" {
+		t.Errorf("expected paths [new.md], got %v", event.Paths)
+	}
+}
+
+// nextWatchEvent waits for the next event from a watcher subscription.
+func nextWatchEvent(t *testing.T, events <-chan WatchEvent) WatchEvent {
+	t.Helper()
+
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for watcher event")
+		return WatchEvent{}
+	}
+}
+
+func TestWatcher_StatusChangesWithoutWorkingTreeChanges(t *testing.T) {
+	tempDir := t.TempDir()
+	repo := createTestRepoWithCommit(t, tempDir)
+
+	// Start with an uncommitted file so committing it only changes .git
+	if err := os.WriteFile(filepath.Join(tempDir, "new.md"), []byte("# New\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	w := newWatcher(provider)
+	w.interval = 20 * time.Millisecond
+	w.debounce = 40 * time.Millisecond
+
+	events, unsubscribe := w.subscribe()
+	defer unsubscribe()
+
+	// Committing updates the branch ref and the index, not the working tree
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	if _, err := worktree.Add("new.md"); err != nil {
+		t.Fatalf("failed to add file: %v", err)
+	}
+	if _, err := worktree.Commit("Add new.md", testCommitOptions()); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	event := nextWatchEvent(t, events)
+	if event.Type != EventStatusChanged {
+		t.Fatalf("expected %s event, got %s", EventStatusChanged, event.Type)
+	}
+	if event.Status == nil || event.Status.IsDirty {
+		t.Errorf("expected clean status after commit, got %+v", event.Status)
+	}
+
+}
+
+func TestWatcher_StopsWithoutSubscribers(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	w := newWatcher(provider)
+	if w == nil {
+		t.Fatal("expected watcher for local provider")
+	}
+
+	_, unsubscribeFirst := w.subscribe()
+	_, unsubscribeSecond := w.subscribe()
+
+	unsubscribeFirst()
+	if w.stop == nil {
+		t.Error("expected watcher to keep running while a subscriber remains")
+	}
+
+	unsubscribeSecond()
+	if w.stop != nil {
+		t.Error("expected watcher to stop after the last subscriber left")
+	}
+
+	// Unsubscribing twice is harmless
+	unsubscribeSecond()
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	now := time.Now()
+	prev := map[string]git.FileStamp{
+		"kept.md":    {ModTime: now, Size: 10},
+		"edited.md":  {ModTime: now, Size: 10},
+		"removed.md": {ModTime: now, Size: 10},
+	}
+	current := map[string]git.FileStamp{
+		"kept.md":   {ModTime: now, Size: 10},
+		"edited.md": {ModTime: now.Add(time.Second), Size: 10},
+		"added.md":  {ModTime: now, Size: 5},
+	}
+
+	treeChanged := make(map[string]struct{})
+	fileChanged := make(map[string]struct{})
+	if !diffSnapshots(prev, current, treeChanged, fileChanged) {
+		t.Fatal("expected changes to be detected")
+	}
+
+	if got := sortedKeys(treeChanged); len(got) != 2 || got[0] != "added.md" || got[1] != "removed.md" {
+		t.Errorf("expected tree changes [added.md removed.md], got %v", got)
+	}
+	if got := sortedKeys(fileChanged); len(got) != 1 || got[0] != "edited.md" {
+		t.Errorf("expected file changes [edited.md], got %v", got)
+	}
+
+	if diffSnapshots(current, current, treeChanged, fileChanged) {
+		t.Error("expected no changes between identical snapshots")
+	}
+}
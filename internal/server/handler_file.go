@@ -2,14 +2,20 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
+	"math"
 	"mime"
 	"net/http"
 	"path/filepath"
+	"strconv"
 	"strings"
+
+	"github.com/buckleypaul/giki/internal/git"
 )
 
 // handleFile handles GET /api/file/<path>?branch=<branch>
-// Returns raw file content with appropriate Content-Type header.
+// Returns raw file content with appropriate Content-Type header and the
+// file's blob hash as ETag, to be sent back as If-Match when editing.
 // Returns 404 JSON error for missing files.
 func (s *Server) handleFile(w http.ResponseWriter, r *http.Request) {
 	// Extract path from URL (everything after /api/file/)
@@ -22,6 +28,10 @@ func (s *Server) handleFile(w http.ResponseWriter, r *http.Request) {
 	// Get file content from provider
 	content, err := s.provider.FileContent(path, branch)
 	if err != nil {
+		if writeRateLimited(w, err) {
+			return
+		}
+
 		// Check if it's a "file not found" error
 		if strings.Contains(err.Error(), "file not found") || strings.Contains(err.Error(), "path is a directory") {
 			w.Header().Set("Content-Type", "application/json")
@@ -42,9 +52,47 @@ func (s *Server) handleFile(w http.ResponseWriter, r *http.Request) {
 
 	// Set headers and return content
 	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("ETag", formatETag(git.BlobHash(content)))
 	w.Write(content)
 }
 
+// writeRateLimited writes a 429 JSON error if err comes from a hosting API
+// whose rate limit is used up, with a Retry-After header when the API said
+// when the limit resets. Reports whether it wrote a response.
+func writeRateLimited(w http.ResponseWriter, err error) bool {
+	var rateErr *git.RateLimitError
+	if !errors.As(err, &rateErr) {
+		return false
+	}
+
+	if retry := rateErr.RetryAfter(); retry > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retry.Seconds()))))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusTooManyRequests)
+	json.NewEncoder(w).Encode(ErrorResponse{
+		Error: rateErr.Error() + ". Try again later, or configure an access token for this host to raise the limit.",
+	})
+	return true
+}
+
+// formatETag quotes a blob hash for use as an ETag header value.
+func formatETag(hash string) string {
+	return `"` + hash + `"`
+}
+
+// parseETag extracts the blob hash from an ETag header value.
+// Returns "" for an empty value or the "*" wildcard.
+func parseETag(value string) string {
+	value = strings.TrimSpace(value)
+	value = strings.TrimPrefix(value, "W/")
+	value = strings.Trim(value, `"`)
+	if value == "*" {
+		return ""
+	}
+	return value
+}
+
 // detectContentType determines the Content-Type for a file.
 // Uses mime.TypeByExtension first, falls back to http.DetectContentType.
 func detectContentType(path string, content []byte) string {
@@ -2,13 +2,16 @@ package server
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/buckleypaul/giki/internal/git"
 	gogit "github.com/go-git/go-git/v5"
@@ -72,6 +75,12 @@ func TestHandleFile_MarkdownFile(t *testing.T) {
 	if body != readmeContent {
 		t.Errorf("expected body %q, got %q", readmeContent, body)
 	}
+
+	// The ETag is the blob hash of the content
+	expectedETag := `"` + git.BlobHash([]byte(readmeContent)) + `"`
+	if etag := w2.Header().Get("ETag"); etag != expectedETag {
+		t.Errorf("expected ETag %s, got %q", expectedETag, etag)
+	}
 }
 
 // TestHandleFile_NonexistentFile tests that nonexistent files return 404.
@@ -381,3 +390,51 @@ func TestHandleFile_DirectoryRequest(t *testing.T) {
 		t.Errorf("expected status 404 for directory request, got %d", w2.Code)
 	}
 }
+
+// rateLimitedProvider is a provider whose hosting API refuses every read
+// because its rate limit is used up.
+type rateLimitedProvider struct {
+	git.GitProvider
+	reset time.Time
+}
+
+func (p *rateLimitedProvider) FileContent(path, branch string) ([]byte, error) {
+	return nil, fmt.Errorf("failed to read file: %w", &git.RateLimitError{Limit: 60, Reset: p.reset})
+}
+
+func (p *rateLimitedProvider) Tree(branch string) (*git.TreeNode, error) {
+	return nil, fmt.Errorf("failed to get tree: %w", &git.RateLimitError{Limit: 60, Reset: p.reset})
+}
+
+// TestHandleFile_RateLimited tests that an exhausted API rate limit is reported as 429.
+func TestHandleFile_RateLimited(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	local, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	server := New(4242, &rateLimitedProvider{GitProvider: local, reset: time.Now().Add(90 * time.Second)})
+
+	req := httptest.NewRequest("GET", "/api/file/README.md", nil)
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status 429, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	retryAfter, err := strconv.Atoi(rec.Header().Get("Retry-After"))
+	if err != nil || retryAfter < 80 || retryAfter > 90 {
+		t.Errorf("expected Retry-After of about 90 seconds, got %q", rec.Header().Get("Retry-After"))
+	}
+
+	var resp ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !strings.Contains(resp.Error, "rate limit exceeded") || !strings.Contains(resp.Error, "access token") {
+		t.Errorf("expected helpful rate limit message, got %q", resp.Error)
+	}
+}
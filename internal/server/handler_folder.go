@@ -0,0 +1,81 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/buckleypaul/giki/internal/git"
+)
+
+// CreateFolderRequest is the request body for POST /api/create-folder.
+type CreateFolderRequest struct {
+	Path string `json:"path"`
+}
+
+// DeleteFolderRequest is the request body for POST /api/delete-folder.
+type DeleteFolderRequest struct {
+	Path      string `json:"path"`
+	Recursive bool   `json:"recursive"` // also delete the folder's contents
+}
+
+// handleCreateFolder creates a folder at path, with a .gitkeep file.
+func (s *Server) handleCreateFolder(w http.ResponseWriter, r *http.Request) {
+	var req CreateFolderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Path == "" {
+		http.Error(w, "path is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.provider.CreateFolder(req.Path); err != nil {
+		http.Error(w, err.Error(), folderErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleDeleteFolder deletes the folder at path, and its contents if recursive is set.
+func (s *Server) handleDeleteFolder(w http.ResponseWriter, r *http.Request) {
+	var req DeleteFolderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Path == "" {
+		http.Error(w, "path is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.provider.DeleteFolder(req.Path, req.Recursive); err != nil {
+		http.Error(w, err.Error(), folderErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// folderErrorStatus maps a folder operation error to an HTTP status code.
+func folderErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, git.ErrReadOnly):
+		return http.StatusForbidden
+	case strings.Contains(err.Error(), "invalid path"),
+		strings.Contains(err.Error(), "cannot be empty"),
+		strings.Contains(err.Error(), "not a directory"):
+		return http.StatusBadRequest
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), "already exists"),
+		strings.Contains(err.Error(), "not empty"):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
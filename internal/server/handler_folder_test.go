@@ -0,0 +1,138 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buckleypaul/giki/internal/git"
+)
+
+func TestHandleCreateFolder(t *testing.T) {
+	// Create temp directory and git repo
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	// Create provider and server
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	server := New(4242, provider)
+
+	// Create request body
+	bodyJSON, _ := json.Marshal(CreateFolderRequest{Path: "docs/new"})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/create-folder", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+
+	// Check status code
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// Verify folder exists with a .gitkeep file
+	if _, err := os.Stat(filepath.Join(tempDir, "docs", "new", ".gitkeep")); err != nil {
+		t.Errorf("docs/new/.gitkeep not found: %v", err)
+	}
+
+	// Creating it again conflicts
+	req = httptest.NewRequest(http.MethodPost, "/api/create-folder", bytes.NewReader(bodyJSON))
+	rec = httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusConflict {
+		t.Errorf("expected status 409, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandleDeleteFolder(t *testing.T) {
+	// Create temp directory and git repo
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	// Create folder with a file
+	dir1 := filepath.Join(tempDir, "dir1")
+	if err := os.MkdirAll(dir1, 0755); err != nil {
+		t.Fatalf("failed to create dir1: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir1, "file.txt"), []byte("content"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	// Create provider and server
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	server := New(4242, provider)
+
+	// Without recursive the non-empty folder is kept
+	bodyJSON, _ := json.Marshal(DeleteFolderRequest{Path: "dir1"})
+	req := httptest.NewRequest(http.MethodPost, "/api/delete-folder", bytes.NewReader(bodyJSON))
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusConflict {
+		t.Errorf("expected status 409, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	bodyJSON, _ = json.Marshal(DeleteFolderRequest{Path: "dir1", Recursive: true})
+	req = httptest.NewRequest(http.MethodPost, "/api/delete-folder", bytes.NewReader(bodyJSON))
+	rec = httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// Verify folder deleted
+	if _, err := os.Stat(dir1); !os.IsNotExist(err) {
+		t.Error("dir1 should be deleted")
+	}
+}
+
+func TestHandleFolder_InvalidRequest(t *testing.T) {
+	// Create temp directory and git repo
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	// Create provider and server
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	server := New(4242, provider)
+
+	tests := []struct {
+		name     string
+		url      string
+		body     string
+		expected int
+	}{
+		{"create invalid json", "/api/create-folder", "invalid json", http.StatusBadRequest},
+		{"create empty path", "/api/create-folder", `{"path": ""}`, http.StatusBadRequest},
+		{"create path traversal", "/api/create-folder", `{"path": "../outside"}`, http.StatusBadRequest},
+		{"delete invalid json", "/api/delete-folder", "invalid json", http.StatusBadRequest},
+		{"delete empty path", "/api/delete-folder", `{"path": ""}`, http.StatusBadRequest},
+		{"delete missing folder", "/api/delete-folder", `{"path": "missing"}`, http.StatusNotFound},
+		{"delete repository root", "/api/delete-folder", `{"path": ".", "recursive": true}`, http.StatusBadRequest},
+		{"delete dot git", "/api/delete-folder", `{"path": "./.git", "recursive": true}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.url, bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			server.mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expected {
+				t.Errorf("expected status %d, got %d: %s", tt.expected, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
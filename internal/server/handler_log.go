@@ -0,0 +1,52 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// handleLog handles GET /api/log requests.
+// Query parameters:
+// - path: file or directory to filter history by (optional)
+// - branch: branch to read history from (defaults to current branch)
+// - limit: maximum number of commits to return (optional)
+// - cursor: nextCursor value from a previous page (optional)
+func (s *Server) handleLog(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit := 0
+	if limitParam := query.Get("limit"); limitParam != "" {
+		parsed, err := strconv.Atoi(limitParam)
+		if err != nil || parsed < 0 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: "invalid limit: must be a non-negative integer"})
+			return
+		}
+		limit = parsed
+	}
+
+	page, err := s.provider.Log(query.Get("path"), query.Get("branch"), limit, query.Get("cursor"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "invalid cursor") || strings.Contains(err.Error(), "invalid path") {
+			status = http.StatusBadRequest
+		} else if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	// Return as JSON
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(page); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
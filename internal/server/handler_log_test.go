@@ -0,0 +1,155 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buckleypaul/giki/internal/git"
+	gogit "github.com/go-git/go-git/v5"
+)
+
+// createLogTestServer creates a repository with two commits (README.md, then docs/guide.md)
+// and returns a server backed by it.
+func createLogTestServer(t *testing.T) *Server {
+	tempDir := t.TempDir()
+	repo := createTestRepoWithCommit(t, tempDir)
+
+	w, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+
+	for _, path := range []string{"README.md", "docs/guide.md"} {
+		fullPath := filepath.Join(tempDir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("failed to create directories: %v", err)
+		}
+		if err := os.WriteFile(fullPath, []byte("content of "+path), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		if _, err := w.Add(path); err != nil {
+			t.Fatalf("failed to add file: %v", err)
+		}
+		if _, err := w.Commit("Add "+path, &gogit.CommitOptions{Author: testSignature()}); err != nil {
+			t.Fatalf("failed to commit: %v", err)
+		}
+	}
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	return New(4242, provider)
+}
+
+func TestHandleLog_FilterByPath(t *testing.T) {
+	server := createLogTestServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/log?path=docs", nil)
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if contentType := rec.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("expected Content-Type application/json, got %s", contentType)
+	}
+
+	var page git.LogPage
+	if err := json.NewDecoder(rec.Body).Decode(&page); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(page.Commits) != 1 {
+		t.Fatalf("expected 1 commit touching docs, got %d", len(page.Commits))
+	}
+	if page.Commits[0].Message != "Add docs/guide.md" {
+		t.Errorf("unexpected commit message %q", page.Commits[0].Message)
+	}
+	if len(page.Commits[0].Paths) != 1 || page.Commits[0].Paths[0] != "docs/guide.md" {
+		t.Errorf("expected paths [docs/guide.md], got %v", page.Commits[0].Paths)
+	}
+}
+
+func TestHandleLog_Pagination(t *testing.T) {
+	server := createLogTestServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/log?limit=2", nil)
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var first git.LogPage
+	if err := json.NewDecoder(rec.Body).Decode(&first); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(first.Commits) != 2 {
+		t.Fatalf("expected 2 commits on first page, got %d", len(first.Commits))
+	}
+	if first.NextCursor == "" {
+		t.Fatal("expected a next cursor on first page")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/log?limit=2&cursor="+first.NextCursor, nil)
+	rec = httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+
+	var second git.LogPage
+	if err := json.NewDecoder(rec.Body).Decode(&second); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(second.Commits) != 1 {
+		t.Fatalf("expected 1 commit on second page, got %d", len(second.Commits))
+	}
+	if second.Commits[0].Message != "Initial commit" {
+		t.Errorf("expected initial commit on last page, got %q", second.Commits[0].Message)
+	}
+	if second.NextCursor != "" {
+		t.Errorf("expected no cursor on last page, got %q", second.NextCursor)
+	}
+}
+
+func TestHandleLog_Errors(t *testing.T) {
+	server := createLogTestServer(t)
+
+	tests := []struct {
+		name       string
+		url        string
+		wantStatus int
+	}{
+		{"invalid limit", "/api/log?limit=abc", http.StatusBadRequest},
+		{"negative limit", "/api/log?limit=-1", http.StatusBadRequest},
+		{"invalid cursor", "/api/log?cursor=xyz", http.StatusBadRequest},
+		{"unknown branch", "/api/log?branch=nonexistent", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			rec := httptest.NewRecorder()
+			server.mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+
+			var resp ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode error response: %v", err)
+			}
+			if resp.Error == "" {
+				t.Error("expected error message in response")
+			}
+		})
+	}
+}
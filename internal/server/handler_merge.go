@@ -0,0 +1,45 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/buckleypaul/giki/internal/git"
+)
+
+// MergeTextRequest represents the JSON payload for POST /api/merge-text
+type MergeTextRequest struct {
+	Base   string `json:"base"`   // version both edits started from
+	Ours   string `json:"ours"`   // the editor's version
+	Theirs string `json:"theirs"` // the newer version, e.g. from disk
+}
+
+// handleMergeText handles POST /api/merge-text requests.
+// Merges two edits of the same text against their common base, returning the
+// merged text with conflict markers and the merge as structured hunks.
+func (s *Server) handleMergeText(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var req MergeTextRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "invalid request body"})
+		return
+	}
+
+	// Only text can be merged line by line
+	for _, text := range []string{req.Base, req.Ours, req.Theirs} {
+		if !utf8.ValidString(text) || strings.ContainsRune(text, 0) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: "cannot merge binary content"})
+			return
+		}
+	}
+
+	// Return the merge result
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(git.MergeText(req.Base, req.Ours, req.Theirs))
+}
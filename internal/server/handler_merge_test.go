@@ -0,0 +1,107 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/buckleypaul/giki/internal/git"
+)
+
+func TestHandleMergeText(t *testing.T) {
+	// Create temp directory and git repo
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	server := New(4242, provider)
+
+	bodyJSON, _ := json.Marshal(MergeTextRequest{
+		Base:   "a\nb\nc\n",
+		Ours:   "A\nb\nc\n",
+		Theirs: "a\nb\nC\n",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/merge-text", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp git.MergeResult
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Conflicts != 0 || resp.Merged != "A\nb\nC\n" {
+		t.Errorf("unexpected merge result %+v", resp)
+	}
+}
+
+func TestHandleMergeText_Conflict(t *testing.T) {
+	// Create temp directory and git repo
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	server := New(4242, provider)
+
+	bodyJSON, _ := json.Marshal(MergeTextRequest{Base: "line\n", Ours: "mine\n", Theirs: "theirs\n"})
+	req := httptest.NewRequest(http.MethodPost, "/api/merge-text", bytes.NewReader(bodyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp git.MergeResult
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Conflicts != 1 || len(resp.Hunks) != 1 || !resp.Hunks[0].Conflict {
+		t.Errorf("expected a single conflict hunk, got %+v", resp)
+	}
+}
+
+func TestHandleMergeText_BadRequest(t *testing.T) {
+	// Create temp directory and git repo
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	server := New(4242, provider)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"invalid json", "invalid"},
+		{"binary content", `{"base": "a", "ours": "a\u0000b", "theirs": "a"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/merge-text", bytes.NewReader([]byte(tt.body)))
+			rec := httptest.NewRecorder()
+			server.mux.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d: %s", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
@@ -25,7 +25,7 @@ func (s *Server) handleMoveFolder(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := s.provider.MoveFolder(req.OldPath, req.NewPath); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), writeErrorStatus(err))
 		return
 	}
 
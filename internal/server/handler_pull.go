@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/buckleypaul/giki/internal/git"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+// RemoteRequest represents the JSON payload for POST /api/fetch and POST /api/pull
+type RemoteRequest struct {
+	Remote string `json:"remote"` // empty = current branch upstream or "origin"
+}
+
+// RemoteErrorResponse is returned when a fetch or pull fails.
+// Reason and Paths are set when a pull is blocked by a dirty tree or diverged history.
+type RemoteErrorResponse struct {
+	Error  string   `json:"error"`
+	Reason string   `json:"reason,omitempty"`
+	Paths  []string `json:"paths,omitempty"`
+}
+
+// ProgressEvent carries a chunk of remote progress output
+type ProgressEvent struct {
+	Message string `json:"message"`
+}
+
+// remoteOperation is a fetch or pull against a remote.
+type remoteOperation func(ctx context.Context, remote string, progress io.Writer) error
+
+// handleFetch handles POST /api/fetch requests.
+// Downloads new commits from the remote without changing the working tree.
+func (s *Server) handleFetch(w http.ResponseWriter, r *http.Request) {
+	s.runRemoteOperation(w, r, s.provider.Fetch)
+}
+
+// handlePull handles POST /api/pull requests.
+// Fetches from the remote and fast-forwards the current branch.
+func (s *Server) handlePull(w http.ResponseWriter, r *http.Request) {
+	s.runRemoteOperation(w, r, s.provider.Pull)
+}
+
+// runRemoteOperation runs a fetch or pull. Clients that accept text/event-stream
+// receive progress events followed by a done or error event; other clients
+// receive a single JSON response when the operation finishes.
+// The operation is cancelled if the client disconnects.
+func (s *Server) runRemoteOperation(w http.ResponseWriter, r *http.Request, op remoteOperation) {
+	// Parse request body; an empty body uses the default remote
+	var req RemoteRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: "invalid request body"})
+			return
+		}
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		stream, err := newSSEStream(w)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		ctx := r.Context()
+		if err := op(ctx, req.Remote, progressWriter{ctx: ctx, stream: stream}); err != nil {
+			// Nobody is listening once the client has gone
+			if ctx.Err() == nil {
+				stream.send("error", remoteErrorResponse(err))
+			}
+			return
+		}
+		stream.send("done", SuccessResponse{Success: true})
+		return
+	}
+
+	if err := op(r.Context(), req.Remote, nil); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(remoteErrorStatus(err))
+		json.NewEncoder(w).Encode(remoteErrorResponse(err))
+		return
+	}
+
+	// Return success response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(SuccessResponse{Success: true})
+}
+
+// remoteErrorResponse builds the error payload for a failed fetch or pull.
+func remoteErrorResponse(err error) RemoteErrorResponse {
+	resp := RemoteErrorResponse{Error: err.Error()}
+
+	var blocked *git.PullBlockedError
+	if errors.As(err, &blocked) {
+		resp.Reason = blocked.Reason
+		resp.Paths = blocked.Paths
+	}
+
+	return resp
+}
+
+// remoteErrorStatus maps a fetch or pull error to an HTTP status code.
+func remoteErrorStatus(err error) int {
+	var blocked *git.PullBlockedError
+	switch {
+	case errors.As(err, &blocked):
+		return http.StatusConflict
+	case errors.Is(err, transport.ErrAuthenticationRequired),
+		errors.Is(err, transport.ErrAuthorizationFailed):
+		return http.StatusUnauthorized
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), "detached HEAD"),
+		strings.Contains(err.Error(), "no upstream"):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// progressWriter forwards remote progress output as SSE progress events.
+// Writes fail once ctx is done, so streaming stops when the client disconnects.
+type progressWriter struct {
+	ctx    context.Context
+	stream *sseStream
+}
+
+func (pw progressWriter) Write(p []byte) (int, error) {
+	if err := pw.ctx.Err(); err != nil {
+		return 0, err
+	}
+	if err := pw.stream.send("progress", ProgressEvent{Message: string(p)}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,188 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gogit "github.com/go-git/go-git/v5"
+)
+
+// pushRemoteChange pushes the local branch to origin, then adds a commit to origin
+// from a second clone so the local repository is one commit behind.
+func pushRemoteChange(t *testing.T, server *Server, localDir string) {
+	req := httptest.NewRequest(http.MethodPost, "/api/push", nil)
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("failed to push: %d %s", rec.Code, rec.Body.String())
+	}
+
+	local, err := gogit.PlainOpen(localDir)
+	if err != nil {
+		t.Fatalf("failed to open repo: %v", err)
+	}
+	origin, err := local.Remote("origin")
+	if err != nil {
+		t.Fatalf("failed to get origin: %v", err)
+	}
+
+	otherDir := t.TempDir()
+	other, err := gogit.PlainClone(otherDir, false, &gogit.CloneOptions{URL: origin.Config().URLs[0]})
+	if err != nil {
+		t.Fatalf("failed to clone: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(otherDir, "remote.md"), []byte("remote"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	w, err := other.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	if _, err := w.Add("remote.md"); err != nil {
+		t.Fatalf("failed to add file: %v", err)
+	}
+	if _, err := w.Commit("Remote change", testCommitOptions()); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	if err := other.Push(&gogit.PushOptions{RemoteName: "origin"}); err != nil {
+		t.Fatalf("failed to push from clone: %v", err)
+	}
+}
+
+func TestHandlePull_JSON(t *testing.T) {
+	server, _, tempDir := createPushTestServer(t)
+	pushRemoteChange(t, server, tempDir)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/pull", nil)
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if _, err := os.Stat(filepath.Join(tempDir, "remote.md")); err != nil {
+		t.Errorf("expected remote.md after pull: %v", err)
+	}
+}
+
+func TestHandlePull_DirtyTree(t *testing.T) {
+	server, _, tempDir := createPushTestServer(t)
+	pushRemoteChange(t, server, tempDir)
+
+	if err := os.WriteFile(filepath.Join(tempDir, "notes.md"), []byte("unsaved"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/pull", strings.NewReader(`{"remote":"origin"}`))
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp RemoteErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Reason != "dirty-tree" || len(resp.Paths) != 1 || resp.Paths[0] != "notes.md" {
+		t.Errorf("unexpected error response: %+v", resp)
+	}
+}
+
+func TestHandleFetch_EventStream(t *testing.T) {
+	server, _, tempDir := createPushTestServer(t)
+	pushRemoteChange(t, server, tempDir)
+
+	ts := httptest.NewServer(server.mux)
+	defer ts.Close()
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/fetch", bytes.NewReader(nil))
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if contentType := resp.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Errorf("expected Content-Type text/event-stream, got %s", contentType)
+	}
+
+	// Progress events may precede the final event
+	var last string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if name, ok := strings.CutPrefix(scanner.Text(), "event: "); ok {
+			last = name
+		}
+	}
+	if last != "done" {
+		t.Errorf("expected stream to end with a done event, got %q", last)
+	}
+
+	// Fetching leaves the working tree alone
+	if _, err := os.Stat(filepath.Join(tempDir, "remote.md")); !os.IsNotExist(err) {
+		t.Errorf("expected remote.md to be absent after fetch, got %v", err)
+	}
+}
+
+func TestHandlePull_Errors(t *testing.T) {
+	server, _, _ := createPushTestServer(t)
+
+	tests := []struct {
+		name       string
+		url        string
+		body       string
+		wantStatus int
+	}{
+		{"invalid body", "/api/pull", "not json", http.StatusBadRequest},
+		{"unknown remote pull", "/api/pull", `{"remote":"upstream"}`, http.StatusNotFound},
+		{"unknown remote fetch", "/api/fetch", `{"remote":"upstream"}`, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.url, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			server.mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandlePull_ClientGone(t *testing.T) {
+	server, _, tempDir := createPushTestServer(t)
+	pushRemoteChange(t, server, tempDir)
+
+	// The client has already disconnected when the pull starts
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodPost, "/api/pull", nil).WithContext(ctx)
+	req.Header.Set("Accept", "text/event-stream")
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+
+	if strings.Contains(rec.Body.String(), "event: ") {
+		t.Errorf("expected no events after the client disconnected, got %q", rec.Body.String())
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "remote.md")); !os.IsNotExist(err) {
+		t.Errorf("expected the cancelled pull to leave remote.md absent, got %v", err)
+	}
+}
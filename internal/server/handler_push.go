@@ -0,0 +1,80 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/buckleypaul/giki/internal/git"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+// PushRequest represents the JSON payload for POST /api/push
+type PushRequest struct {
+	Remote string `json:"remote"` // empty = branch upstream or "origin"
+	Branch string `json:"branch"` // empty = current branch
+}
+
+// PushRejectedResponse is returned when the remote refuses a push
+type PushRejectedResponse struct {
+	Error    string `json:"error"`
+	Rejected bool   `json:"rejected"`
+	Remote   string `json:"remote"`
+	Branch   string `json:"branch"`
+	Reason   string `json:"reason"`
+}
+
+// handlePush handles POST /api/push requests.
+// Pushes committed changes on a branch to its remote.
+func (s *Server) handlePush(w http.ResponseWriter, r *http.Request) {
+	// Parse request body; an empty body pushes the current branch
+	var req PushRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: "invalid request body"})
+			return
+		}
+	}
+
+	// Push
+	if err := s.provider.Push(req.Remote, req.Branch); err != nil {
+		var rejected *git.PushRejectedError
+		if errors.As(err, &rejected) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(PushRejectedResponse{
+				Error:    err.Error(),
+				Rejected: true,
+				Remote:   rejected.Remote,
+				Branch:   rejected.Branch,
+				Reason:   rejected.Reason,
+			})
+			return
+		}
+
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, git.ErrReadOnly):
+			status = http.StatusForbidden
+		case errors.Is(err, transport.ErrAuthenticationRequired),
+			errors.Is(err, transport.ErrAuthorizationFailed):
+			status = http.StatusUnauthorized
+		case strings.Contains(err.Error(), "not found"):
+			status = http.StatusNotFound
+		case strings.Contains(err.Error(), "detached HEAD"):
+			status = http.StatusConflict
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	// Return success response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(SuccessResponse{Success: true})
+}
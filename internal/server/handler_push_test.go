@@ -0,0 +1,173 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buckleypaul/giki/internal/git"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// createPushTestServer creates a repository whose origin is a local bare repository
+// and returns a server backed by it, along with the bare repository.
+func createPushTestServer(t *testing.T) (*Server, *gogit.Repository, string) {
+	remoteDir := t.TempDir()
+	remote, err := gogit.PlainInit(remoteDir, true)
+	if err != nil {
+		t.Fatalf("failed to init bare repo: %v", err)
+	}
+
+	tempDir := t.TempDir()
+	repo := createTestRepoWithCommit(t, tempDir)
+	if _, err := repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{remoteDir}}); err != nil {
+		t.Fatalf("failed to add remote: %v", err)
+	}
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	return New(4242, provider), remote, tempDir
+}
+
+func TestHandlePush_Success(t *testing.T) {
+	server, remote, tempDir := createPushTestServer(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/push", nil)
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	local, err := gogit.PlainOpen(tempDir)
+	if err != nil {
+		t.Fatalf("failed to open repo: %v", err)
+	}
+	head, err := local.Head()
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v", err)
+	}
+
+	ref, err := remote.Reference(head.Name(), false)
+	if err != nil {
+		t.Fatalf("expected %s on remote: %v", head.Name().Short(), err)
+	}
+	if ref.Hash() != head.Hash() {
+		t.Errorf("expected remote at %s, got %s", head.Hash(), ref.Hash())
+	}
+}
+
+func TestHandlePush_Rejected(t *testing.T) {
+	server, remote, tempDir := createPushTestServer(t)
+
+	local, err := gogit.PlainOpen(tempDir)
+	if err != nil {
+		t.Fatalf("failed to open repo: %v", err)
+	}
+	head, err := local.Head()
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v", err)
+	}
+
+	// Give the remote branch a history unrelated to the local one
+	otherDir := t.TempDir()
+	other := createTestRepoWithCommit(t, otherDir)
+	if err := os.WriteFile(filepath.Join(otherDir, "theirs.md"), []byte("theirs"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	w, err := other.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	if _, err := w.Add("theirs.md"); err != nil {
+		t.Fatalf("failed to add file: %v", err)
+	}
+	if _, err := w.Commit("Their change", testCommitOptions()); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	origin, err := local.Remote("origin")
+	if err != nil {
+		t.Fatalf("failed to get origin: %v", err)
+	}
+	if _, err := other.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: origin.Config().URLs}); err != nil {
+		t.Fatalf("failed to add remote: %v", err)
+	}
+	otherHead, err := other.Head()
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v", err)
+	}
+	refSpec := config.RefSpec(otherHead.Name().String() + ":" + head.Name().String())
+	if err := other.Push(&gogit.PushOptions{RemoteName: "origin", RefSpecs: []config.RefSpec{refSpec}}); err != nil {
+		t.Fatalf("failed to push from other repo: %v", err)
+	}
+
+	body, _ := json.Marshal(PushRequest{Remote: "origin", Branch: head.Name().Short()})
+	req := httptest.NewRequest(http.MethodPost, "/api/push", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp PushRejectedResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !resp.Rejected || resp.Remote != "origin" || resp.Branch != head.Name().Short() || resp.Reason == "" {
+		t.Errorf("unexpected rejection response: %+v", resp)
+	}
+
+	// The remote branch is untouched
+	ref, err := remote.Reference(plumbing.NewBranchReferenceName(head.Name().Short()), false)
+	if err != nil {
+		t.Fatalf("expected branch on remote: %v", err)
+	}
+	if ref.Hash() == head.Hash() {
+		t.Error("expected rejected push to leave the remote branch unchanged")
+	}
+}
+
+func TestHandlePush_Errors(t *testing.T) {
+	server, _, _ := createPushTestServer(t)
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"invalid body", "not json", http.StatusBadRequest},
+		{"unknown remote", `{"remote":"upstream"}`, http.StatusNotFound},
+		{"unknown branch", `{"branch":"nonexistent"}`, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/push", bytes.NewReader([]byte(tt.body)))
+			rec := httptest.NewRecorder()
+			server.mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+
+			var resp ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode error response: %v", err)
+			}
+			if resp.Error == "" {
+				t.Error("expected error message in response")
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// handleTags handles GET /api/tags requests.
+// Returns a JSON array of all tags with their target commit and date, newest first.
+func (s *Server) handleTags(w http.ResponseWriter, r *http.Request) {
+	// Get tags from provider
+	tags, err := s.provider.Tags()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Return as JSON
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(tags); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buckleypaul/giki/internal/git"
+	gogit "github.com/go-git/go-git/v5"
+)
+
+func TestHandleTags(t *testing.T) {
+	tempDir := t.TempDir()
+	repo := createTestRepoWithCommit(t, tempDir)
+
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v", err)
+	}
+	if _, err := repo.CreateTag("v1.0", head.Hash(), nil); err != nil {
+		t.Fatalf("failed to create tag: %v", err)
+	}
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	server := New(4242, provider)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tags", nil)
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var tags []git.TagInfo
+	if err := json.NewDecoder(rec.Body).Decode(&tags); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(tags) != 1 {
+		t.Fatalf("expected 1 tag, got %d", len(tags))
+	}
+	if tags[0].Name != "v1.0" || tags[0].Hash != head.Hash().String() {
+		t.Errorf("unexpected tag: %+v", tags[0])
+	}
+}
+
+// TestHandleFile_TagPermalink tests reading a file at a tag after it changed on the current branch.
+func TestHandleFile_TagPermalink(t *testing.T) {
+	tempDir := t.TempDir()
+	repo := createTestRepoWithCommit(t, tempDir)
+
+	w, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+
+	pagePath := filepath.Join(tempDir, "page.md")
+	if err := os.WriteFile(pagePath, []byte("released"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if _, err := w.Add("page.md"); err != nil {
+		t.Fatalf("failed to add file: %v", err)
+	}
+	hash, err := w.Commit("Release", &gogit.CommitOptions{Author: testSignature()})
+	if err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	if _, err := repo.CreateTag("v1.2", hash, nil); err != nil {
+		t.Fatalf("failed to create tag: %v", err)
+	}
+
+	// Keep editing on the current branch
+	if err := os.WriteFile(pagePath, []byte("work in progress"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	server := New(4242, provider)
+
+	for _, rev := range []string{"v1.2", hash.String()[:8]} {
+		req := httptest.NewRequest(http.MethodGet, "/api/file/page.md?branch="+rev, nil)
+		rec := httptest.NewRecorder()
+		server.mux.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200 for %s, got %d: %s", rev, rec.Code, rec.Body.String())
+		}
+		if body := rec.Body.String(); body != "released" {
+			t.Errorf("expected tagged content for %s, got %q", rev, body)
+		}
+	}
+}
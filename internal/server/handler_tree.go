@@ -15,6 +15,9 @@ func (s *Server) handleTree(w http.ResponseWriter, r *http.Request) {
 	// Get tree from provider
 	tree, err := s.provider.Tree(branch)
 	if err != nil {
+		if writeRateLimited(w, err) {
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
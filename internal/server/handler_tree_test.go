@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/buckleypaul/giki/internal/git"
@@ -100,3 +101,30 @@ func TestHandleTree_Integration(t *testing.T) {
 		}
 	}
 }
+
+// TestHandleTree_RateLimited tests that an exhausted API rate limit is reported as 429.
+func TestHandleTree_RateLimited(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	local, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	// The reset time is unknown, so there is no Retry-After
+	server := New(4242, &rateLimitedProvider{GitProvider: local})
+
+	req := httptest.NewRequest("GET", "/api/tree", nil)
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status 429, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if retryAfter := rec.Header().Get("Retry-After"); retryAfter != "" {
+		t.Errorf("expected no Retry-After, got %q", retryAfter)
+	}
+	if !strings.Contains(rec.Body.String(), "rate limit exceeded") {
+		t.Errorf("expected rate limit message, got %s", rec.Body.String())
+	}
+}
@@ -0,0 +1,312 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	pathpkg "path"
+	"strconv"
+	"strings"
+
+	"github.com/buckleypaul/giki/internal/git"
+)
+
+// defaultUploadLimit is the largest file accepted by /api/upload unless configured otherwise.
+const defaultUploadLimit = 10 << 20
+
+// uploadExtensions names uploads that have no file name by their detected
+// content type. mime.ExtensionsByType is avoided because it can list unusual
+// extensions first (".jfif" for JPEG).
+var uploadExtensions = map[string]string{
+	"image/png":       ".png",
+	"image/jpeg":      ".jpg",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"image/bmp":       ".bmp",
+	"image/x-icon":    ".ico",
+	"application/pdf": ".pdf",
+}
+
+// uploadFormOverhead is the room allowed for multipart headers and form
+// fields on top of the file itself.
+const uploadFormOverhead = 64 << 10
+
+// errUploadTooLarge is returned when an uploaded file exceeds the size limit.
+var errUploadTooLarge = errors.New("file exceeds upload size limit")
+
+// UploadResponse represents the response from POST /api/upload
+type UploadResponse struct {
+	Success bool   `json:"success"`
+	Path    string `json:"path"` // where the file was written
+	Size    int    `json:"size"`
+	Link    string `json:"link,omitempty"` // path relative to the page, for embedding in it
+}
+
+// SetUploadLimit sets the largest file accepted by /api/upload, in bytes.
+// Zero or less restores the default limit.
+func (s *Server) SetUploadLimit(limit int64) {
+	s.uploadLimit = limit
+}
+
+// handleUpload handles POST /api/upload?path=<path>&page=<page>&name=<name>&overwrite=<bool>
+// Writes a binary file such as an image or attachment, sent either as the
+// "file" field of a multipart form or as the raw request body. The path,
+// page, name and overwrite parameters may also be form fields.
+// Without a path, the file is named after name (or the multipart file name)
+// and placed in an assets/ folder next to page, avoiding existing files.
+// An existing file at an explicit path is only replaced when If-Match holds
+// its current ETag or overwrite is true; otherwise the response is 409.
+func (s *Server) handleUpload(w http.ResponseWriter, r *http.Request) {
+	limit := s.uploadLimit
+	if limit <= 0 {
+		limit = defaultUploadLimit
+	}
+
+	query := r.URL.Query()
+	path, page, name := query.Get("path"), query.Get("page"), query.Get("name")
+	overwriteParam := query.Get("overwrite")
+
+	// Read the file from a multipart form or the raw body
+	var content []byte
+	var err error
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		r.Body = http.MaxBytesReader(w, r.Body, limit+uploadFormOverhead)
+		var fields map[string]string
+		content, fields, err = readMultipartUpload(r, limit)
+		if fields["path"] != "" {
+			path = fields["path"]
+		}
+		if fields["page"] != "" {
+			page = fields["page"]
+		}
+		if fields["name"] != "" {
+			name = fields["name"]
+		}
+		if fields["overwrite"] != "" {
+			overwriteParam = fields["overwrite"]
+		}
+	} else {
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+		content, err = readUpload(r.Body, limit)
+	}
+
+	if err == nil && content == nil {
+		err = fmt.Errorf("no file uploaded")
+	}
+	if err != nil {
+		status := http.StatusBadRequest
+		var maxBytesErr *http.MaxBytesError
+		if errors.Is(err, errUploadTooLarge) || errors.As(err, &maxBytesErr) {
+			status = http.StatusRequestEntityTooLarge
+			err = fmt.Errorf("%w of %d bytes", errUploadTooLarge, limit)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	overwrite := false
+	if overwriteParam != "" {
+		overwrite, err = strconv.ParseBool(overwriteParam)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: "invalid overwrite parameter"})
+			return
+		}
+	}
+
+	// Validate the page the file belongs to
+	page = strings.Trim(strings.ReplaceAll(page, "\\", "/"), "/")
+	if strings.Contains(page, "..") {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "invalid page: cannot contain '..'"})
+		return
+	}
+	if git.IsGitDirPath(page) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "invalid page: cannot be in the .git directory"})
+		return
+	}
+
+	// Name the file in the page's assets folder unless a path was given
+	path = strings.Trim(strings.ReplaceAll(path, "\\", "/"), "/")
+	base := "" // version of the file an explicit path replaces, from If-Match
+	if path == "" {
+		path, err = s.assetPath(page, name, content)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	} else if git.IsGitDirPath(path) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "invalid path: cannot write to the .git directory"})
+		return
+	} else if base = parseETag(r.Header.Get("If-Match")); base == "" && !overwrite {
+		// Don't replace an existing file the client may not know about
+		if current, err := s.provider.FileContent(path, ""); err == nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("ETag", formatETag(git.BlobHash(current)))
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: "file already exists; send If-Match or overwrite=true to replace it"})
+			return
+		}
+	}
+
+	// Write file to disk; with If-Match, replace only the version the client has seen
+	if err := s.provider.WriteFileIfUnchanged(path, content, base); err != nil {
+		if writeConflict(w, err, nil) {
+			return
+		}
+		status := writeErrorStatus(err)
+		if strings.Contains(err.Error(), "invalid path") || strings.Contains(err.Error(), "cannot be empty") {
+			status = http.StatusBadRequest
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	resp := UploadResponse{Success: true, Path: path, Size: len(content)}
+	if page != "" {
+		resp.Link = relativeLink(pathpkg.Dir(page), path)
+	}
+
+	// Return success response with the file's ETag
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", formatETag(git.BlobHash(content)))
+	json.NewEncoder(w).Encode(resp)
+}
+
+// readUpload reads an uploaded file, failing with errUploadTooLarge if it
+// is larger than limit bytes.
+func readUpload(r io.Reader, limit int64) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > limit {
+		return nil, errUploadTooLarge
+	}
+	if content == nil {
+		content = []byte{}
+	}
+	return content, nil
+}
+
+// readMultipartUpload reads the "file" part of a multipart form along with its
+// small text fields. The file's own name is returned as the "name" field
+// unless the form sets one. Content is nil if the form has no file.
+func readMultipartUpload(r *http.Request, limit int64) ([]byte, map[string]string, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid multipart form: %w", err)
+	}
+
+	var content []byte
+	var fileName string
+	fields := map[string]string{}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid multipart form: %w", err)
+		}
+
+		switch part.FormName() {
+		case "file":
+			content, err = readUpload(part, limit)
+			fileName = part.FileName()
+		case "path", "page", "name", "overwrite":
+			var value []byte
+			value, err = io.ReadAll(io.LimitReader(part, 4096))
+			fields[part.FormName()] = string(value)
+		}
+		part.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if fields["name"] == "" {
+		fields["name"] = fileName
+	}
+	return content, fields, nil
+}
+
+// assetPath picks a path for an uploaded file in the assets/ folder next to
+// page (or at the repository root without a page), named after name and
+// numbered if a file of that name already exists.
+func (s *Server) assetPath(page, name string, content []byte) (string, error) {
+	dir := "assets"
+	if parent := pathpkg.Dir(page); page != "" && parent != "." {
+		dir = parent + "/assets"
+	}
+
+	name = sanitizeFileName(name)
+	if name == "" {
+		name = "upload"
+		name += uploadExtensions[http.DetectContentType(content)]
+	}
+
+	ext := pathpkg.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	for i := 1; i < 1000; i++ {
+		candidate := dir + "/" + name
+		if i > 1 {
+			candidate = fmt.Sprintf("%s/%s-%d%s", dir, stem, i, ext)
+		}
+
+		_, err := s.provider.FileContent(candidate, "")
+		if err != nil && strings.Contains(err.Error(), "file not found") {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("could not find a free name for %s in %s", name, dir)
+}
+
+// sanitizeFileName reduces a client-supplied file name to its base name,
+// replacing characters other than letters, digits, '.', '-' and '_' with '-'.
+func sanitizeFileName(name string) string {
+	name = pathpkg.Base(strings.ReplaceAll(name, "\\", "/"))
+
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+
+	// Leading dots would hide the file or climb out of the folder
+	return strings.TrimLeft(b.String(), ".-")
+}
+
+// relativeLink returns target as a slash-separated path relative to the folder dir.
+func relativeLink(dir, target string) string {
+	if dir == "." || dir == "" {
+		return target
+	}
+
+	dirParts := strings.Split(dir, "/")
+	targetParts := strings.Split(target, "/")
+	common := 0
+	for common < len(dirParts) && common < len(targetParts)-1 && dirParts[common] == targetParts[common] {
+		common++
+	}
+
+	return strings.Repeat("../", len(dirParts)-common) + strings.Join(targetParts[common:], "/")
+}
@@ -0,0 +1,298 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buckleypaul/giki/internal/git"
+)
+
+// pngHeader is the start of a PNG file, enough for content type detection.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+// newUploadTestServer creates a server for a new repository and returns its directory.
+func newUploadTestServer(t *testing.T) (string, *Server) {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	return tempDir, New(4242, provider)
+}
+
+// postUpload sends an upload request through the server's mux and decodes a successful response.
+func postUpload(t *testing.T, server *Server, query, contentType string, body []byte) (*httptest.ResponseRecorder, UploadResponse) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/upload"+query, bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+
+	var resp UploadResponse
+	if rec.Code == http.StatusOK {
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+	}
+	return rec, resp
+}
+
+// multipartBody builds a multipart form with a file and optional text fields.
+func multipartBody(t *testing.T, fileName string, content []byte, fields map[string]string) ([]byte, string) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	for key, value := range fields {
+		if err := writer.WriteField(key, value); err != nil {
+			t.Fatalf("failed to write field: %v", err)
+		}
+	}
+	part, err := writer.CreateFormFile("file", fileName)
+	if err != nil {
+		t.Fatalf("failed to create form file: %v", err)
+	}
+	part.Write(content)
+	writer.Close()
+
+	return buf.Bytes(), writer.FormDataContentType()
+}
+
+func TestHandleUpload_Multipart(t *testing.T) {
+	tempDir, server := newUploadTestServer(t)
+
+	body, contentType := multipartBody(t, "My Diagram.png", pngHeader, map[string]string{"page": "docs/guide.md"})
+	rec, resp := postUpload(t, server, "", contentType, body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if resp.Path != "docs/assets/My-Diagram.png" || resp.Link != "assets/My-Diagram.png" || resp.Size != len(pngHeader) {
+		t.Errorf("unexpected response %+v", resp)
+	}
+	if rec.Header().Get("ETag") != formatETag(git.BlobHash(pngHeader)) {
+		t.Errorf("unexpected ETag %q", rec.Header().Get("ETag"))
+	}
+
+	written, err := os.ReadFile(filepath.Join(tempDir, "docs", "assets", "My-Diagram.png"))
+	if err != nil {
+		t.Fatalf("failed to read uploaded file: %v", err)
+	}
+	if !bytes.Equal(written, pngHeader) {
+		t.Errorf("uploaded content was not written unchanged")
+	}
+
+	// A second upload of the same name gets a new name
+	_, resp = postUpload(t, server, "", contentType, body)
+	if resp.Path != "docs/assets/My-Diagram-2.png" {
+		t.Errorf("expected numbered name, got %q", resp.Path)
+	}
+}
+
+func TestHandleUpload_RawBody(t *testing.T) {
+	tempDir, server := newUploadTestServer(t)
+
+	// An explicit path is used as given
+	rec, resp := postUpload(t, server, "?path=files/report.pdf&page=docs/guide.md", "application/pdf", []byte("%PDF-1.4"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if resp.Path != "files/report.pdf" || resp.Link != "../files/report.pdf" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+	if got, _ := os.ReadFile(filepath.Join(tempDir, "files", "report.pdf")); string(got) != "%PDF-1.4" {
+		t.Errorf("unexpected uploaded content %q", got)
+	}
+
+	// Without a name, the file is named from its content type
+	rec, resp = postUpload(t, server, "", "application/octet-stream", pngHeader)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if resp.Path != "assets/upload.png" || resp.Link != "" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+
+	// JPEG gets its common extension
+	_, resp = postUpload(t, server, "", "application/octet-stream", []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00"))
+	if resp.Path != "assets/upload.jpg" {
+		t.Errorf("expected assets/upload.jpg, got %q", resp.Path)
+	}
+}
+
+func TestHandleUpload_ExistingPath(t *testing.T) {
+	tempDir, server := newUploadTestServer(t)
+
+	rec, _ := postUpload(t, server, "?path=images/logo.png", "application/octet-stream", pngHeader)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	etag := rec.Header().Get("ETag")
+
+	// Uploading over the file without If-Match or overwrite is refused
+	replacement := append(append([]byte{}, pngHeader...), 'x')
+	rec, _ = postUpload(t, server, "?path=images/logo.png", "application/octet-stream", replacement)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("ETag") != etag {
+		t.Errorf("expected current ETag %s, got %q", etag, rec.Header().Get("ETag"))
+	}
+
+	// A stale If-Match conflicts, the current one replaces the file
+	req := httptest.NewRequest(http.MethodPost, "/api/upload?path=images/logo.png", bytes.NewReader(replacement))
+	req.Header.Set("If-Match", formatETag(git.BlobHash([]byte("stale"))))
+	rec = httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected status 409 for stale If-Match, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/upload?path=images/logo.png", bytes.NewReader(replacement))
+	req.Header.Set("If-Match", etag)
+	rec = httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200 for current If-Match, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// An explicit overwrite replaces the file regardless of its version
+	body, contentType := multipartBody(t, "logo.png", pngHeader, map[string]string{"path": "images/logo.png", "overwrite": "true"})
+	rec, _ = postUpload(t, server, "", contentType, body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200 with overwrite, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got, _ := os.ReadFile(filepath.Join(tempDir, "images", "logo.png")); !bytes.Equal(got, pngHeader) {
+		t.Errorf("expected file to be overwritten, got %q", got)
+	}
+
+	rec, _ = postUpload(t, server, "?path=images/logo.png&overwrite=maybe", "application/octet-stream", pngHeader)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid overwrite, got %d", rec.Code)
+	}
+}
+
+func TestHandleUpload_GitDirectory(t *testing.T) {
+	tempDir, server := newUploadTestServer(t)
+
+	// Files git would run, like hooks, can never be uploaded
+	hook := []byte("#!/bin/sh\necho pwned\n")
+	for _, query := range []string{
+		"?path=.git/hooks/pre-commit&overwrite=true",
+		"?path=./.git/hooks/pre-commit&overwrite=true",
+		"?path=/.git&overwrite=true",
+		"?page=.git/hooks/page.md&name=pre-commit",
+	} {
+		rec, _ := postUpload(t, server, query, "application/octet-stream", hook)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d: %s", query, rec.Code, rec.Body.String())
+		}
+	}
+
+	for _, path := range []string{".git/hooks/pre-commit", ".git/hooks/assets/pre-commit"} {
+		if _, err := os.Stat(filepath.Join(tempDir, filepath.FromSlash(path))); !os.IsNotExist(err) {
+			t.Errorf("expected %s not to be written, got %v", path, err)
+		}
+	}
+}
+
+func TestHandleUpload_SizeLimit(t *testing.T) {
+	_, server := newUploadTestServer(t)
+	server.SetUploadLimit(8)
+
+	rec, _ := postUpload(t, server, "?name=big.bin", "application/octet-stream", bytes.Repeat([]byte{1}, 9))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status 413 for raw body, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	body, contentType := multipartBody(t, "big.bin", bytes.Repeat([]byte{1}, 9), nil)
+	rec, _ = postUpload(t, server, "", contentType, body)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status 413 for multipart, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// A file at the limit is accepted
+	rec, _ = postUpload(t, server, "?name=ok.bin", "application/octet-stream", bytes.Repeat([]byte{1}, 8))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandleUpload_BadRequest(t *testing.T) {
+	_, server := newUploadTestServer(t)
+
+	body, contentType := multipartBody(t, "", nil, map[string]string{"name": "a.png"})
+	noFile := bytes.Replace(body, []byte(`name="file"`), []byte(`name="other"`), 1)
+
+	tests := []struct {
+		name        string
+		query       string
+		contentType string
+		body        []byte
+	}{
+		{"path traversal", "?path=../outside.png", "image/png", pngHeader},
+		{"page traversal", "?page=../docs/page.md", "image/png", pngHeader},
+		{"multipart without file", "", contentType, noFile},
+		{"malformed multipart", "", "multipart/form-data; boundary=x", []byte("garbage")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, _ := postUpload(t, server, tt.query, tt.contentType, tt.body)
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d: %s", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestSanitizeFileName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"image.png", "image.png"},
+		{"My Photo (1).jpg", "My-Photo--1-.jpg"},
+		{"../../etc/passwd", "passwd"},
+		{`C:\Users\me\shot.png`, "shot.png"},
+		{".hidden", "hidden"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := sanitizeFileName(tt.name); got != tt.expected {
+			t.Errorf("sanitizeFileName(%q) = %q, want %q", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestRelativeLink(t *testing.T) {
+	tests := []struct {
+		dir      string
+		target   string
+		expected string
+	}{
+		{".", "assets/a.png", "assets/a.png"},
+		{"docs", "docs/assets/a.png", "assets/a.png"},
+		{"docs/guide", "docs/assets/a.png", "../assets/a.png"},
+		{"docs", "files/a.pdf", "../files/a.pdf"},
+		{"docs", "docs", "../docs"},
+	}
+
+	for _, tt := range tests {
+		if got := relativeLink(tt.dir, tt.target); got != tt.expected {
+			t.Errorf("relativeLink(%q, %q) = %q, want %q", tt.dir, tt.target, got, tt.expected)
+		}
+	}
+}
@@ -2,24 +2,42 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+
+	"github.com/buckleypaul/giki/internal/git"
 )
 
 // WriteRequest represents the JSON payload for POST /api/write
 type WriteRequest struct {
-	Path    string `json:"path"`
-	Content string `json:"content"`
+	Path     string `json:"path"`
+	Content  string `json:"content"`
+	BaseHash string `json:"baseHash,omitempty"` // ETag of the version edited; overrides If-Match
 }
 
 // DeleteRequest represents the JSON payload for POST /api/delete
 type DeleteRequest struct {
-	Path string `json:"path"`
+	Path     string `json:"path"`
+	BaseHash string `json:"baseHash,omitempty"` // ETag of the version deleted; overrides If-Match
 }
 
 // MoveRequest represents the JSON payload for POST /api/move
 type MoveRequest struct {
-	OldPath string `json:"oldPath"`
-	NewPath string `json:"newPath"`
+	OldPath  string `json:"oldPath"`
+	NewPath  string `json:"newPath"`
+	BaseHash string `json:"baseHash,omitempty"` // ETag of the version moved; overrides If-Match
+}
+
+// ConflictResponse is returned when a file changed since the version an edit was based on
+type ConflictResponse struct {
+	Error       string `json:"error"`
+	Conflict    bool   `json:"conflict"`
+	Path        string `json:"path"`
+	BaseHash    string `json:"baseHash"`
+	CurrentHash string `json:"currentHash,omitempty"` // empty if the file was deleted
+	Content     string `json:"content"`               // current content
+	Deleted     bool   `json:"deleted"`
+	Index       *int   `json:"index,omitempty"` // change set operation, if any
 }
 
 // SuccessResponse represents a generic success response
@@ -34,6 +52,7 @@ type ErrorResponse struct {
 
 // handleWrite handles POST /api/write requests.
 // Writes content to a file at the specified path.
+// With a base hash (baseHash or If-Match), responds 409 if the file has changed.
 func (s *Server) handleWrite(w http.ResponseWriter, r *http.Request) {
 	// Parse request body
 	var req WriteRequest
@@ -52,21 +71,26 @@ func (s *Server) handleWrite(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Write file to disk
-	if err := s.provider.WriteFile(req.Path, []byte(req.Content)); err != nil {
+	// Write file to disk, unless it changed since the version being edited
+	if err := s.provider.WriteFileIfUnchanged(req.Path, []byte(req.Content), baseHash(r, req.BaseHash)); err != nil {
+		if writeConflict(w, err, nil) {
+			return
+		}
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(writeErrorStatus(err))
 		json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	// Return success response
+	// Return success response with the new version's ETag
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", formatETag(git.BlobHash([]byte(req.Content))))
 	json.NewEncoder(w).Encode(SuccessResponse{Success: true})
 }
 
 // handleDelete handles POST /api/delete requests.
 // Deletes a file at the specified path.
+// With a base hash (baseHash or If-Match), responds 409 if the file has changed.
 func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
 	// Parse request body
 	var req DeleteRequest
@@ -85,10 +109,13 @@ func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Delete file from disk
-	if err := s.provider.DeleteFile(req.Path); err != nil {
+	// Delete file from disk, unless it changed since the version being deleted
+	if err := s.provider.DeleteFileIfUnchanged(req.Path, baseHash(r, req.BaseHash)); err != nil {
+		if writeConflict(w, err, nil) {
+			return
+		}
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(writeErrorStatus(err))
 		json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
 		return
 	}
@@ -100,6 +127,7 @@ func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
 
 // handleMove handles POST /api/move requests.
 // Moves/renames a file from oldPath to newPath.
+// With a base hash (baseHash or If-Match), responds 409 if the file has changed.
 func (s *Server) handleMove(w http.ResponseWriter, r *http.Request) {
 	// Parse request body
 	var req MoveRequest
@@ -118,10 +146,13 @@ func (s *Server) handleMove(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Move file on disk
-	if err := s.provider.MoveFile(req.OldPath, req.NewPath); err != nil {
+	// Move file on disk, unless it changed since the version being moved
+	if err := s.provider.MoveFileIfUnchanged(req.OldPath, req.NewPath, baseHash(r, req.BaseHash)); err != nil {
+		if writeConflict(w, err, nil) {
+			return
+		}
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(writeErrorStatus(err))
 		json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
 		return
 	}
@@ -130,3 +161,47 @@ func (s *Server) handleMove(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(SuccessResponse{Success: true})
 }
+
+// baseHash returns the version an edit is based on: the request field if set,
+// otherwise the If-Match header.
+func baseHash(r *http.Request, field string) string {
+	if field != "" {
+		return field
+	}
+	return parseETag(r.Header.Get("If-Match"))
+}
+
+// writeErrorStatus maps an error from a write operation to an HTTP status:
+// 403 for a read-only repository, 500 otherwise.
+func writeErrorStatus(err error) int {
+	if errors.Is(err, git.ErrReadOnly) {
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+// writeConflict writes a 409 response with the file's current content if err
+// is a *git.ConflictError, and reports whether it did.
+func writeConflict(w http.ResponseWriter, err error, index *int) bool {
+	var conflict *git.ConflictError
+	if !errors.As(err, &conflict) {
+		return false
+	}
+
+	if conflict.CurrentHash != "" {
+		w.Header().Set("ETag", formatETag(conflict.CurrentHash))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+	json.NewEncoder(w).Encode(ConflictResponse{
+		Error:       conflict.Error(),
+		Conflict:    true,
+		Path:        conflict.Path,
+		BaseHash:    conflict.BaseHash,
+		CurrentHash: conflict.CurrentHash,
+		Content:     string(conflict.Content),
+		Deleted:     conflict.Content == nil,
+		Index:       index,
+	})
+	return true
+}
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/buckleypaul/giki/internal/git"
@@ -253,3 +254,196 @@ func TestHandleMove_EmptyPaths(t *testing.T) {
 		t.Errorf("expected status 400, got %d", rec.Code)
 	}
 }
+
+// TestHandleWrite_IfMatch tests that writes based on an outdated version are rejected.
+func TestHandleWrite_IfMatch(t *testing.T) {
+	// Create temp directory and git repo
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	if err := provider.WriteFile("page.md", []byte("original")); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	server := New(4242, provider)
+
+	// Load the file to get its ETag
+	req := httptest.NewRequest(http.MethodGet, "/api/file/page.md", nil)
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected an ETag")
+	}
+
+	// First editor saves based on the loaded version
+	bodyJSON, _ := json.Marshal(WriteRequest{Path: "page.md", Content: "first"})
+	req = httptest.NewRequest(http.MethodPost, "/api/write", bytes.NewReader(bodyJSON))
+	req.Header.Set("If-Match", etag)
+	rec = httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	newETag := rec.Header().Get("ETag")
+	if newETag == "" || newETag == etag {
+		t.Errorf("expected a new ETag after writing, got %q", newETag)
+	}
+
+	// Second editor saves based on the same, now outdated, version
+	bodyJSON, _ = json.Marshal(WriteRequest{Path: "page.md", Content: "second"})
+	req = httptest.NewRequest(http.MethodPost, "/api/write", bytes.NewReader(bodyJSON))
+	req.Header.Set("If-Match", etag)
+	rec = httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp ConflictResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !resp.Conflict || resp.Content != "first" || resp.Deleted {
+		t.Errorf("expected conflict with current content, got %+v", resp)
+	}
+	if rec.Header().Get("ETag") != newETag {
+		t.Errorf("expected current ETag %s, got %s", newETag, rec.Header().Get("ETag"))
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "page.md"))
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	if string(content) != "first" {
+		t.Errorf("expected first save to be kept, got %q", content)
+	}
+}
+
+// TestHandleDeleteAndMove_BaseHash tests the baseHash field on delete and move.
+func TestHandleDeleteAndMove_BaseHash(t *testing.T) {
+	// Create temp directory and git repo
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	if err := provider.WriteFile("page.md", []byte("current")); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	server := New(4242, provider)
+
+	stale := git.BlobHash([]byte("stale"))
+	current := git.BlobHash([]byte("current"))
+
+	tests := []struct {
+		name   string
+		url    string
+		body   any
+		status int
+	}{
+		{"delete stale", "/api/delete", DeleteRequest{Path: "page.md", BaseHash: stale}, http.StatusConflict},
+		{"move stale", "/api/move", MoveRequest{OldPath: "page.md", NewPath: "moved.md", BaseHash: stale}, http.StatusConflict},
+		{"move current", "/api/move", MoveRequest{OldPath: "page.md", NewPath: "moved.md", BaseHash: current}, http.StatusOK},
+		{"delete current", "/api/delete", DeleteRequest{Path: "moved.md", BaseHash: current}, http.StatusOK},
+		{"delete missing", "/api/delete", DeleteRequest{Path: "moved.md", BaseHash: current}, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		bodyJSON, _ := json.Marshal(tt.body)
+		req := httptest.NewRequest(http.MethodPost, tt.url, bytes.NewReader(bodyJSON))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		server.mux.ServeHTTP(rec, req)
+
+		if rec.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d: %s", tt.name, tt.status, rec.Code, rec.Body.String())
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(tempDir, "moved.md")); !os.IsNotExist(err) {
+		t.Errorf("expected moved.md to be deleted, got %v", err)
+	}
+}
+
+// readOnlyProvider behaves like a provider that browses a repository through a
+// hosting API: every operation that would modify the repository fails.
+type readOnlyProvider struct {
+	git.GitProvider
+}
+
+func (readOnlyProvider) WriteFile(path string, content []byte) error { return git.ErrReadOnly }
+func (readOnlyProvider) DeleteFile(path string) error                { return git.ErrReadOnly }
+func (readOnlyProvider) MoveFile(oldPath, newPath string) error      { return git.ErrReadOnly }
+func (readOnlyProvider) MoveFolder(oldPath, newPath string) error    { return git.ErrReadOnly }
+func (readOnlyProvider) CreateFolder(path string) error              { return git.ErrReadOnly }
+func (readOnlyProvider) DeleteFolder(path string, recursive bool) error {
+	return git.ErrReadOnly
+}
+func (readOnlyProvider) WriteFileIfUnchanged(path string, content []byte, baseHash string) error {
+	return git.ErrReadOnly
+}
+func (readOnlyProvider) DeleteFileIfUnchanged(path, baseHash string) error { return git.ErrReadOnly }
+func (readOnlyProvider) MoveFileIfUnchanged(oldPath, newPath, baseHash string) error {
+	return git.ErrReadOnly
+}
+func (readOnlyProvider) ApplyChangeSet(ops []git.ChangeOp, message string) (string, error) {
+	return "", git.ErrReadOnly
+}
+func (readOnlyProvider) CommitWithOptions(opts git.CommitOptions) (string, error) {
+	return "", git.ErrReadOnly
+}
+func (readOnlyProvider) Push(remote, branch string) error           { return git.ErrReadOnly }
+func (readOnlyProvider) CreateBranch(name, from string) error       { return git.ErrReadOnly }
+func (readOnlyProvider) DeleteBranch(name string, force bool) error { return git.ErrReadOnly }
+
+func TestWriteEndpoints_ReadOnly(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestRepoWithCommit(t, tempDir)
+
+	provider, err := git.NewLocalProvider(tempDir, "")
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	server := New(4242, readOnlyProvider{provider})
+
+	tests := []struct {
+		method string
+		url    string
+		body   string
+	}{
+		{http.MethodPost, "/api/write", `{"path": "a.md", "content": "a"}`},
+		{http.MethodPost, "/api/write", `{"path": "a.md", "content": "a", "baseHash": "abc"}`},
+		{http.MethodPost, "/api/delete", `{"path": "a.md"}`},
+		{http.MethodPost, "/api/move", `{"oldPath": "a.md", "newPath": "b.md"}`},
+		{http.MethodPost, "/api/move-folder", `{"oldPath": "a", "newPath": "b"}`},
+		{http.MethodPost, "/api/create-folder", `{"path": "a"}`},
+		{http.MethodPost, "/api/delete-folder", `{"path": "a"}`},
+		{http.MethodPost, "/api/upload?path=a.png", "data"},
+		{http.MethodPost, "/api/changeset", `{"operations": [{"op": "write", "path": "a.md"}]}`},
+		{http.MethodPost, "/api/commit", `{"message": "msg"}`},
+		{http.MethodPost, "/api/push", `{}`},
+		{http.MethodPost, "/api/branches", `{"name": "feature"}`},
+		{http.MethodDelete, "/api/branches/feature", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.url, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			server.mux.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusForbidden {
+				t.Errorf("expected status 403, got %d: %s", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
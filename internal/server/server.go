@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 
+	"github.com/buckleypaul/giki/internal/drafts"
 	"github.com/buckleypaul/giki/internal/git"
 	"github.com/buckleypaul/giki/ui"
 )
@@ -16,7 +17,11 @@ type Server struct {
 	mux       *http.ServeMux
 	port      int
 	provider  git.GitProvider
-	themesDir string // directory for user theme files; empty = default (~/.config/giki/themes)
+	themesDir string        // directory for user theme files; empty = default (~/.config/giki/themes)
+	watcher   *watcher      // working tree watcher for /api/events; nil if unsupported
+	drafts    *drafts.Store // unsaved edits for /api/drafts; nil if unavailable
+
+	uploadLimit int64 // largest file accepted by /api/upload, in bytes; 0 = default
 }
 
 // New creates a new Server instance.
@@ -27,20 +32,42 @@ func New(port int, provider git.GitProvider) *Server {
 		mux:      mux,
 		port:     port,
 		provider: provider,
+		watcher:  newWatcher(provider),
+		drafts:   newDraftStore(provider),
 	}
 
 	// Mount API handlers
 	mux.HandleFunc("GET /api/tree", s.handleTree)
 	mux.HandleFunc("GET /api/file/", s.handleFile)
 	mux.HandleFunc("GET /api/branches", s.handleBranches)
+	mux.HandleFunc("POST /api/branches", s.handleCreateBranch)
+	mux.HandleFunc("DELETE /api/branches/{name...}", s.handleDeleteBranch)
+	mux.HandleFunc("GET /api/tags", s.handleTags)
 	mux.HandleFunc("GET /api/status", s.handleStatus)
 	mux.HandleFunc("POST /api/write", s.handleWrite)
 	mux.HandleFunc("POST /api/delete", s.handleDelete)
 	mux.HandleFunc("POST /api/move", s.handleMove)
 	mux.HandleFunc("POST /api/move-folder", s.handleMoveFolder)
+	mux.HandleFunc("POST /api/create-folder", s.handleCreateFolder)
+	mux.HandleFunc("POST /api/delete-folder", s.handleDeleteFolder)
+	mux.HandleFunc("POST /api/upload", s.handleUpload)
+	mux.HandleFunc("POST /api/changeset", s.handleChangeSet)
+	mux.HandleFunc("POST /api/merge-text", s.handleMergeText)
+	mux.HandleFunc("GET /api/drafts", s.handleGetDrafts)
+	mux.HandleFunc("PUT /api/drafts", s.handlePutDraft)
+	mux.HandleFunc("DELETE /api/drafts", s.handleDeleteDraft)
 	mux.HandleFunc("POST /api/commit", s.handleCommit)
+	mux.HandleFunc("POST /api/checkout", s.handleCheckout)
+	mux.HandleFunc("POST /api/push", s.handlePush)
+	mux.HandleFunc("POST /api/fetch", s.handleFetch)
+	mux.HandleFunc("POST /api/pull", s.handlePull)
 	mux.HandleFunc("GET /api/search", s.handleSearch)
 	mux.HandleFunc("GET /api/themes", s.handleThemes)
+	mux.HandleFunc("GET /api/log", s.handleLog)
+	mux.HandleFunc("GET /api/blame/", s.handleBlame)
+	mux.HandleFunc("GET /api/diff", s.handleDiff)
+	mux.HandleFunc("GET /api/compare", s.handleCompare)
+	mux.HandleFunc("GET /api/events", s.handleEvents)
 
 	// Check if we're in dev mode
 	devMode := os.Getenv("GIKI_DEV") == "1"
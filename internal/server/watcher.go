@@ -0,0 +1,264 @@
+package server
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/buckleypaul/giki/internal/git"
+)
+
+// Event types pushed to /api/events subscribers.
+const (
+	EventTreeChanged   = "tree-changed"   // files were added or removed
+	EventFileChanged   = "file-changed"   // existing files were modified
+	EventStatusChanged = "status-changed" // repository status (branch, dirty state) changed
+)
+
+const (
+	// defaultWatchInterval is how often the working tree is polled for changes.
+	defaultWatchInterval = 500 * time.Millisecond
+
+	// defaultWatchDebounce is how long the tree must be quiet before changes are reported,
+	// so bursts (editor saves, checkouts) produce a single set of events.
+	defaultWatchDebounce = 300 * time.Millisecond
+)
+
+// WatchEvent is a change notification sent to event stream subscribers.
+type WatchEvent struct {
+	Type   string          `json:"type"`
+	Paths  []string        `json:"paths,omitempty"`  // changed paths for tree-changed and file-changed
+	Status *git.RepoStatus `json:"status,omitempty"` // new status for status-changed
+}
+
+// snapshotter is implemented by providers that can report working tree file stamps,
+// and stamps for the repository metadata (HEAD, index, refs) that commits,
+// pulls, branch switches and stashes change without touching the working tree.
+type snapshotter interface {
+	WorkingTreeSnapshot() (map[string]git.FileStamp, error)
+	RepoStateSnapshot() (map[string]git.FileStamp, error)
+}
+
+// watcher polls the working tree for changes and fans events out to subscribers.
+// Polling only runs while at least one subscriber is connected.
+type watcher struct {
+	provider git.GitProvider
+	source   snapshotter
+	interval time.Duration
+	debounce time.Duration
+
+	mu          sync.Mutex
+	subscribers map[chan WatchEvent]struct{}
+	stop        chan struct{}
+}
+
+// newWatcher creates a watcher for the provider, or returns nil if the provider
+// has no working tree to watch.
+func newWatcher(provider git.GitProvider) *watcher {
+	source, ok := provider.(snapshotter)
+	if !ok {
+		return nil
+	}
+
+	return &watcher{
+		provider:    provider,
+		source:      source,
+		interval:    defaultWatchInterval,
+		debounce:    defaultWatchDebounce,
+		subscribers: make(map[chan WatchEvent]struct{}),
+	}
+}
+
+// subscribe registers a new subscriber and starts polling if it is the first one.
+// The returned function unsubscribes and must be called when the subscriber leaves.
+func (w *watcher) subscribe() (<-chan WatchEvent, func()) {
+	ch := make(chan WatchEvent, 16)
+
+	w.mu.Lock()
+	w.subscribers[ch] = struct{}{}
+	if w.stop == nil {
+		w.stop = make(chan struct{})
+
+		// Take the baseline before returning so changes made right after
+		// subscribing are detected
+		w.startLocked(w.stop)
+	}
+	w.mu.Unlock()
+
+	unsubscribe := func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		if _, ok := w.subscribers[ch]; !ok {
+			return
+		}
+		delete(w.subscribers, ch)
+		if len(w.subscribers) == 0 && w.stop != nil {
+			close(w.stop)
+			w.stop = nil
+		}
+	}
+
+	return ch, unsubscribe
+}
+
+// startLocked captures the baseline state and starts the polling loop.
+// Must be called with w.mu held.
+func (w *watcher) startLocked(stop chan struct{}) {
+	files, err := w.source.WorkingTreeSnapshot()
+	if err != nil {
+		log.Printf("watcher: failed to snapshot working tree: %v", err)
+		files = map[string]git.FileStamp{}
+	}
+
+	state, err := w.source.RepoStateSnapshot()
+	if err != nil {
+		log.Printf("watcher: failed to snapshot repository state: %v", err)
+		state = map[string]git.FileStamp{}
+	}
+
+	status, err := w.provider.Status()
+	if err != nil {
+		log.Printf("watcher: failed to get status: %v", err)
+	}
+
+	go w.run(stop, files, state, status)
+}
+
+// run polls the working tree and repository state until stop is closed.
+// Status is recomputed only when either of them changed, since it is the
+// most expensive check.
+func (w *watcher) run(stop chan struct{}, prev, prevState map[string]git.FileStamp, prevStatus *git.RepoStatus) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	// Changes seen since the last flush
+	treeChanged := make(map[string]struct{})
+	fileChanged := make(map[string]struct{})
+	stateChanged := false
+	var lastChange time.Time
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			current, err := w.source.WorkingTreeSnapshot()
+			if err != nil {
+				log.Printf("watcher: failed to snapshot working tree: %v", err)
+				continue
+			}
+
+			if diffSnapshots(prev, current, treeChanged, fileChanged) {
+				lastChange = now
+			}
+			prev = current
+
+			state, err := w.source.RepoStateSnapshot()
+			if err != nil {
+				log.Printf("watcher: failed to snapshot repository state: %v", err)
+			} else {
+				if !sameSnapshots(prevState, state) {
+					stateChanged = true
+					lastChange = now
+				}
+				prevState = state
+			}
+
+			// Wait for the burst to settle before reporting
+			if len(treeChanged) == 0 && len(fileChanged) == 0 && !stateChanged {
+				continue
+			}
+			if now.Sub(lastChange) < w.debounce {
+				continue
+			}
+
+			if len(treeChanged) > 0 {
+				w.broadcast(WatchEvent{Type: EventTreeChanged, Paths: sortedKeys(treeChanged)})
+			}
+			if len(fileChanged) > 0 {
+				w.broadcast(WatchEvent{Type: EventFileChanged, Paths: sortedKeys(fileChanged)})
+			}
+			treeChanged = make(map[string]struct{})
+			fileChanged = make(map[string]struct{})
+			stateChanged = false
+
+			status, err := w.provider.Status()
+			if err != nil {
+				log.Printf("watcher: failed to get status: %v", err)
+				continue
+			}
+			if prevStatus == nil || *status != *prevStatus {
+				w.broadcast(WatchEvent{Type: EventStatusChanged, Status: status})
+			}
+			prevStatus = status
+		}
+	}
+}
+
+// broadcast sends an event to all subscribers.
+// Subscribers that are not keeping up miss the event rather than blocking the watcher.
+func (w *watcher) broadcast(event WatchEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for ch := range w.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// diffSnapshots records added/removed paths in treeChanged and modified paths in fileChanged.
+// Returns true if any difference was found.
+func diffSnapshots(prev, current map[string]git.FileStamp, treeChanged, fileChanged map[string]struct{}) bool {
+	changed := false
+
+	for path, stamp := range current {
+		prevStamp, ok := prev[path]
+		if !ok {
+			treeChanged[path] = struct{}{}
+			changed = true
+			continue
+		}
+		if !stamp.ModTime.Equal(prevStamp.ModTime) || stamp.Size != prevStamp.Size {
+			fileChanged[path] = struct{}{}
+			changed = true
+		}
+	}
+
+	for path := range prev {
+		if _, ok := current[path]; !ok {
+			treeChanged[path] = struct{}{}
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// sameSnapshots reports whether two snapshots hold the same paths and stamps.
+func sameSnapshots(a, b map[string]git.FileStamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, stamp := range a {
+		other, ok := b[path]
+		if !ok || !stamp.ModTime.Equal(other.ModTime) || stamp.Size != other.Size {
+			return false
+		}
+	}
+	return true
+}
+
+// sortedKeys returns the keys of a set in sorted order.
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}